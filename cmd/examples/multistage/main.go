@@ -24,6 +24,9 @@ import (
 
 	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/decoder"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/replication"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/replication/transport/inprocess"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider/ulid"
 	"github.com/project-alvarium/go-sdk/pkg/annotator"
@@ -111,6 +114,20 @@ func newClient(url string) *api.API {
 	return client
 }
 
+// replicate copies the annotations held by a node's store to a new store for the next node and returns it.
+func replicate(from replication.Contract) replication.Contract {
+	to := memory.New()
+	identities, metadata := decoder.IdentityFactory(), decoder.MetadataFactory()
+	_, err := replication.New(to, identities, metadata).Sync(
+		inprocess.New(replication.New(from, identities, metadata)),
+	)
+	if err != nil {
+		fmt.Println("Unable to replicate annotations")
+		os.Exit(1)
+	}
+	return to
+}
+
 // main is the example entry point.
 func main() {
 	var iotaURL string
@@ -126,7 +143,7 @@ func main() {
 	hashProvider := sha256.New()
 	uniqueProvider := ulid.New()
 	idProvider := identityProvider.New(hashProvider)
-	persistence := memory.New()
+	var persistence replication.Contract = memory.New()
 	passthroughFilter := passthrough.New()
 
 	// create new TPM keys
@@ -172,6 +189,9 @@ func main() {
 	cleanUp()
 	sdkInstance.Close()
 
+	// replicate annotations to the next node; each node has its own store.
+	persistence = replicate(persistence)
+
 	// create SDK instance for annotation and assessment.
 	p = newProvenance("transit-1")
 	sdkInstance = sdk.New(
@@ -207,6 +227,9 @@ func main() {
 	// close SDK instance.
 	sdkInstance.Close()
 
+	// replicate annotations to the next node.
+	persistence = replicate(persistence)
+
 	// create SDK instance for annotation and assessment.
 	p = newProvenance("transit-2")
 	sdkInstance = sdk.New(
@@ -240,6 +263,9 @@ func main() {
 	// close SDK instance.
 	sdkInstance.Close()
 
+	// replicate annotations to the next node.
+	persistence = replicate(persistence)
+
 	// create SDK instance for publishing.
 	p = newProvenance("publisher")
 	w := testwriter.New()
@@ -0,0 +1,118 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/decoder"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
+	jsonEncoding "github.com/project-alvarium/go-sdk/pkg/annotation/encoding/json"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/bolt"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/file"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/relational"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/remote"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+
+	_ "modernc.org/sqlite"
+)
+
+// newEncoding returns the annotation encoding of the given kind.
+func newEncoding(kind string) (encoding.Contract, error) {
+	switch kind {
+	case jsonEncoding.Kind:
+		return jsonEncoding.New(), nil
+	case cbor.Kind:
+		return cbor.New(), nil
+	}
+	return nil, fmt.Errorf("unknown encoding %q", kind)
+}
+
+// open returns the store of the given kind (persisted at path for durable kinds) and a function to close it.
+func open(
+	kind, path string,
+	encoding encoding.Contract,
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract) (store.Contract, func() error, error) {
+
+	switch kind {
+	case "memory":
+		return memory.New(), func() error { return nil }, nil
+	case "file":
+		s, err := file.NewWithEncoding(path, identityFactory, metadataFactory, encoding)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, s.Close, nil
+	case "bolt":
+		s, err := bolt.NewWithEncoding(path, identityFactory, metadataFactory, encoding)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, s.Close, nil
+	case "sqlite":
+		db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+		if err != nil {
+			return nil, nil, err
+		}
+		s, err := relational.New(db, identityFactory, metadataFactory)
+		if err != nil {
+			_ = db.Close()
+			return nil, nil, err
+		}
+		return s, db.Close, nil
+	}
+	return nil, nil, fmt.Errorf("unknown store %q", kind)
+}
+
+// main serves an annotation store over HTTP for use with the remote store client.
+func main() {
+	address := flag.String("address", ":8080", "address to listen on")
+	kind := flag.String("store", "memory", "store to serve (memory, file, bolt, or sqlite)")
+	path := flag.String("path", "annotations.db", "path of the file, bolt, or sqlite store")
+	encodingKind := flag.String("encoding", jsonEncoding.Kind, "encoding of the file or bolt store (json or cbor)")
+	flag.Parse()
+
+	e, err := newEncoding(*encodingKind)
+	if err != nil {
+		fmt.Println("Unable to open store:", err)
+		os.Exit(1)
+	}
+
+	identities := decoder.IdentityFactory()
+	metadata := decoder.MetadataFactory()
+
+	persistence, closer, err := open(*kind, *path, e, identities, metadata)
+	if err != nil {
+		fmt.Println("Unable to open store:", err)
+		os.Exit(1)
+	}
+	defer func(closer func() error) { _ = closer() }(closer)
+
+	fmt.Printf("Serving %s store on %s\n", *kind, *address)
+	err = http.ListenAndServe(*address, remote.NewHandler(persistence, identities, metadata, sha256.New()))
+	if err != nil && err != http.ErrServerClosed {
+		fmt.Println("Server failed:", err)
+	}
+}
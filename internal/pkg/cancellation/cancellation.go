@@ -0,0 +1,43 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// cancellation implements bounding calls that do not accept a context.Context by one.
+package cancellation
+
+import "context"
+
+// Run calls f and returns once f returns or ctx is done, whichever happens first; it returns ctx's error in the
+// latter case.
+//
+// f cannot be interrupted, so it runs in its own goroutine and continues after Run returns early; results f
+// assigns must only be read when Run returns nil.  f is called directly when ctx can never be done.
+func Run(ctx context.Context, f func()) error {
+	if ctx.Done() == nil {
+		f()
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,76 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package cancellation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRun tests Run.
+func TestRun(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "background",
+			test: func(t *testing.T) {
+				called := false
+
+				err := Run(context.Background(), func() { called = true })
+
+				assert.NoError(t, err)
+				assert.True(t, called)
+			},
+		},
+		{
+			name: "completed",
+			test: func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				called := false
+
+				err := Run(ctx, func() { called = true })
+
+				assert.NoError(t, err)
+				assert.True(t, called)
+			},
+		},
+		{
+			name: "cancelled",
+			test: func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				release := make(chan struct{})
+				defer close(release)
+
+				err := Run(ctx, func() {
+					cancel()
+					<-release
+				})
+
+				assert.Equal(t, context.Canceled, err)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
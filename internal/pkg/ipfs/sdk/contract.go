@@ -14,8 +14,13 @@
 
 package sdk
 
+import "context"
+
 // Contract defines a contract used to encapsulate the IPFS SDK and enable unit testing.
 type Contract interface {
 	// Add is called to add annotations to the IPFS instance that resides at url.
 	Add(url string, annotations []byte) (string, error)
+
+	// AddContext is called to add annotations to the IPFS instance that resides at url within ctx.
+	AddContext(ctx context.Context, url string, annotations []byte) (string, error)
 }
@@ -15,9 +15,10 @@
 package ipfs
 
 import (
-	"bytes"
+	"context"
 
 	ipfsAPI "github.com/ipfs/go-ipfs-api"
+	files "github.com/ipfs/go-ipfs-files"
 )
 
 // Instance is a receiver that encapsulates required dependencies.
@@ -29,7 +30,20 @@ func New() *Instance {
 }
 
 // Add is called to add annotations to the IPFS instance that resides at url.
-func (Instance) Add(url string, annotations []byte) (string, error) {
-	shell := ipfsAPI.NewShell(url)
-	return shell.Add(bytes.NewReader(annotations))
+func (i Instance) Add(url string, annotations []byte) (string, error) {
+	return i.AddContext(context.Background(), url, annotations)
+}
+
+// AddContext is called to add annotations to the IPFS instance that resides at url within ctx; the request is
+// abandoned when ctx is done.
+//
+// The request mirrors the shell's Add, which does not accept a context.
+func (Instance) AddContext(ctx context.Context, url string, annotations []byte) (string, error) {
+	directory := files.NewSliceDirectory([]files.DirEntry{files.FileEntry("", files.NewBytesFile(annotations))})
+
+	var out struct {
+		Hash string
+	}
+	err := ipfsAPI.NewShell(url).Request("add").Body(files.NewMultiFileReader(directory, true)).Exec(ctx, &out)
+	return out.Hash, err
 }
@@ -15,9 +15,17 @@
 package ipfs
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/pkg/test"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestNew tests ipfs.New.
@@ -37,3 +45,59 @@ func TestInstance_Add(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "", cid)
 }
+
+// TestInstance_AddContext tests instance.AddContext.
+func TestInstance_AddContext(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "added",
+			test: func(t *testing.T) {
+				hash := test.FactoryRandomString()
+				annotations := test.FactoryRandomByteSlice()
+				var received []byte
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if reader, err := r.MultipartReader(); err == nil {
+						if part, err := reader.NextPart(); err == nil {
+							received, _ = ioutil.ReadAll(part)
+						}
+					}
+					_, _ = w.Write([]byte(`{"Hash":"` + hash + `"}`))
+				}))
+				defer server.Close()
+
+				cid, err := New().AddContext(context.Background(), server.Listener.Addr().String(), annotations)
+
+				require.NoError(t, err)
+				assert.Equal(t, hash, cid)
+				assert.Equal(t, annotations, received)
+			},
+		},
+		{
+			name: "deadline exceeded",
+			test: func(t *testing.T) {
+				release := make(chan struct{})
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					<-release
+				}))
+				defer server.Close()
+				defer close(release)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				cid, err := New().AddContext(ctx, server.Listener.Addr().String(), test.FactoryRandomByteSlice())
+
+				assert.Error(t, err)
+				assert.Equal(t, "", cid)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
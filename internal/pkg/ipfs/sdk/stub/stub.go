@@ -14,6 +14,8 @@
 
 package stub
 
+import "context"
+
 // Instance is a receiver that encapsulates required dependencies.
 type Instance struct {
 	CapturedURL         string
@@ -36,3 +38,11 @@ func (i *Instance) Add(url string, annotations []byte) (string, error) {
 	i.CapturedAnnotations = annotations
 	return i.resultValue, i.resultError
 }
+
+// AddContext is called to add annotations to the IPFS instance that resides at url within ctx.
+func (i *Instance) AddContext(ctx context.Context, url string, annotations []byte) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return i.Add(url, annotations)
+}
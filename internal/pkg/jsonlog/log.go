@@ -0,0 +1,94 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// jsonlog implements an append-only log of JSON values, one per line, used to persist decorator state.
+package jsonlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// Log is an append-only log of JSON values.
+type Log struct {
+	file *os.File
+	size int64
+}
+
+// Open opens (or creates) the log at path and calls decode with each value it holds in order.
+//
+// A final value without a trailing newline was torn by an interrupted write and is truncated; any value decode
+// rejects is returned as an error and the log is left untouched.
+func Open(path string, decode func(data []byte) error) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Log{file: f}
+	if err := l.load(decode); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// load reads the log, calls decode with each complete value, and truncates a torn final value.
+func (l *Log) load(decode func(data []byte) error) error {
+	data, err := ioutil.ReadAll(l.file)
+	if err != nil {
+		return err
+	}
+
+	for n := 1; len(data) > 0; n++ {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			return l.file.Truncate(l.size)
+		}
+		if err := decode(data[:end]); err != nil {
+			return fmt.Errorf("line %d: %v", n, err)
+		}
+		l.size += int64(end + 1)
+		data = data[end+1:]
+	}
+	return nil
+}
+
+// Append writes v to the end of the log and syncs it; a failed write is truncated.
+func (l *Log) Append(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if _, err := l.file.WriteAt(data, l.size); err != nil {
+		_ = l.file.Truncate(l.size)
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		_ = l.file.Truncate(l.size)
+		return err
+	}
+	l.size += int64(len(data))
+	return nil
+}
+
+// Close closes the log.
+func (l *Log) Close() error {
+	return l.file.Close()
+}
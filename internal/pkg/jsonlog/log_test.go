@@ -0,0 +1,120 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package jsonlog
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPath returns the path of a log in a new temporary directory and a function that removes it.
+func newPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "jsonlog")
+	require.NoError(t, err)
+	return filepath.Join(dir, "log.json"), func() { _ = os.RemoveAll(dir) }
+}
+
+// collect returns a decode function appending each value to values.
+func collect(values *[]int) func(data []byte) error {
+	return func(data []byte) error {
+		var v int
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*values = append(*values, v)
+		return nil
+	}
+}
+
+// TestOpen tests Open.
+func TestOpen(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "appended values reloaded",
+			test: func(t *testing.T) {
+				path, cleanup := newPath(t)
+				defer cleanup()
+				sut, err := Open(path, collect(new([]int)))
+				require.NoError(t, err)
+				require.NoError(t, sut.Append(1))
+				require.NoError(t, sut.Append(2))
+				require.NoError(t, sut.Close())
+
+				values := make([]int, 0)
+				sut, err = Open(path, collect(&values))
+
+				require.NoError(t, err)
+				defer func() { _ = sut.Close() }()
+				assert.Equal(t, []int{1, 2}, values)
+			},
+		},
+		{
+			name: "torn value truncated",
+			test: func(t *testing.T) {
+				path, cleanup := newPath(t)
+				defer cleanup()
+				require.NoError(t, ioutil.WriteFile(path, []byte("1\n2"), 0600))
+
+				values := make([]int, 0)
+				sut, err := Open(path, collect(&values))
+
+				require.NoError(t, err)
+				assert.Equal(t, []int{1}, values)
+				require.NoError(t, sut.Append(3))
+				require.NoError(t, sut.Close())
+				data, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t, "1\n3\n", string(data))
+			},
+		},
+		{
+			name: "rejected value",
+			test: func(t *testing.T) {
+				path, cleanup := newPath(t)
+				defer cleanup()
+				require.NoError(t, ioutil.WriteFile(path, []byte("1\n2\n3\n"), 0600))
+
+				sut, err := Open(path, func(data []byte) error {
+					if string(data) == "2" {
+						return errors.New("rejected")
+					}
+					return nil
+				})
+
+				assert.Nil(t, sut)
+				assert.EqualError(t, err, "line 2: rejected")
+				data, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t, "1\n2\n3\n", string(data))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
@@ -41,6 +41,7 @@ func Assert(t *testing.T, expected []*annotation.Instance, id identity.Contract,
 	for i := range expected {
 		assert.Equal(t, expected[i].CurrentIdentity, actual[i].CurrentIdentity)
 		assert.Equal(t, expected[i].PreviousIdentity, actual[i].PreviousIdentity)
+		assert.Equal(t, expected[i].PreviousIdentities, actual[i].PreviousIdentities)
 
 		expectedCreated := datetime.TimeFromCreated(expected[i].Created)
 		actualCreated := datetime.TimeFromCreated(actual[i].Created)
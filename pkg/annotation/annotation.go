@@ -19,14 +19,32 @@ import (
 	"errors"
 
 	"github.com/project-alvarium/go-sdk/internal/pkg/datetime"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	opaqueMetadata "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/registry"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/schema"
+	"github.com/project-alvarium/go-sdk/pkg/canonical"
 	"github.com/project-alvarium/go-sdk/pkg/identity"
 	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
 )
 
+// Signature is an author signature over an annotation's signed data (see Instance.SignedData).
+type Signature struct {
+	Value          []byte            `json:"value"`
+	PublicKey      []byte            `json:"publicKey"`
+	SignerKind     string            `json:"signerType"`
+	SignerMetadata metadata.Contract `json:"signerMetadata"`
+}
+
 // Instance is the standard metadata returned by all Annotate() methods.
+//
+// SchemaVersion and MetadataSchemaVersion record the schema versions (see schema.Current) of the annotation envelope
+// and of its metadata payload; they are zero only for annotations written before schemas were versioned.
 type Instance struct {
+	SchemaVersion        int               `json:"schemaVersion,omitempty"`
 	Unique               string            `json:"unique"`
 	Created              string            `json:"created"`
 	CurrentIdentityKind  string            `json:"identityCurrentType"`
@@ -36,8 +54,21 @@ type Instance struct {
 	MetadataKind         string            `json:"metadataType"`
 	Metadata             metadata.Contract `json:"metadata"`
 
-	identityFactory identityFactory.Contract
-	metadataFactory metadataFactory.Contract
+	MetadataSchemaVersion int `json:"metadataSchemaVersion,omitempty"`
+
+	PreviousIdentitiesKinds []string            `json:"identitiesPreviousType,omitempty"`
+	PreviousIdentities      []identity.Contract `json:"identitiesPrevious,omitempty"`
+
+	AuthorKind string            `json:"authorType,omitempty"`
+	Author     identity.Contract `json:"author,omitempty"`
+	OwnerKind  string            `json:"ownerType,omitempty"`
+	Owner      identity.Contract `json:"owner,omitempty"`
+
+	Signature *Signature `json:"signature,omitempty"`
+
+	identityFactory       identityFactory.Contract
+	metadataFactory       metadataFactory.Contract
+	signerMetadataFactory metadataFactory.Contract
 }
 
 // New is a factory function that returns an initialized Instance.
@@ -54,15 +85,78 @@ func New(
 	}
 
 	return &Instance{
-		Unique:               unique,
-		Created:              datetime.Created(),
-		CurrentIdentityKind:  currentIdentityKind,
-		CurrentIdentity:      currentIdentity,
-		PreviousIdentityKind: previousIdentityKind,
-		PreviousIdentity:     previousIdentity,
-		MetadataKind:         metadata.Kind(),
-		Metadata:             metadata,
+		SchemaVersion:         schema.Current(schema.Annotation),
+		Unique:                unique,
+		Created:               datetime.Created(),
+		CurrentIdentityKind:   currentIdentityKind,
+		CurrentIdentity:       currentIdentity,
+		PreviousIdentityKind:  previousIdentityKind,
+		PreviousIdentity:      previousIdentity,
+		MetadataKind:          metadata.Kind(),
+		Metadata:              metadata,
+		MetadataSchemaVersion: schema.Current(metadata.Kind()),
+	}
+}
+
+// NewDerived is a factory function that returns an initialized Instance for data derived from multiple previous
+// identities; PreviousIdentity is set to the first previous identity for consumers unaware of PreviousIdentities.
+func NewDerived(
+	unique string,
+	currentIdentity identity.Contract,
+	previousIdentities []identity.Contract,
+	metadata metadata.Contract) *Instance {
+
+	var previousIdentity identity.Contract = nil
+	if len(previousIdentities) > 0 {
+		previousIdentity = previousIdentities[0]
+	}
+
+	i := New(unique, currentIdentity, previousIdentity, metadata)
+	if len(previousIdentities) > 0 {
+		i.PreviousIdentitiesKinds = make([]string, len(previousIdentities))
+		for p := range previousIdentities {
+			i.PreviousIdentitiesKinds[p] = previousIdentities[p].Kind()
+		}
+		i.PreviousIdentities = previousIdentities
 	}
+	return i
+}
+
+// Predecessors returns the annotation's previous identities (PreviousIdentities if set, otherwise PreviousIdentity).
+func (i *Instance) Predecessors() []identity.Contract {
+	if len(i.PreviousIdentities) > 0 {
+		return i.PreviousIdentities
+	}
+	if i.PreviousIdentity != nil {
+		return []identity.Contract{i.PreviousIdentity}
+	}
+	return nil
+}
+
+// SetAuthor records the identity of the entity that recorded the annotation.
+func (i *Instance) SetAuthor(author identity.Contract) {
+	i.Author = author
+	i.AuthorKind = ""
+	if author != nil {
+		i.AuthorKind = author.Kind()
+	}
+}
+
+// SetOwner records the identity of the entity responsible for (and the source of truth for) the annotated data.
+func (i *Instance) SetOwner(owner identity.Contract) {
+	i.Owner = owner
+	i.OwnerKind = ""
+	if owner != nil {
+		i.OwnerKind = owner.Kind()
+	}
+}
+
+// SignedData returns the annotation's canonical JSON encoding (see canonical.Marshal) without its signature, which
+// is the data a signature covers, or an error if the annotation cannot be encoded.
+func (i *Instance) SignedData() ([]byte, error) {
+	unsigned := *i
+	unsigned.Signature = nil
+	return canonical.Marshal(&unsigned)
 }
 
 // SetIdentityFactory provides for method injection of required factory to unmarshal identity JSON.
@@ -75,7 +169,45 @@ func (i *Instance) SetMetadataFactory(metadataFactory metadataFactory.Contract)
 	i.metadataFactory = metadataFactory
 }
 
+// SetSignerMetadataFactory provides for method injection of the factory used to unmarshal signature signer metadata
+// JSON; if it is not set, the signer kinds registered in the process-wide registry are used.
+func (i *Instance) SetSignerMetadataFactory(signerMetadataFactory metadataFactory.Contract) {
+	i.signerMetadataFactory = signerMetadataFactory
+}
+
+// createIdentity returns the identity the identity factory creates from data; an identity of a kind the factory
+// does not recognize is returned as an opaque identity retaining data, and null data is returned as nil.
+func (i *Instance) createIdentity(kind string, data json.RawMessage) identity.Contract {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+
+	if result := i.identityFactory.Create(kind, data); result != nil {
+		return result
+	}
+	return opaqueIdentity.New(kind, data)
+}
+
+// createMetadata returns the metadata the metadata factory creates from data; metadata of a kind the factory does
+// not recognize is returned as opaque metadata retaining data, and null data is returned as nil.
+func (i *Instance) createMetadata(kind string, data json.RawMessage) metadata.Contract {
+	return opaqueMetadata.Create([]metadataFactory.Contract{i.metadataFactory}, kind, data)
+}
+
+// createSignerMetadata returns the signer metadata the signer metadata factory creates from data; signer kinds are
+// distinct from annotation metadata kinds, so they are never decoded with the metadata factory.
+func (i *Instance) createSignerMetadata(kind string, data json.RawMessage) metadata.Contract {
+	f := i.signerMetadataFactory
+	if f == nil {
+		f = registry.NewMetadataFactory(registry.Signer)
+	}
+	return opaqueMetadata.Create([]metadataFactory.Contract{f}, kind, data)
+}
+
 // UnmarshalJSON converts JSON into appropriate contract implementations.
+//
+// JSON written at an earlier schema version is upgraded (see schema.Upgrade) before it is converted; the envelope
+// is upgraded first, followed by its metadata payload.
 func (i *Instance) UnmarshalJSON(data []byte) error {
 	if i.identityFactory == nil {
 		return errors.New("identityFactory not set")
@@ -84,6 +216,17 @@ func (i *Instance) UnmarshalJSON(data []byte) error {
 		return errors.New("metadataFactory not set")
 	}
 
+	var version struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(data, &version); err != nil {
+		return err
+	}
+	data, schemaVersion, err := schema.Upgrade(schema.Annotation, version.SchemaVersion, data)
+	if err != nil {
+		return err
+	}
+
 	type instance struct {
 		Unique               string          `json:"unique"`
 		Created              string          `json:"created"`
@@ -93,21 +236,86 @@ func (i *Instance) UnmarshalJSON(data []byte) error {
 		PreviousIdentity     json.RawMessage `json:"identityPrevious"`
 		MetadataKind         string          `json:"metadataType"`
 		Metadata             json.RawMessage `json:"metadata"`
+
+		MetadataSchemaVersion int `json:"metadataSchemaVersion"`
+
+		PreviousIdentitiesKinds []string          `json:"identitiesPreviousType"`
+		PreviousIdentities      []json.RawMessage `json:"identitiesPrevious"`
+
+		AuthorKind string          `json:"authorType"`
+		Author     json.RawMessage `json:"author"`
+		OwnerKind  string          `json:"ownerType"`
+		Owner      json.RawMessage `json:"owner"`
+
+		Signature *struct {
+			Value          []byte          `json:"value"`
+			PublicKey      []byte          `json:"publicKey"`
+			SignerKind     string          `json:"signerType"`
+			SignerMetadata json.RawMessage `json:"signerMetadata"`
+		} `json:"signature"`
 	}
 
 	var value instance
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
+	metadataData, metadataSchemaVersion, err := schema.Upgrade(
+		value.MetadataKind,
+		value.MetadataSchemaVersion,
+		value.Metadata,
+	)
+	if err != nil {
+		return err
+	}
 
+	i.SchemaVersion = schemaVersion
 	i.Unique = value.Unique
 	i.Created = value.Created
 	i.CurrentIdentityKind = value.CurrentIdentityKind
-	i.CurrentIdentity = i.identityFactory.Create(value.CurrentIdentityKind, value.CurrentIdentity)
+	i.CurrentIdentity = i.createIdentity(value.CurrentIdentityKind, value.CurrentIdentity)
 	i.PreviousIdentityKind = value.PreviousIdentityKind
-	i.PreviousIdentity = i.identityFactory.Create(value.PreviousIdentityKind, value.PreviousIdentity)
+	i.PreviousIdentity = i.createIdentity(value.PreviousIdentityKind, value.PreviousIdentity)
 	i.MetadataKind = value.MetadataKind
-	i.Metadata = i.metadataFactory.Create(value.MetadataKind, value.Metadata)
+	i.Metadata = i.createMetadata(value.MetadataKind, metadataData)
+	i.MetadataSchemaVersion = metadataSchemaVersion
+	i.PreviousIdentitiesKinds = nil
+	i.PreviousIdentities = nil
+	if len(value.PreviousIdentities) > 0 {
+		if len(value.PreviousIdentitiesKinds) != len(value.PreviousIdentities) {
+			return errors.New("identitiesPreviousType does not match identitiesPrevious")
+		}
+		i.PreviousIdentitiesKinds = value.PreviousIdentitiesKinds
+		i.PreviousIdentities = make([]identity.Contract, len(value.PreviousIdentities))
+		for p := range value.PreviousIdentities {
+			i.PreviousIdentities[p] = i.createIdentity(value.PreviousIdentitiesKinds[p], value.PreviousIdentities[p])
+		}
+	}
+	i.AuthorKind = value.AuthorKind
+	i.Author = i.createIdentity(value.AuthorKind, value.Author)
+	i.OwnerKind = value.OwnerKind
+	i.Owner = i.createIdentity(value.OwnerKind, value.Owner)
+	i.Signature = nil
+	if value.Signature != nil {
+		i.Signature = &Signature{
+			Value:          value.Signature.Value,
+			PublicKey:      value.Signature.PublicKey,
+			SignerKind:     value.Signature.SignerKind,
+			SignerMetadata: i.createSignerMetadata(value.Signature.SignerKind, value.Signature.SignerMetadata),
+		}
+	}
 
 	return nil
 }
+
+// UnmarshalCBOR converts CBOR into appropriate contract implementations.
+//
+// The CBOR data item is transcoded to JSON and converted by UnmarshalJSON so both encodings share the same
+// factory-driven decoding and schema upgrades.  CBOR therefore only reduces the size of stored and published
+// annotations; decoding it costs the transcoding on top of decoding the equivalent JSON.
+func (i *Instance) UnmarshalCBOR(data []byte) error {
+	transcoded, err := cbor.ToJSON(data)
+	if err != nil {
+		return err
+	}
+	return i.UnmarshalJSON(transcoded)
+}
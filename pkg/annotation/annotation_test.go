@@ -0,0 +1,448 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package annotation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	metadataStubFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/schema"
+	"github.com/project-alvarium/go-sdk/pkg/canonical"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/identity/principal"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIdentity returns a new random identity.
+func newIdentity() identity.Contract {
+	return identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+}
+
+// TestInstance_Predecessors tests Instance.Predecessors.
+func TestInstance_Predecessors(t *testing.T) {
+	id1, id2, id3 := newIdentity(), newIdentity(), newIdentity()
+
+	type testCase struct {
+		name     string
+		sut      *Instance
+		expected []identity.Contract
+	}
+
+	cases := []testCase{
+		{
+			name:     "none",
+			sut:      New(test.FactoryRandomString(), id1, nil, metadataStub.NewNullObject()),
+			expected: nil,
+		},
+		{
+			name:     "previous identity",
+			sut:      New(test.FactoryRandomString(), id1, id2, metadataStub.NewNullObject()),
+			expected: []identity.Contract{id2},
+		},
+		{
+			name:     "previous identities",
+			sut:      NewDerived(test.FactoryRandomString(), id1, []identity.Contract{id2, id3}, metadataStub.NewNullObject()),
+			expected: []identity.Contract{id2, id3},
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				assert.Equal(t, cases[i].expected, cases[i].sut.Predecessors())
+			},
+		)
+	}
+}
+
+// TestInstance_UnmarshalJSON tests Instance.UnmarshalJSON.
+func TestInstance_UnmarshalJSON(t *testing.T) {
+	id1, id2, id3 := newIdentity(), newIdentity(), newIdentity()
+	m := metadataStub.NewNullObject()
+
+	type testCase struct {
+		name string
+		m    *Instance
+	}
+
+	cases := []testCase{
+		{
+			name: "single predecessor",
+			m:    New(test.FactoryRandomString(), id1, id2, m),
+		},
+		{
+			name: "multiple predecessors",
+			m:    NewDerived(test.FactoryRandomString(), id1, []identity.Contract{id2, id3}, m),
+		},
+		func() testCase {
+			signed := New(test.FactoryRandomString(), id1, id2, m)
+			signed.Signature = &Signature{
+				Value:          test.FactoryRandomByteSlice(),
+				PublicKey:      test.FactoryRandomByteSlice(),
+				SignerKind:     m.Kind(),
+				SignerMetadata: m,
+			}
+			return testCase{
+				name: "signed",
+				m:    signed,
+			}
+		}(),
+		func() testCase {
+			attributed := New(test.FactoryRandomString(), id1, nil, m)
+			attributed.SetAuthor(principal.New(test.FactoryRandomString()))
+			attributed.SetOwner(principal.New(test.FactoryRandomString()))
+			return testCase{
+				name: "author and owner",
+				m:    attributed,
+			}
+		}(),
+		func() testCase {
+			unversioned := New(test.FactoryRandomString(), id1, id2, m)
+			unversioned.SchemaVersion = 0
+			unversioned.MetadataSchemaVersion = 0
+			return testCase{
+				name: "unversioned",
+				m:    unversioned,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				data, err := json.Marshal(cases[i].m)
+				require.NoError(t, err)
+
+				var sut Instance
+				sut.SetIdentityFactory(identityFactory.New())
+				sut.SetMetadataFactory(
+					metadataFactory.New([]metadataFactory.Contract{metadataStubFactory.New(m)}),
+				)
+				err = json.Unmarshal(data, &sut)
+
+				assert.NoError(t, err)
+				assert.Equal(t, cases[i].m.Predecessors(), sut.Predecessors())
+				roundTrip, err := json.Marshal(&sut)
+				assert.NoError(t, err)
+				assert.Equal(t, string(data), string(roundTrip))
+			},
+		)
+	}
+}
+
+// TestInstance_UnmarshalJSON_Upgrade tests Instance.UnmarshalJSON upgrades JSON written at earlier schema versions.
+func TestInstance_UnmarshalJSON_Upgrade(t *testing.T) {
+	m := metadataStub.New(test.FactoryRandomString(), test.FactoryRandomString())
+	require.NoError(
+		t,
+		schema.Register(m.Kind(), schema.Initial, func(data json.RawMessage) (json.RawMessage, error) {
+			var value struct {
+				Old interface{} `json:"old"`
+			}
+			if err := json.Unmarshal(data, &value); err != nil {
+				return nil, err
+			}
+			return json.Marshal(map[string]interface{}{"value": value.Old})
+		}),
+	)
+
+	// unmarshal returns an Instance unmarshalled from the JSON encoding of legacy with its metadata replaced by
+	// metadata.
+	unmarshal := func(t *testing.T, legacy *Instance, metadata string) (*Instance, error) {
+		data, err := json.Marshal(legacy)
+		require.NoError(t, err)
+		var value map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(data, &value))
+		value["metadata"] = json.RawMessage(metadata)
+		data, err = json.Marshal(value)
+		require.NoError(t, err)
+
+		var sut Instance
+		sut.SetIdentityFactory(identityFactory.New())
+		sut.SetMetadataFactory(metadataFactory.New([]metadataFactory.Contract{metadataStubFactory.New(m)}))
+		return &sut, json.Unmarshal(data, &sut)
+	}
+
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "current version stamped",
+			test: func(t *testing.T) {
+				sut := New(test.FactoryRandomString(), newIdentity(), nil, m)
+
+				assert.Equal(t, schema.Current(schema.Annotation), sut.SchemaVersion)
+				assert.Equal(t, 2, sut.MetadataSchemaVersion)
+			},
+		},
+		{
+			name: "metadata upgraded",
+			test: func(t *testing.T) {
+				legacy := New(test.FactoryRandomString(), newIdentity(), nil, m)
+				legacy.MetadataSchemaVersion = schema.Initial
+				value := test.FactoryRandomString()
+
+				sut, err := unmarshal(t, legacy, `{"old":"`+value+`"}`)
+
+				assert.NoError(t, err)
+				assert.Equal(t, value, sut.Metadata.(*metadataStub.Instance).Value)
+				assert.Equal(t, 2, sut.MetadataSchemaVersion)
+				assert.Equal(t, legacy.SchemaVersion, sut.SchemaVersion)
+			},
+		},
+		{
+			name: "unversioned metadata upgraded",
+			test: func(t *testing.T) {
+				legacy := New(test.FactoryRandomString(), newIdentity(), nil, m)
+				legacy.SchemaVersion = 0
+				legacy.MetadataSchemaVersion = 0
+				value := test.FactoryRandomString()
+
+				sut, err := unmarshal(t, legacy, `{"old":"`+value+`"}`)
+
+				assert.NoError(t, err)
+				assert.Equal(t, value, sut.Metadata.(*metadataStub.Instance).Value)
+				assert.Equal(t, 2, sut.MetadataSchemaVersion)
+				assert.Equal(t, 0, sut.SchemaVersion)
+			},
+		},
+		{
+			name: "newer metadata version",
+			test: func(t *testing.T) {
+				legacy := New(test.FactoryRandomString(), newIdentity(), nil, m)
+				legacy.MetadataSchemaVersion = 3
+
+				sut, err := unmarshal(t, legacy, `{"value":null}`)
+
+				assert.NoError(t, err)
+				assert.Equal(t, 3, sut.MetadataSchemaVersion)
+				assert.Equal(t, legacy.SchemaVersion, sut.SchemaVersion)
+				assert.Equal(t, legacy.Unique, sut.Unique)
+			},
+		},
+		{
+			name: "newer annotation version",
+			test: func(t *testing.T) {
+				legacy := New(test.FactoryRandomString(), newIdentity(), nil, m)
+				legacy.SchemaVersion = schema.Current(schema.Annotation) + 1
+
+				sut, err := unmarshal(t, legacy, `{"value":null}`)
+
+				assert.NoError(t, err)
+				assert.Equal(t, legacy.SchemaVersion, sut.SchemaVersion)
+				assert.Equal(t, 2, sut.MetadataSchemaVersion)
+				assert.Equal(t, legacy.Unique, sut.Unique)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_UnmarshalJSON_Unrecognized tests Instance.UnmarshalJSON retains identities and metadata of kinds the
+// factories do not recognize so that the annotation re-marshals to the same bytes.
+func TestInstance_UnmarshalJSON_Unrecognized(t *testing.T) {
+	kind := test.FactoryRandomString()
+	data := `{"unique":"` + test.FactoryRandomString() + `","created":"2020-06-01T00:00:00Z",` +
+		`"identityCurrentType":"` + kind + `","identityCurrent":{"z":"` + test.FactoryRandomString() + `","a":1.50},` +
+		`"identityPreviousType":"` + identityHash.Kind + `","identityPrevious":{"hash":"YWI="},` +
+		`"metadataType":"` + kind + `","metadata":{"z":[true,null],"a":{"\u003c":"` + test.FactoryRandomString() + `"}},` +
+		`"authorType":"` + kind + `","author":"` + test.FactoryRandomString() + `",` +
+		`"signature":{"value":"YWI=","publicKey":"Y2Q=","signerType":"` + kind + `","signerMetadata":{"z":0,"a":[]}}}`
+
+	type testCase struct {
+		name   string
+		decode func(t *testing.T, sut *Instance)
+	}
+
+	cases := []testCase{
+		{
+			name: "json",
+			decode: func(t *testing.T, sut *Instance) {
+				require.NoError(t, json.Unmarshal([]byte(data), sut))
+			},
+		},
+		{
+			name: "cbor",
+			decode: func(t *testing.T, sut *Instance) {
+				var decoded Instance
+				decoded.SetIdentityFactory(identityFactory.New())
+				decoded.SetMetadataFactory(metadataFactory.New([]metadataFactory.Contract{}))
+				require.NoError(t, json.Unmarshal([]byte(data), &decoded))
+				encoded, err := cbor.Marshal(&decoded)
+				require.NoError(t, err)
+				require.NoError(t, cbor.Unmarshal(encoded, sut))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				var sut Instance
+				sut.SetIdentityFactory(identityFactory.New())
+				sut.SetMetadataFactory(metadataFactory.New([]metadataFactory.Contract{}))
+
+				cases[i].decode(t, &sut)
+
+				assert.Equal(t, kind, sut.CurrentIdentity.(*opaqueIdentity.Identity).Kind())
+				assert.IsType(t, &identityHash.Identity{}, sut.PreviousIdentity)
+				assert.Equal(t, kind, sut.Metadata.Kind())
+				assert.Equal(t, kind, sut.Signature.SignerMetadata.Kind())
+				result, err := json.Marshal(&sut)
+				require.NoError(t, err)
+				assert.Equal(t, data, string(result))
+			},
+		)
+	}
+}
+
+// TestInstance_SignedData tests Instance.SignedData.
+func TestInstance_SignedData(t *testing.T) {
+	m := New(test.FactoryRandomString(), newIdentity(), nil, metadataStub.NewNullObject())
+	unsigned, err := m.SignedData()
+	require.NoError(t, err)
+
+	m.Signature = &Signature{Value: test.FactoryRandomByteSlice(), PublicKey: test.FactoryRandomByteSlice()}
+
+	signed, err := m.SignedData()
+	require.NoError(t, err)
+	assert.Equal(t, string(unsigned), string(signed))
+	assert.NotNil(t, m.Signature)
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.NotEqual(t, string(unsigned), string(data))
+	m.Unique = test.FactoryRandomString()
+	changed, err := m.SignedData()
+	require.NoError(t, err)
+	assert.NotEqual(t, string(unsigned), string(changed))
+	canonicalForm, err := canonical.Transform(changed)
+	require.NoError(t, err)
+	assert.Equal(t, string(canonicalForm), string(changed))
+
+	m.Metadata = metadataStub.New(test.FactoryRandomString(), make(chan int))
+	_, err = m.SignedData()
+	assert.Error(t, err)
+}
+
+// TestInstance_SetOwner tests Instance.SetAuthor and Instance.SetOwner.
+func TestInstance_SetOwner(t *testing.T) {
+	author, owner := principal.New(test.FactoryRandomString()), newIdentity()
+	sut := New(test.FactoryRandomString(), newIdentity(), nil, metadataStub.NewNullObject())
+
+	sut.SetAuthor(author)
+	sut.SetOwner(owner)
+
+	assert.Equal(t, principal.Kind, sut.AuthorKind)
+	assert.Equal(t, author, sut.Author)
+	assert.Equal(t, identityHash.Kind, sut.OwnerKind)
+	assert.Equal(t, owner, sut.Owner)
+
+	sut.SetOwner(nil)
+
+	assert.Equal(t, "", sut.OwnerKind)
+	assert.Nil(t, sut.Owner)
+	data, err := json.Marshal(sut)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"owner`)
+}
+
+// TestInstance_UnmarshalCBOR tests Instance.UnmarshalCBOR.
+func TestInstance_UnmarshalCBOR(t *testing.T) {
+	id1, id2, id3 := newIdentity(), newIdentity(), newIdentity()
+	m := metadataStub.New(test.FactoryRandomString(), test.FactoryRandomString())
+
+	type testCase struct {
+		name string
+		m    *Instance
+	}
+
+	cases := []testCase{
+		{
+			name: "single predecessor",
+			m:    New(test.FactoryRandomString(), id1, id2, m),
+		},
+		{
+			name: "multiple predecessors",
+			m:    NewDerived(test.FactoryRandomString(), id1, []identity.Contract{id2, id3}, m),
+		},
+		func() testCase {
+			signed := New(test.FactoryRandomString(), id1, nil, m)
+			signed.SetAuthor(principal.New(test.FactoryRandomString()))
+			signed.SetOwner(principal.New(test.FactoryRandomString()))
+			signed.Signature = &Signature{
+				Value:          test.FactoryRandomByteSlice(),
+				PublicKey:      test.FactoryRandomByteSlice(),
+				SignerKind:     m.Kind(),
+				SignerMetadata: m,
+			}
+			return testCase{
+				name: "signed with author and owner",
+				m:    signed,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				data, err := cbor.Marshal(cases[i].m)
+				require.NoError(t, err)
+
+				var sut Instance
+				sut.SetIdentityFactory(identityFactory.New())
+				sut.SetMetadataFactory(
+					metadataFactory.New([]metadataFactory.Contract{metadataStubFactory.New(m)}),
+				)
+				err = cbor.Unmarshal(data, &sut)
+
+				assert.NoError(t, err)
+				assert.Equal(t, cases[i].m.Predecessors(), sut.Predecessors())
+				expected, err := json.Marshal(cases[i].m)
+				require.NoError(t, err)
+				actual, err := json.Marshal(&sut)
+				require.NoError(t, err)
+				assert.Equal(t, string(expected), string(actual))
+			},
+		)
+	}
+
+	t.Run("factories not set", func(t *testing.T) {
+		data, err := cbor.Marshal(cases[0].m)
+		require.NoError(t, err)
+
+		var sut Instance
+		assert.Error(t, cbor.Unmarshal(data, &sut))
+	})
+}
@@ -0,0 +1,353 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// archive implements export and import of store annotations in a portable, self-describing archive format.
+//
+// An archive is newline-delimited JSON: a header line, one line per annotation (with the identity it is stored
+// against), and a trailing manifest line that records the number of annotations and a digest of their lines so
+// truncation or alteration is detected on import.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/datetime"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+const (
+	// Format identifies the archive format in its header.
+	Format = "alvarium-annotation-archive"
+
+	// Version is the archive format version written by Export; version 2 records the identity each annotation is
+	// stored against.
+	Version = 2
+
+	headerKind     = "header"
+	annotationKind = "annotation"
+	manifestKind   = "manifest"
+)
+
+// Header describes an archive.
+type Header struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	Created string `json:"created"`
+}
+
+// Manifest summarizes an archive's content.
+type Manifest struct {
+	Count      int    `json:"count"`
+	DigestKind string `json:"digestType"`
+	Digest     []byte `json:"digest"`
+}
+
+// line defines the structure of a single archive line; IdentityKind and Identity hold the kind and JSON of the
+// identity an annotation is stored against.
+type line struct {
+	Kind         string          `json:"type"`
+	Header       *Header         `json:"header,omitempty"`
+	IdentityKind string          `json:"identityType,omitempty"`
+	Identity     json.RawMessage `json:"identity,omitempty"`
+	Annotation   json.RawMessage `json:"annotation,omitempty"`
+	Manifest     *Manifest       `json:"manifest,omitempty"`
+}
+
+// entry is an annotation and the identity it is stored against.
+type entry struct {
+	id identity.Contract
+	m  *annotation.Instance
+}
+
+// digest returns the digest of the given annotation lines (without their trailing newlines).
+func digest(hashProvider hashprovider.Contract, lines [][]byte) []byte {
+	var b []byte
+	for i := range lines {
+		b = append(b, lines[i]...)
+		b = append(b, '\n')
+	}
+	return hashProvider.Derive(b)
+}
+
+// fetcher returns a lineage.Fetch that reads the annotations stored directly against an identity from s (using
+// query.Contract if s implements it) and records the identity each is stored against in storedAgainst.
+func fetcher(s store.Contract, storedAgainst map[string]identity.Contract) lineage.Fetch {
+	return func(id identity.Contract) ([]*annotation.Instance, status.Value) {
+		var annotations []*annotation.Instance
+		if q, ok := s.(query.Contract); ok {
+			var result status.Value
+			if annotations, result = query.All(q, query.Criteria{Identity: id}); result != status.Success {
+				return nil, result
+			}
+		} else {
+			node, result := s.FindDescendants(id)
+			if result != status.Success {
+				return nil, result
+			}
+			annotations = node.Annotations
+		}
+
+		for a := range annotations {
+			if _, exists := storedAgainst[annotations[a].Unique]; !exists {
+				storedAgainst[annotations[a].Unique] = id
+			}
+		}
+		return annotations, status.Success
+	}
+}
+
+// collect returns the unique annotations for the given identities (and their lineage) with the identities they are
+// stored against; if no identities are given and s implements query.Contract, all annotations in s are returned.
+func collect(s store.Contract, identities []identity.Contract) ([]entry, error) {
+	if len(identities) == 0 {
+		q, ok := s.(query.Contract)
+		if !ok {
+			return nil, errors.New("store does not support enumeration; identities required")
+		}
+		annotations, result := query.All(q, query.Criteria{})
+		if result != status.Success {
+			return nil, fmt.Errorf("query returned %d", result)
+		}
+		storedAgainst, result := query.Locate(q, annotations)
+		if result != status.Success {
+			return nil, fmt.Errorf("locating stored identities returned %d", result)
+		}
+
+		entries := make([]entry, len(annotations))
+		for a := range annotations {
+			entries[a] = entry{id: storedAgainst[annotations[a].Unique], m: annotations[a]}
+		}
+		return entries, nil
+	}
+
+	entries := make([]entry, 0)
+	seen := make(map[string]struct{})
+	storedAgainst := make(map[string]identity.Contract)
+	fetch := fetcher(s, storedAgainst)
+	for i := range identities {
+		found, result := lineage.Find(identities[i], fetch)
+		switch result {
+		case status.Success:
+		case status.NotFound:
+			continue
+		default:
+			return nil, fmt.Errorf("reading lineage returned %d", result)
+		}
+
+		for f := range found {
+			if _, exists := seen[found[f].Unique]; exists {
+				continue
+			}
+			seen[found[f].Unique] = struct{}{}
+			entries = append(entries, entry{id: storedAgainst[found[f].Unique], m: found[f]})
+		}
+	}
+	return entries, nil
+}
+
+// Export writes the annotations for the given identities (or, if none are given, every annotation in a store that
+// implements query.Contract) and the identities they are stored against to w and returns the archive's manifest.
+//
+// The identities annotations are stored against are read by identity; a store that does not implement query.Contract
+// is read using FindDescendants.
+func Export(
+	w io.Writer,
+	s store.Contract,
+	identities []identity.Contract,
+	hashProvider hashprovider.Contract) (*Manifest, error) {
+
+	entries, err := collect(s, identities)
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(line{
+		Kind:   headerKind,
+		Header: &Header{Format: Format, Version: Version, Created: datetime.Created()},
+	}); err != nil {
+		return nil, err
+	}
+
+	lines := make([][]byte, len(entries))
+	for i := range entries {
+		marshaledIdentity, err := json.Marshal(entries[i].id)
+		if err != nil {
+			return nil, err
+		}
+		marshaledAnnotation, err := json.Marshal(entries[i].m)
+		if err != nil {
+			return nil, err
+		}
+		if lines[i], err = json.Marshal(line{
+			Kind:         annotationKind,
+			IdentityKind: entries[i].id.Kind(),
+			Identity:     marshaledIdentity,
+			Annotation:   marshaledAnnotation,
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(append(lines[i], '\n')); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest := &Manifest{
+		Count:      len(entries),
+		DigestKind: hashProvider.Kind(),
+		Digest:     digest(hashProvider, lines),
+	}
+	if err := encoder.Encode(line{Kind: manifestKind, Manifest: manifest}); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// read parses and verifies an archive and returns its annotation lines and manifest.
+func read(r io.Reader, hashProvider hashprovider.Contract) ([]line, *Manifest, error) {
+	reader := bufio.NewReader(r)
+	annotations := make([]line, 0)
+	lines := make([][]byte, 0)
+	var header *Header
+	var manifest *Manifest
+	for {
+		b, err := reader.ReadBytes('\n')
+		if len(b) > 0 {
+			if manifest != nil {
+				return nil, nil, errors.New("content after manifest")
+			}
+
+			var l line
+			if err := json.Unmarshal(b, &l); err != nil {
+				return nil, nil, err
+			}
+			switch {
+			case l.Kind == headerKind && header == nil && l.Header != nil:
+				header = l.Header
+				if header.Format != Format || header.Version != Version {
+					return nil, nil, fmt.Errorf("unsupported archive %s version %d", header.Format, header.Version)
+				}
+			case l.Kind == annotationKind && header != nil:
+				annotations = append(annotations, l)
+				lines = append(lines, bytes.TrimSuffix(b, []byte{'\n'}))
+			case l.Kind == manifestKind && header != nil && l.Manifest != nil:
+				manifest = l.Manifest
+			default:
+				return nil, nil, fmt.Errorf("unexpected %s line", l.Kind)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch {
+	case manifest == nil:
+		return nil, nil, errors.New("manifest missing; archive truncated")
+	case manifest.Count != len(annotations):
+		return nil, nil, fmt.Errorf("manifest count %d does not match %d annotations", manifest.Count, len(annotations))
+	case manifest.DigestKind != hashProvider.Kind():
+		return nil, nil, fmt.Errorf("manifest digest type %s not supported", manifest.DigestKind)
+	case !bytes.Equal(manifest.Digest, digest(hashProvider, lines)):
+		return nil, nil, errors.New("manifest digest mismatch")
+	}
+	return annotations, manifest, nil
+}
+
+// Import verifies the archive read from r and, if it is intact, stores its annotations in s (decoded using the given
+// factories) and returns the archive's manifest.  Each annotation is stored against the identity recorded with it
+// (created as an opaque identity if the identity factory does not recognize its kind) with its unique, created, and
+// lineage values unchanged.
+//
+// Annotations whose unique is already present in the lineage of the identity they are stored against in s are
+// skipped, so importing
+// an archive more than once does not duplicate its annotations.  Nothing is stored unless the archive is intact, but
+// a store failure partway through leaves the annotations stored before it in place; repeating the import once the
+// failure is resolved stores the remainder.
+func Import(
+	r io.Reader,
+	s store.Contract,
+	hashProvider hashprovider.Contract,
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract) (*Manifest, error) {
+
+	lines, manifest, err := read(r, hashProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]entry, len(lines))
+	for i := range lines {
+		var a annotation.Instance
+		a.SetIdentityFactory(identityFactory)
+		a.SetMetadataFactory(metadataFactory)
+		if err := json.Unmarshal(lines[i].Annotation, &a); err != nil {
+			return nil, err
+		}
+		if a.CurrentIdentity == nil || a.Metadata == nil || lines[i].IdentityKind == "" || len(lines[i].Identity) == 0 {
+			return nil, fmt.Errorf("annotation %s: missing identity or metadata", a.Unique)
+		}
+		id := identityFactory.Create(lines[i].IdentityKind, lines[i].Identity)
+		if id == nil {
+			id = opaqueIdentity.New(lines[i].IdentityKind, lines[i].Identity)
+		}
+		entries[i] = entry{id: id, m: &a}
+	}
+
+	present := make(map[string]struct{})
+	fetched := make(map[string]struct{})
+	for i := range entries {
+		id, m := entries[i].id, entries[i].m
+		if _, ok := fetched[id.Printable()]; !ok {
+			fetched[id.Printable()] = struct{}{}
+			existing, result := s.FindByIdentity(id)
+			if result != status.Success && result != status.NotFound {
+				return nil, fmt.Errorf("FindByIdentity returned %d", result)
+			}
+			for e := range existing {
+				present[existing[e].Unique] = struct{}{}
+			}
+		}
+		if _, ok := present[m.Unique]; ok {
+			continue
+		}
+
+		result := s.Append(id, m)
+		if result == status.NotFound {
+			result = s.Create(id, m)
+		}
+		if result != status.Success {
+			return nil, fmt.Errorf("annotation %s: store returned %d", m.Unique, result)
+		}
+		present[m.Unique] = struct{}{}
+	}
+	return manifest, nil
+}
@@ -0,0 +1,476 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	metadataStubFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/chain"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	pkiFactory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signerMetadata is the signer metadata embedded in every test annotation.
+var signerMetadata = metadataStub.NewNullObject()
+
+// newMetadataFactory returns the metadata factory used to import test annotations.
+func newMetadataFactory() metadataFactory.Contract {
+	return metadataFactory.New(
+		[]metadataFactory.Contract{
+			pkiFactory.New([]metadataFactory.Contract{metadataStubFactory.New(signerMetadata)}),
+		},
+	)
+}
+
+// newAnnotation returns a new annotation for the given identities.
+func newAnnotation(id, previous identity.Contract) *annotation.Instance {
+	return annotation.New(
+		test.FactoryRandomFixedLengthAlphanumericString(26),
+		id,
+		previous,
+		metadata.New(
+			test.FactoryRandomString(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			signerMetadata,
+		),
+	)
+}
+
+// populate stores a lineage of four identities (the last derived from the second and third) in s and returns the
+// identities.
+func populate(t *testing.T, s store.Contract) []identity.Contract {
+	ids := []identity.Contract{
+		identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32)),
+		identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32)),
+		identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32)),
+		identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32)),
+	}
+	derived := annotation.NewDerived(
+		test.FactoryRandomFixedLengthAlphanumericString(26),
+		ids[3],
+		ids[1:3],
+		newAnnotation(ids[3], nil).Metadata,
+	)
+	require.Equal(t, status.Success, s.Create(ids[0], newAnnotation(ids[0], nil)))
+	require.Equal(t, status.Success, s.Append(ids[0], newAnnotation(ids[0], ids[0])))
+	require.Equal(t, status.Success, s.Create(ids[1], newAnnotation(ids[1], ids[0])))
+	require.Equal(t, status.Success, s.Create(ids[2], newAnnotation(ids[2], nil)))
+	require.Equal(t, status.Success, s.Create(ids[3], derived))
+	return ids
+}
+
+// export returns the archive of s for the given identities.
+func export(t *testing.T, s store.Contract, identities []identity.Contract) string {
+	var b bytes.Buffer
+	_, err := Export(&b, s, identities, sha256.New())
+	require.NoError(t, err)
+	return b.String()
+}
+
+// body returns archive without its header line.
+func body(archive string) string {
+	return strings.SplitN(archive, "\n", 2)[1]
+}
+
+// TestExport tests Export.
+func TestExport(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "identities with lineage",
+			test: func(t *testing.T) {
+				source := memory.New()
+				ids := populate(t, source)
+				var b bytes.Buffer
+
+				manifest, err := Export(&b, source, ids[1:2], sha256.New())
+
+				assert.NoError(t, err)
+				assert.Equal(t, 3, manifest.Count)
+				assert.Equal(t, 5, strings.Count(b.String(), "\n"))
+			},
+		},
+		{
+			name: "shared lineage exported once",
+			test: func(t *testing.T) {
+				source := memory.New()
+				ids := populate(t, source)
+
+				manifest, err := Export(&bytes.Buffer{}, source, ids[:2], sha256.New())
+
+				assert.NoError(t, err)
+				assert.Equal(t, 3, manifest.Count)
+			},
+		},
+		{
+			name: "entire queryable store",
+			test: func(t *testing.T) {
+				source := memory.New()
+				populate(t, source)
+
+				manifest, err := Export(&bytes.Buffer{}, source, nil, sha256.New())
+
+				assert.NoError(t, err)
+				assert.Equal(t, 5, manifest.Count)
+			},
+		},
+		{
+			name: "entire store without query support",
+			test: func(t *testing.T) {
+				source := chain.New(memory.New(), sha256.New())
+				populate(t, source)
+
+				manifest, err := Export(&bytes.Buffer{}, source, nil, sha256.New())
+
+				assert.Error(t, err)
+				assert.Nil(t, manifest)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestImport tests Import.
+func TestImport(t *testing.T) {
+	type testCase struct {
+		name   string
+		tamper func(archive string) string
+		valid  bool
+	}
+
+	cases := []testCase{
+		{
+			name:   "intact",
+			tamper: func(archive string) string { return archive },
+			valid:  true,
+		},
+		{
+			name: "truncated",
+			tamper: func(archive string) string {
+				lines := strings.SplitAfter(archive, "\n")
+				return strings.Join(lines[:len(lines)-2], "")
+			},
+			valid: false,
+		},
+		{
+			name: "annotation removed",
+			tamper: func(archive string) string {
+				lines := strings.SplitAfter(archive, "\n")
+				return strings.Join(append(lines[:1], lines[2:]...), "")
+			},
+			valid: false,
+		},
+		{
+			name: "annotation altered",
+			tamper: func(archive string) string {
+				lines := strings.SplitAfter(archive, "\n")
+				lines[1] = strings.Replace(lines[1], `"created":"`, `"created":"1`, 1)
+				return strings.Join(lines, "")
+			},
+			valid: false,
+		},
+		{
+			name: "stored identity altered",
+			tamper: func(archive string) string {
+				lines := strings.SplitAfter(archive, "\n")
+				lines[1] = strings.Replace(lines[1], `"identityType":"`, `"identityType":"1`, 1)
+				return strings.Join(lines, "")
+			},
+			valid: false,
+		},
+		{
+			name: "unsupported format",
+			tamper: func(archive string) string {
+				return strings.Replace(archive, Format, "other", 1)
+			},
+			valid: false,
+		},
+		{
+			name: "missing header",
+			tamper: func(archive string) string {
+				return body(archive)
+			},
+			valid: false,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				source := memory.New()
+				ids := populate(t, source)
+				archive := cases[i].tamper(export(t, source, ids))
+				destination := memory.New()
+
+				manifest, err := Import(
+					strings.NewReader(archive),
+					destination,
+					sha256.New(),
+					identityFactory.New(),
+					newMetadataFactory(),
+				)
+
+				if !cases[i].valid {
+					assert.Error(t, err)
+					assert.Nil(t, manifest)
+					for _, id := range ids {
+						_, result := destination.FindByIdentity(id)
+						assert.Equal(t, status.NotFound, result)
+					}
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, 5, manifest.Count)
+				for _, id := range ids {
+					expected, _ := source.FindByIdentity(id)
+					actual, result := destination.FindByIdentity(id)
+					assert.Equal(t, status.Success, result)
+					assert.Equal(t, testInternal.Marshal(t, expected), testInternal.Marshal(t, actual))
+				}
+				assert.Equal(t, body(export(t, source, nil)), body(export(t, destination, nil)))
+			},
+		)
+	}
+}
+
+// TestImport_UnrecognizedMetadata tests Import with a metadata factory that does not recognize the archive's
+// metadata; the metadata is retained unchanged.
+func TestImport_UnrecognizedMetadata(t *testing.T) {
+	source := memory.New()
+	populate(t, source)
+	destination := memory.New()
+
+	manifest, err := Import(
+		strings.NewReader(export(t, source, nil)),
+		destination,
+		sha256.New(),
+		identityFactory.New(),
+		metadataFactory.New([]metadataFactory.Contract{}),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, manifest.Count)
+	assert.Equal(t, body(export(t, source, nil)), body(export(t, destination, nil)))
+}
+
+// TestImport_StoredAgainst tests that annotations are imported against the identity they were stored against rather
+// than their current identity.
+func TestImport_StoredAgainst(t *testing.T) {
+	type testCase struct {
+		name       string
+		source     func() store.Contract
+		identities func(previous identity.Contract) []identity.Contract
+	}
+
+	cases := []testCase{
+		{
+			name:       "entire queryable store",
+			source:     func() store.Contract { return memory.New() },
+			identities: func(identity.Contract) []identity.Contract { return nil },
+		},
+		{
+			name:       "identities of store without query support",
+			source:     func() store.Contract { return chain.New(memory.New(), sha256.New()) },
+			identities: func(previous identity.Contract) []identity.Contract { return []identity.Contract{previous} },
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				previous := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				current := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				source := cases[i].source()
+				require.Equal(t, status.Success, source.Create(previous, newAnnotation(previous, nil)))
+				require.Equal(t, status.Success, source.Append(previous, newAnnotation(current, previous)))
+				destination := memory.New()
+
+				_, err := Import(
+					strings.NewReader(export(t, source, cases[i].identities(previous))),
+					destination,
+					sha256.New(),
+					identityFactory.New(),
+					newMetadataFactory(),
+				)
+
+				require.NoError(t, err)
+				expected, _ := source.FindByIdentity(previous)
+				actual, result := destination.FindByIdentity(previous)
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, testInternal.Marshal(t, expected), testInternal.Marshal(t, actual))
+				_, result = destination.FindByIdentity(current)
+				assert.Equal(t, status.NotFound, result)
+			},
+		)
+	}
+}
+
+// TestImport_MissingIdentity tests that an archive whose annotation lines do not record the identity they are stored
+// against is not imported.
+func TestImport_MissingIdentity(t *testing.T) {
+	id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	data, err := json.Marshal(newAnnotation(id, nil))
+	require.NoError(t, err)
+	annotationLine, err := json.Marshal(line{Kind: annotationKind, Annotation: data})
+	require.NoError(t, err)
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	require.NoError(t, encoder.Encode(line{Kind: headerKind, Header: &Header{Format: Format, Version: Version}}))
+	b.Write(append(annotationLine, '\n'))
+	require.NoError(t, encoder.Encode(line{
+		Kind: manifestKind,
+		Manifest: &Manifest{
+			Count:      1,
+			DigestKind: sha256.New().Kind(),
+			Digest:     digest(sha256.New(), [][]byte{annotationLine}),
+		},
+	}))
+	destination := memory.New()
+
+	manifest, err := Import(&b, destination, sha256.New(), identityFactory.New(), newMetadataFactory())
+
+	assert.Error(t, err)
+	assert.Nil(t, manifest)
+	_, result := destination.FindByIdentity(id)
+	assert.Equal(t, status.NotFound, result)
+}
+
+// failing is a store whose writes fail once limit annotations have been stored.
+type failing struct {
+	store.Contract
+	limit int
+}
+
+// Create stores annotations corresponding to a new identity and returns status.
+func (f *failing) Create(id identity.Contract, m *annotation.Instance) status.Value {
+	if f.limit == 0 {
+		return status.Unknown
+	}
+	f.limit--
+	return f.Contract.Create(id, m)
+}
+
+// Append stores annotations corresponding to identity and returns status.
+func (f *failing) Append(id identity.Contract, m *annotation.Instance) status.Value {
+	if f.limit == 0 {
+		return status.Unknown
+	}
+	f.limit--
+	return f.Contract.Append(id, m)
+}
+
+// TestImport_Repeated tests that repeating an import does not duplicate annotations and completes an import that
+// failed partway.
+func TestImport_Repeated(t *testing.T) {
+	type testCase struct {
+		name  string
+		first func(destination store.Contract) store.Contract
+	}
+
+	cases := []testCase{
+		{
+			name:  "completed import repeated",
+			first: func(destination store.Contract) store.Contract { return destination },
+		},
+		{
+			name:  "failed import repeated",
+			first: func(destination store.Contract) store.Contract { return &failing{Contract: destination, limit: 2} },
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				source := memory.New()
+				populate(t, source)
+				archive := export(t, source, nil)
+				destination := memory.New()
+				_, _ = Import(
+					strings.NewReader(archive),
+					cases[i].first(destination),
+					sha256.New(),
+					identityFactory.New(),
+					newMetadataFactory(),
+				)
+
+				manifest, err := Import(
+					strings.NewReader(archive),
+					destination,
+					sha256.New(),
+					identityFactory.New(),
+					newMetadataFactory(),
+				)
+
+				require.NoError(t, err)
+				assert.Equal(t, 5, manifest.Count)
+				assert.Equal(t, body(archive), body(export(t, destination, nil)))
+			},
+		)
+	}
+}
+
+// TestImport_Failure tests that a store failure partway through an import is returned and leaves the annotations
+// stored before it in place.
+func TestImport_Failure(t *testing.T) {
+	source := memory.New()
+	populate(t, source)
+	destination := memory.New()
+
+	manifest, err := Import(
+		strings.NewReader(export(t, source, nil)),
+		&failing{Contract: destination, limit: 2},
+		sha256.New(),
+		identityFactory.New(),
+		newMetadataFactory(),
+	)
+
+	assert.Error(t, err)
+	assert.Nil(t, manifest)
+	identities, result := destination.Identities()
+	assert.Equal(t, status.Success, result)
+	assert.NotEmpty(t, identities)
+}
@@ -0,0 +1,150 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// decoder implements one-call decoding of the annotation JSON produced by this SDK.
+//
+// Importing decoder registers every identity and metadata kind defined by the SDK; kinds defined elsewhere are
+// decoded once their packages register them (see the registry package).  Kinds that are not registered are
+// preserved as opaque identities or metadata, so annotations written by newer SDK releases or third-party
+// annotators are not lost.
+package decoder
+
+import (
+	"encoding/json"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/registry"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+
+	// register the SDK's metadata kinds
+	_ "github.com/project-alvarium/go-sdk/pkg/annotation/retention/metadata/factory"
+	_ "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/ownership/metadata/factory"
+	_ "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/metadata/factory"
+	_ "github.com/project-alvarium/go-sdk/pkg/annotator/assess/metadata/factory"
+	_ "github.com/project-alvarium/go-sdk/pkg/annotator/ownership/metadata/factory"
+	_ "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata/factory"
+	_ "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15/metadata/factory"
+	_ "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signtpmv2/metadata/factory"
+	_ "github.com/project-alvarium/go-sdk/pkg/annotator/publish/metadata/factory"
+	_ "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/example/metadata/factory"
+	_ "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/iota/metadata/factory"
+	_ "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/ipfs/metadata/factory"
+)
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	identityFactory       identityFactory.Contract
+	metadataFactory       metadataFactory.Contract
+	signerMetadataFactory metadataFactory.Contract
+}
+
+// New is a factory function that returns an initialized instance that decodes the kinds registered in registry.
+func New(registry registry.Contract) *instance {
+	return newWithFactories(
+		registry.IdentityFactory(),
+		registry.MetadataFactory(metadataScope),
+		registry.MetadataFactory(signerScope),
+	)
+}
+
+// newWithFactories is a factory function that returns an initialized instance.
+func newWithFactories(
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract,
+	signerMetadataFactory metadataFactory.Contract) *instance {
+
+	return &instance{
+		identityFactory:       identityFactory,
+		metadataFactory:       metadataFactory,
+		signerMetadataFactory: signerMetadataFactory,
+	}
+}
+
+// metadataScope and signerScope are the scopes of the metadata kinds found directly within an annotation: its
+// metadata and the signer metadata of its signature.
+const (
+	metadataScope = registry.Metadata
+	signerScope   = registry.Signer
+)
+
+// IdentityFactory returns the factory used to decode identities.
+func (i *instance) IdentityFactory() identityFactory.Contract {
+	return i.identityFactory
+}
+
+// MetadataFactory returns the factory used to decode metadata.
+func (i *instance) MetadataFactory() metadataFactory.Contract {
+	return i.metadataFactory
+}
+
+// Decode converts annotation JSON into an annotation.
+func (i *instance) Decode(data []byte) (*annotation.Instance, error) {
+	var a annotation.Instance
+	a.SetIdentityFactory(i.identityFactory)
+	a.SetMetadataFactory(i.metadataFactory)
+	a.SetSignerMetadataFactory(i.signerMetadataFactory)
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// DecodeAll converts a JSON array of annotations (as published) into annotations.
+func (i *instance) DecodeAll(data []byte) ([]*annotation.Instance, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	annotations := make([]*annotation.Instance, len(raw))
+	for r := range raw {
+		a, err := i.Decode(raw[r])
+		if err != nil {
+			return nil, err
+		}
+		annotations[r] = a
+	}
+	return annotations, nil
+}
+
+// decoder decodes the kinds registered in the process-wide registry.
+var decoder = newWithFactories(
+	registry.NewIdentityFactory(),
+	registry.NewMetadataFactory(metadataScope),
+	registry.NewMetadataFactory(signerScope),
+)
+
+// IdentityFactory returns a factory that decodes every identity kind registered in the process-wide registry.
+func IdentityFactory() identityFactory.Contract {
+	return decoder.IdentityFactory()
+}
+
+// MetadataFactory returns a factory that decodes every annotation metadata kind registered in the process-wide
+// registry; it is suitable for the stores' metadata factory (signature signer metadata is decoded with the registered
+// signer kinds).
+func MetadataFactory() metadataFactory.Contract {
+	return decoder.MetadataFactory()
+}
+
+// Decode converts annotation JSON into an annotation using the kinds registered in the process-wide registry.
+func Decode(data []byte) (*annotation.Instance, error) {
+	return decoder.Decode(data)
+}
+
+// DecodeAll converts a JSON array of annotations into annotations using the kinds registered in the process-wide
+// registry.
+func DecodeAll(data []byte) ([]*annotation.Instance, error) {
+	return decoder.DecodeAll(data)
+}
@@ -0,0 +1,274 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package decoder
+
+import (
+	"crypto"
+	"encoding/json"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	opaqueMetadata "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/opaque"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/registry"
+	retentionMetadata "github.com/project-alvarium/go-sdk/pkg/annotation/retention/metadata"
+	pkiAssessorMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/metadata"
+	assessMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/metadata"
+	ownershipMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/ownership/metadata"
+	pkiMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	signpkcs1v15Metadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15/metadata"
+	publishMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/metadata"
+	exampleMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/example/metadata"
+	iotaMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/iota/metadata"
+	ipfsMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/ipfs/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/identity/principal"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIdentity returns a new random identity.
+func newIdentity() identity.Contract {
+	return identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+}
+
+// newAnnotation returns a new annotation for m with randomized identities, author, and owner.
+func newAnnotation(m metadata.Contract) *annotation.Instance {
+	a := annotation.New(test.FactoryRandomString(), newIdentity(), newIdentity(), m)
+	a.SetAuthor(principal.New(test.FactoryRandomString()))
+	a.SetOwner(principal.New(test.FactoryRandomString()))
+	return a
+}
+
+// newPKIMetadata returns new PKI metadata embedding signer.
+func newPKIMetadata(signer metadata.Contract) *pkiMetadata.Instance {
+	return pkiMetadata.New(
+		test.FactoryRandomString(),
+		test.FactoryRandomByteSlice(),
+		test.FactoryRandomByteSlice(),
+		test.FactoryRandomByteSlice(),
+		signer,
+	)
+}
+
+// marshal returns the JSON encoding of v.
+func marshal(t *testing.T, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
+// TestDecode tests Decode.
+func TestDecode(t *testing.T) {
+	type testCase struct {
+		name       string
+		annotation *annotation.Instance
+		assert     func(t *testing.T, result *annotation.Instance)
+	}
+
+	assertType := func(expected interface{}) func(t *testing.T, result *annotation.Instance) {
+		return func(t *testing.T, result *annotation.Instance) {
+			assert.IsType(t, expected, result.Metadata)
+		}
+	}
+
+	cases := []testCase{
+		{
+			name:       "pki",
+			annotation: newAnnotation(newPKIMetadata(signpkcs1v15Metadata.NewSuccess(crypto.SHA256, "sha256"))),
+			assert: func(t *testing.T, result *annotation.Instance) {
+				require.IsType(t, &pkiMetadata.Instance{}, result.Metadata)
+				assert.IsType(t, &signpkcs1v15Metadata.Success{}, result.Metadata.(*pkiMetadata.Instance).SignerMetadata)
+			},
+		},
+		{
+			name: "assess",
+			annotation: newAnnotation(
+				assessMetadata.New(test.FactoryRandomString(), pkiAssessorMetadata.NewSuccess(true, []string{})),
+			),
+			assert: func(t *testing.T, result *annotation.Instance) {
+				require.IsType(t, &assessMetadata.Instance{}, result.Metadata)
+				assert.IsType(t, &pkiAssessorMetadata.Success{}, result.Metadata.(*assessMetadata.Instance).AssessorMetadata)
+			},
+		},
+		{
+			name:       "publish ipfs",
+			annotation: newAnnotation(publishMetadata.New(test.FactoryRandomString(), ipfsMetadata.NewSuccess("cid"))),
+			assert: func(t *testing.T, result *annotation.Instance) {
+				require.IsType(t, &publishMetadata.Instance{}, result.Metadata)
+				assert.IsType(t, &ipfsMetadata.Success{}, result.Metadata.(*publishMetadata.Instance).PublisherMetadata)
+			},
+		},
+		{
+			name:       "publish iota",
+			annotation: newAnnotation(publishMetadata.New(test.FactoryRandomString(), iotaMetadata.NewFailure("failed"))),
+			assert: func(t *testing.T, result *annotation.Instance) {
+				require.IsType(t, &publishMetadata.Instance{}, result.Metadata)
+				assert.IsType(t, &iotaMetadata.Failure{}, result.Metadata.(*publishMetadata.Instance).PublisherMetadata)
+			},
+		},
+		{
+			name:       "publish example",
+			annotation: newAnnotation(publishMetadata.New(test.FactoryRandomString(), exampleMetadata.NewSuccess())),
+			assert: func(t *testing.T, result *annotation.Instance) {
+				require.IsType(t, &publishMetadata.Instance{}, result.Metadata)
+				assert.IsType(t, &exampleMetadata.Success{}, result.Metadata.(*publishMetadata.Instance).PublisherMetadata)
+			},
+		},
+		{
+			name:       "ownership",
+			annotation: newAnnotation(ownershipMetadata.New(test.FactoryRandomString(), ownershipMetadata.ActionCreate, "")),
+			assert:     assertType(&ownershipMetadata.Instance{}),
+		},
+		{
+			name:       "retention",
+			annotation: newAnnotation(retentionMetadata.New([]string{"unique"}, []string{pkiMetadata.Kind}, "from", "to")),
+			assert:     assertType(&retentionMetadata.Instance{}),
+		},
+		func() testCase {
+			signed := newAnnotation(ownershipMetadata.New(test.FactoryRandomString(), ownershipMetadata.ActionCreate, ""))
+			signed.Signature = &annotation.Signature{
+				Value:          test.FactoryRandomByteSlice(),
+				PublicKey:      test.FactoryRandomByteSlice(),
+				SignerKind:     signpkcs1v15Metadata.Kind,
+				SignerMetadata: signpkcs1v15Metadata.NewSuccess(crypto.SHA256, "sha256"),
+			}
+			return testCase{
+				name:       "signature",
+				annotation: signed,
+				assert: func(t *testing.T, result *annotation.Instance) {
+					require.NotNil(t, result.Signature)
+					assert.IsType(t, &signpkcs1v15Metadata.Success{}, result.Signature.SignerMetadata)
+				},
+			}
+		}(),
+		{
+			name:       "unknown metadata kind",
+			annotation: newAnnotation(metadataStub.New("unknown", test.FactoryRandomString())),
+			assert:     assertType(&opaqueMetadata.Instance{}),
+		},
+		{
+			name:       "unknown signer kind",
+			annotation: newAnnotation(newPKIMetadata(metadataStub.New("unknown", test.FactoryRandomString()))),
+			assert: func(t *testing.T, result *annotation.Instance) {
+				require.IsType(t, &pkiMetadata.Instance{}, result.Metadata)
+				assert.IsType(t, &opaqueMetadata.Instance{}, result.Metadata.(*pkiMetadata.Instance).SignerMetadata)
+			},
+		},
+		func() testCase {
+			a := newAnnotation(ownershipMetadata.New(test.FactoryRandomString(), ownershipMetadata.ActionCreate, ""))
+			a.CurrentIdentity = principal.New(test.FactoryRandomString())
+			a.CurrentIdentityKind = "unknown"
+			return testCase{
+				name:       "unknown identity kind",
+				annotation: a,
+				assert: func(t *testing.T, result *annotation.Instance) {
+					assert.IsType(t, &opaqueIdentity.Identity{}, result.CurrentIdentity)
+				},
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				data := marshal(t, cases[i].annotation)
+
+				result, err := Decode(data)
+
+				require.NoError(t, err)
+				cases[i].assert(t, result)
+				assert.Equal(t, string(data), string(marshal(t, result)))
+			},
+		)
+	}
+}
+
+// TestDecodeAll tests DecodeAll.
+func TestDecodeAll(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "published annotations",
+			test: func(t *testing.T) {
+				annotations := []*annotation.Instance{
+					newAnnotation(newPKIMetadata(signpkcs1v15Metadata.NewSuccess(crypto.SHA256, "sha256"))),
+					newAnnotation(publishMetadata.New(test.FactoryRandomString(), ipfsMetadata.NewSuccess("cid"))),
+				}
+				data := marshal(t, annotations)
+
+				result, err := DecodeAll(data)
+
+				require.NoError(t, err)
+				assert.Len(t, result, len(annotations))
+				assert.Equal(t, string(data), string(marshal(t, result)))
+			},
+		},
+		{
+			name: "not an array",
+			test: func(t *testing.T) {
+				_, err := DecodeAll([]byte(`{}`))
+
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "malformed annotation",
+			test: func(t *testing.T) {
+				_, err := DecodeAll([]byte(`[{"unique":1}]`))
+
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestNew tests that a decoder over an empty registry preserves every kind.
+func TestNew(t *testing.T) {
+	a := newAnnotation(newPKIMetadata(signpkcs1v15Metadata.NewSuccess(crypto.SHA256, "sha256")))
+	data := marshal(t, a)
+	sut := New(registry.New())
+
+	result, err := sut.Decode(data)
+
+	require.NoError(t, err)
+	assert.IsType(t, &opaqueIdentity.Identity{}, result.CurrentIdentity)
+	assert.IsType(t, &opaqueMetadata.Instance{}, result.Metadata)
+	assert.Equal(t, string(data), string(marshal(t, result)))
+}
+
+// TestKinds tests that importing decoder registers the SDK's kinds.
+func TestKinds(t *testing.T) {
+	assert.Equal(t, []string{identityHash.Kind, principal.Kind}, registry.Kinds(registry.Identity))
+	assert.Equal(
+		t,
+		[]string{assessMetadata.Kind, ownershipMetadata.Kind, pkiMetadata.Kind, publishMetadata.Kind, retentionMetadata.Kind},
+		registry.Kinds(registry.Metadata),
+	)
+}
@@ -0,0 +1,140 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// cbor implements an annotation encoding on top of CBOR (RFC 7049).
+//
+// Values are encoded deterministically (core deterministic encoding, RFC 7049 section 3.9) using their JSON field
+// names, so a CBOR annotation carries the same structure as its JSON counterpart in fewer bytes.  Polymorphic values
+// (identities and metadata) are decoded by transcoding to JSON and reusing the factory-driven JSON decoding.
+package cbor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const Kind = "cbor"
+
+// EmbeddedJSON is the tag number of a byte string containing JSON (IANA registered tag 262), used to carry JSON that
+// must be reproduced exactly (see EmbedJSON).
+const EmbeddedJSON = 262
+
+// encMode is the deterministic encoding mode shared by every marshal operation.
+var encMode = func() cbor.EncMode {
+	mode, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct{}
+
+// New is a factory function that returns an initialized instance.
+func New() *instance {
+	return &instance{}
+}
+
+// Marshal returns the CBOR encoding of v.
+func Marshal(v interface{}) ([]byte, error) {
+	return encMode.Marshal(v)
+}
+
+// Unmarshal decodes CBOR data into the value pointed to by v.
+func Unmarshal(data []byte, v interface{}) error {
+	return cbor.Unmarshal(data, v)
+}
+
+// EmbedJSON returns a CBOR data item that carries JSON data unchanged; ToJSON reproduces the embedded JSON as is
+// (compacted) rather than transcoding it.
+func EmbedJSON(data []byte) ([]byte, error) {
+	content, err := encMode.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return cbor.RawTag{Number: EmbeddedJSON, Content: content}.MarshalCBOR()
+}
+
+// ToJSON transcodes a CBOR data item to JSON; byte strings become base64 strings as encoding/json would produce.
+func ToJSON(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := cbor.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	converted, err := convert(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(converted)
+}
+
+// convert replaces the generic maps produced by the CBOR decoder with string-keyed maps JSON can represent.
+func convert(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key := range v {
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("cbor: unsupported map key type %T", key)
+			}
+			converted, err := convert(v[key])
+			if err != nil {
+				return nil, err
+			}
+			m[name] = converted
+		}
+		return m, nil
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i := range v {
+			converted, err := convert(v[i])
+			if err != nil {
+				return nil, err
+			}
+			s[i] = converted
+		}
+		return s, nil
+	case cbor.Tag:
+		if v.Number != EmbeddedJSON {
+			return convert(v.Content)
+		}
+		data, ok := v.Content.([]byte)
+		if !ok || !json.Valid(data) {
+			return nil, errors.New("cbor: embedded JSON must be a byte string containing valid JSON")
+		}
+		return json.RawMessage(data), nil
+	}
+	return value, nil
+}
+
+// Marshal returns the CBOR encoding of v.
+func (*instance) Marshal(v interface{}) ([]byte, error) {
+	return Marshal(v)
+}
+
+// Unmarshal decodes CBOR data into the value pointed to by v.
+func (*instance) Unmarshal(data []byte, v interface{}) error {
+	return Unmarshal(data, v)
+}
+
+// Kind returns an implementation mnemonic.
+func (*instance) Kind() string {
+	return Kind
+}
@@ -0,0 +1,103 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package cbor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// embedded is a type whose CBOR encoding embeds its JSON.
+type embedded json.RawMessage
+
+// MarshalCBOR embeds the value's JSON.
+func (e embedded) MarshalCBOR() ([]byte, error) {
+	return EmbedJSON(e)
+}
+
+// TestToJSON tests ToJSON.
+func TestToJSON(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "structure",
+			test: func(t *testing.T) {
+				value := map[string]interface{}{
+					"name":   test.FactoryRandomString(),
+					"values": []interface{}{"a", true},
+					"data":   []byte(test.FactoryRandomString()),
+				}
+				data, err := Marshal(value)
+				require.NoError(t, err)
+
+				result, err := ToJSON(data)
+
+				require.NoError(t, err)
+				expected, err := json.Marshal(value)
+				require.NoError(t, err)
+				assert.Equal(t, string(expected), string(result))
+			},
+		},
+		{
+			name: "embedded JSON reproduced",
+			test: func(t *testing.T) {
+				retained := `{"z":1,"a":[2.50,"` + test.FactoryRandomString() + `"]}`
+				data, err := Marshal(map[string]interface{}{"value": embedded(retained)})
+				require.NoError(t, err)
+
+				result, err := ToJSON(data)
+
+				require.NoError(t, err)
+				assert.Equal(t, `{"value":`+retained+`}`, string(result))
+			},
+		},
+		{
+			name: "embedded invalid JSON",
+			test: func(t *testing.T) {
+				data, err := Marshal(cbor.Tag{Number: EmbeddedJSON, Content: []byte("{")})
+				require.NoError(t, err)
+
+				_, err = ToJSON(data)
+
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "non-string map key",
+			test: func(t *testing.T) {
+				data, err := Marshal(map[int]string{1: test.FactoryRandomString()})
+				require.NoError(t, err)
+
+				_, err = ToJSON(data)
+
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
@@ -0,0 +1,231 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package encoding
+
+import (
+	"crypto"
+	"encoding/json"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
+	jsonEncoding "github.com/project-alvarium/go-sdk/pkg/annotation/encoding/json"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	retentionMetadata "github.com/project-alvarium/go-sdk/pkg/annotation/retention/metadata"
+	retentionFactory "github.com/project-alvarium/go-sdk/pkg/annotation/retention/metadata/factory"
+	pkiAssessorMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/metadata"
+	assessMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/metadata"
+	assessFactory "github.com/project-alvarium/go-sdk/pkg/annotator/assess/metadata/factory"
+	ownershipMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/ownership/metadata"
+	ownershipFactory "github.com/project-alvarium/go-sdk/pkg/annotator/ownership/metadata/factory"
+	pkiMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	pkiFactory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata/factory"
+	signpkcs1v15Metadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15/metadata"
+	publishMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/metadata"
+	publishFactory "github.com/project-alvarium/go-sdk/pkg/annotator/publish/metadata/factory"
+	iotaMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/iota/metadata"
+	ipfsMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/ipfs/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/identity/principal"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namedEncoding pairs an encoding under test with a name distinguishing it from other encodings of the same kind.
+type namedEncoding struct {
+	name     string
+	encoding Contract
+}
+
+// encodings returns the encodings under test.
+func encodings() []namedEncoding {
+	return []namedEncoding{
+		{name: "json", encoding: jsonEncoding.New()},
+		{name: "canonical json", encoding: jsonEncoding.NewCanonical()},
+		{name: "cbor", encoding: cbor.New()},
+	}
+}
+
+// newIdentity returns a new random identity.
+func newIdentity() identity.Contract {
+	return identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+}
+
+// newAnnotation returns a new annotation for m with randomized identities, author, and owner.
+func newAnnotation(m metadata.Contract) *annotation.Instance {
+	previous := []identity.Contract{newIdentity(), newIdentity()}
+	a := annotation.NewDerived(test.FactoryRandomString(), newIdentity(), previous, m)
+	a.SetAuthor(principal.New(test.FactoryRandomString()))
+	a.SetOwner(principal.New(test.FactoryRandomString()))
+	return a
+}
+
+// newAnnotations returns one annotation for each built-in metadata kind.
+func newAnnotations() []*annotation.Instance {
+	return []*annotation.Instance{
+		newAnnotation(
+			pkiMetadata.New(
+				test.FactoryRandomString(),
+				test.FactoryRandomByteSlice(),
+				test.FactoryRandomByteSlice(),
+				test.FactoryRandomByteSlice(),
+				signpkcs1v15Metadata.NewSuccess(crypto.SHA256, test.FactoryRandomString()),
+			),
+		),
+		newAnnotation(
+			assessMetadata.New(
+				test.FactoryRandomString(),
+				pkiAssessorMetadata.NewSuccess(true, []string{test.FactoryRandomString(), test.FactoryRandomString()}),
+			),
+		),
+		newAnnotation(publishMetadata.New(test.FactoryRandomString(), ipfsMetadata.NewSuccess(test.FactoryRandomString()))),
+		newAnnotation(
+			publishMetadata.New(
+				test.FactoryRandomString(),
+				iotaMetadata.NewSuccess(test.FactoryRandomString(), test.FactoryRandomString(), test.FactoryRandomString()),
+			),
+		),
+		newAnnotation(ownershipMetadata.New(test.FactoryRandomString(), ownershipMetadata.ActionTransfer, "previous")),
+		newAnnotation(
+			retentionMetadata.New(
+				[]string{test.FactoryRandomString()},
+				[]string{pkiMetadata.Kind},
+				test.FactoryRandomString(),
+				test.FactoryRandomString(),
+			),
+		),
+	}
+}
+
+// newInstance returns an annotation ready to be decoded with the default factories.
+func newInstance() *annotation.Instance {
+	var a annotation.Instance
+	a.SetIdentityFactory(identityFactory.New())
+	a.SetMetadataFactory(
+		metadataFactory.New(
+			[]metadataFactory.Contract{
+				pkiFactory.NewDefault(),
+				assessFactory.NewDefault(),
+				publishFactory.NewDefault(),
+				ownershipFactory.New(),
+				retentionFactory.NewDefault(),
+			},
+		),
+	)
+	return &a
+}
+
+// TestEncoding_RoundTrip tests that every encoding round-trips every built-in metadata kind.
+func TestEncoding_RoundTrip(t *testing.T) {
+	annotations := newAnnotations()
+	for _, named := range encodings() {
+		e := named.encoding
+		for a := range annotations {
+			t.Run(
+				named.name+"/"+annotations[a].MetadataKind,
+				func(t *testing.T) {
+					data, err := e.Marshal(annotations[a])
+					require.NoError(t, err)
+
+					sut := newInstance()
+					err = e.Unmarshal(data, sut)
+
+					require.NoError(t, err)
+					require.NotNil(t, sut.Metadata)
+					assert.Equal(t, annotations[a].Metadata.Kind(), sut.Metadata.Kind())
+					expected, err := json.Marshal(annotations[a])
+					require.NoError(t, err)
+					actual, err := json.Marshal(sut)
+					require.NoError(t, err)
+					assert.Equal(t, string(expected), string(actual))
+				},
+			)
+		}
+	}
+}
+
+// TestEncoding_Size tests that CBOR encodes annotations more compactly than JSON.
+func TestEncoding_Size(t *testing.T) {
+	annotations := newAnnotations()
+	for a := range annotations {
+		t.Run(
+			annotations[a].MetadataKind,
+			func(t *testing.T) {
+				j, err := jsonEncoding.New().Marshal(annotations[a])
+				require.NoError(t, err)
+				c, err := cbor.New().Marshal(annotations[a])
+				require.NoError(t, err)
+
+				assert.Less(t, len(c), len(j))
+			},
+		)
+	}
+}
+
+// BenchmarkMarshal compares encoding size and marshal throughput.
+func BenchmarkMarshal(b *testing.B) {
+	annotations := newAnnotations()
+	for _, named := range encodings() {
+		e := named.encoding
+		b.Run(
+			named.name,
+			func(b *testing.B) {
+				size := 0
+				b.ReportAllocs()
+				for n := 0; n < b.N; n++ {
+					data, err := e.Marshal(annotations[n%len(annotations)])
+					if err != nil {
+						b.Fatal(err)
+					}
+					size += len(data)
+				}
+				b.ReportMetric(float64(size)/float64(b.N), "bytes/annotation")
+			},
+		)
+	}
+}
+
+// BenchmarkUnmarshal compares unmarshal throughput, including factory-driven metadata decoding.
+func BenchmarkUnmarshal(b *testing.B) {
+	annotations := newAnnotations()
+	for _, named := range encodings() {
+		e := named.encoding
+		encoded := make([][]byte, len(annotations))
+		for a := range annotations {
+			data, err := e.Marshal(annotations[a])
+			if err != nil {
+				b.Fatal(err)
+			}
+			encoded[a] = data
+		}
+
+		b.Run(
+			named.name,
+			func(b *testing.B) {
+				b.ReportAllocs()
+				for n := 0; n < b.N; n++ {
+					if err := e.Unmarshal(encoded[n%len(encoded)], newInstance()); err != nil {
+						b.Fatal(err)
+					}
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,58 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// json implements an annotation encoding on top of JSON.
+package json
+
+import (
+	encoding "encoding/json"
+
+	"github.com/project-alvarium/go-sdk/pkg/canonical"
+)
+
+const Kind = "json"
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// New is a factory function that returns an initialized instance.
+func New() *instance {
+	return &instance{
+		marshal: encoding.Marshal,
+	}
+}
+
+// NewCanonical is a factory function that returns an initialized instance whose output is canonicalized per RFC 8785.
+func NewCanonical() *instance {
+	return &instance{
+		marshal: canonical.Marshal,
+	}
+}
+
+// Marshal returns the JSON encoding of v.
+func (i *instance) Marshal(v interface{}) ([]byte, error) {
+	return i.marshal(v)
+}
+
+// Unmarshal decodes JSON data into the value pointed to by v.
+func (*instance) Unmarshal(data []byte, v interface{}) error {
+	return encoding.Unmarshal(data, v)
+}
+
+// Kind returns an implementation mnemonic.
+func (*instance) Kind() string {
+	return Kind
+}
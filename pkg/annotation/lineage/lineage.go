@@ -0,0 +1,120 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// lineage implements a cycle-safe traversal of an identity's chain of custody shared by store implementations.
+package lineage
+
+import (
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// Fetch returns the annotations stored directly against identity (without traversal) and status; it returns
+// status.NotFound if nothing is stored for identity.
+type Fetch func(id identity.Contract) ([]*annotation.Instance, status.Value)
+
+// Derived returns the identities whose annotations name identity as one of their predecessors and status.
+type Derived func(id identity.Contract) ([]identity.Contract, status.Value)
+
+// Node is an identity within a tree of descendants along with the annotations stored directly against it.
+type Node struct {
+	Identity    identity.Contract      `json:"identity"`
+	Annotations []*annotation.Instance `json:"annotations"`
+	Children    []*Node                `json:"children"`
+}
+
+// Find traverses a chain of custody breadth-first starting at id and returns its annotations.
+//
+// Each identity is visited at most once, so reverts (A -> B -> A) and diamonds terminate and contribute their
+// annotations exactly once.  Every distinct predecessor (see annotation.Instance.Predecessors) is followed in the order it is first encountered,
+// which makes the result deterministic for a given store ordering.  Previous identities that are not stored are
+// skipped; any other failure aborts the traversal and is returned.
+func Find(id identity.Contract, fetch Fetch) ([]*annotation.Instance, status.Value) {
+	annotations := make([]*annotation.Instance, 0)
+	visited := map[string]struct{}{id.Printable(): {}}
+	queue := []identity.Contract{id}
+	for root := true; len(queue) > 0; root = false {
+		current := queue[0]
+		queue = queue[1:]
+
+		m, result := fetch(current)
+		switch {
+		case result == status.NotFound && root:
+			return annotations, status.NotFound
+		case result == status.NotFound:
+			continue
+		case result != status.Success:
+			return annotations, result
+		}
+
+		for i := range m {
+			annotations = append(annotations, m[i])
+			predecessors := m[i].Predecessors()
+			for p := range predecessors {
+				if _, seen := visited[predecessors[p].Printable()]; !seen {
+					visited[predecessors[p].Printable()] = struct{}{}
+					queue = append(queue, predecessors[p])
+				}
+			}
+		}
+	}
+	return annotations, status.Success
+}
+
+// Descendants traverses derivations breadth-first starting at id and returns the tree of identities derived from it.
+//
+// Each identity appears in the tree at most once (at its shallowest position, first encountered), so reverts and
+// diamonds terminate.  Derived identities that are not stored are skipped; any other failure aborts the traversal
+// and is returned.
+func Descendants(id identity.Contract, fetch Fetch, derived Derived) (*Node, status.Value) {
+	m, result := fetch(id)
+	if result != status.Success {
+		return nil, result
+	}
+
+	root := &Node{Identity: id, Annotations: m, Children: make([]*Node, 0)}
+	visited := map[string]struct{}{id.Printable(): {}}
+	queue := []*Node{root}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+
+		children, result := derived(parent.Identity)
+		if result != status.Success {
+			return nil, result
+		}
+
+		for c := range children {
+			p := children[c].Printable()
+			if _, seen := visited[p]; seen {
+				continue
+			}
+			visited[p] = struct{}{}
+
+			m, result := fetch(children[c])
+			switch {
+			case result == status.NotFound:
+				continue
+			case result != status.Success:
+				return nil, result
+			}
+
+			child := &Node{Identity: children[c], Annotations: m, Children: make([]*Node, 0)}
+			parent.Children = append(parent.Children, child)
+			queue = append(queue, child)
+		}
+	}
+	return root, status.Success
+}
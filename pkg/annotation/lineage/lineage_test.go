@@ -0,0 +1,397 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package lineage
+
+import (
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// graph is a test store that retains annotations in insertion order.
+type graph []*annotation.Instance
+
+// add stores an annotation for id with the given previous identity and returns its unique.
+func (g *graph) add(id, previous identity.Contract) string {
+	unique := test.FactoryRandomFixedLengthAlphanumericString(26)
+	*g = append(*g, annotation.New(unique, id, previous, metadataStub.NewNullObject()))
+	return unique
+}
+
+// merge stores an annotation for id derived from the given previous identities and returns its unique.
+func (g *graph) merge(id identity.Contract, previous ...identity.Contract) string {
+	unique := test.FactoryRandomFixedLengthAlphanumericString(26)
+	*g = append(*g, annotation.NewDerived(unique, id, previous, metadataStub.NewNullObject()))
+	return unique
+}
+
+// fetch implements Fetch.
+func (g *graph) fetch(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	annotations := make([]*annotation.Instance, 0)
+	for _, m := range *g {
+		if m.CurrentIdentity.Printable() == id.Printable() {
+			annotations = append(annotations, m)
+		}
+	}
+	if len(annotations) == 0 {
+		return nil, status.NotFound
+	}
+	return annotations, status.Success
+}
+
+// derived implements Derived.
+func (g *graph) derived(id identity.Contract) ([]identity.Contract, status.Value) {
+	identities := make([]identity.Contract, 0)
+	for _, m := range *g {
+		for _, p := range m.Predecessors() {
+			if p.Printable() == id.Printable() {
+				identities = append(identities, m.CurrentIdentity)
+			}
+		}
+	}
+	return identities, status.Success
+}
+
+// newIdentity returns a new random identity.
+func newIdentity() identity.Contract {
+	return identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+}
+
+// uniques returns the unique values of the given annotations.
+func uniques(annotations []*annotation.Instance) []string {
+	result := make([]string, len(annotations))
+	for i := range annotations {
+		result[i] = annotations[i].Unique
+	}
+	return result
+}
+
+// TestFind tests Find.
+func TestFind(t *testing.T) {
+	type testCase struct {
+		name            string
+		identity        identity.Contract
+		fetch           Fetch
+		expectedUniques []string
+		expectedStatus  status.Value
+	}
+
+	cases := []testCase{
+		func() testCase {
+			g := &graph{}
+			return testCase{
+				name:            "does not exist",
+				identity:        newIdentity(),
+				fetch:           g.fetch,
+				expectedUniques: []string{},
+				expectedStatus:  status.NotFound,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a, b := newIdentity(), newIdentity()
+			u1 := g.add(a, nil)
+			u2 := g.add(b, a)
+			u3 := g.add(b, nil)
+			return testCase{
+				name:            "single predecessor",
+				identity:        b,
+				fetch:           g.fetch,
+				expectedUniques: []string{u2, u3, u1},
+				expectedStatus:  status.Success,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a := newIdentity()
+			u1 := g.add(a, nil)
+			u2 := g.add(a, a)
+			return testCase{
+				name:            "self reference",
+				identity:        a,
+				fetch:           g.fetch,
+				expectedUniques: []string{u1, u2},
+				expectedStatus:  status.Success,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a, b := newIdentity(), newIdentity()
+			u1 := g.add(a, nil)
+			u2 := g.add(b, a)
+			u3 := g.add(a, b)
+			return testCase{
+				name:            "revert",
+				identity:        a,
+				fetch:           g.fetch,
+				expectedUniques: []string{u1, u3, u2},
+				expectedStatus:  status.Success,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a, b, c, d := newIdentity(), newIdentity(), newIdentity(), newIdentity()
+			u1 := g.add(a, nil)
+			u2 := g.add(b, a)
+			u3 := g.add(c, a)
+			u4 := g.add(d, c)
+			u5 := g.add(d, b)
+			return testCase{
+				name:            "diamond",
+				identity:        d,
+				fetch:           g.fetch,
+				expectedUniques: []string{u4, u5, u3, u2, u1},
+				expectedStatus:  status.Success,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a, b, c, d := newIdentity(), newIdentity(), newIdentity(), newIdentity()
+			u1 := g.add(a, nil)
+			u2 := g.add(b, a)
+			u3 := g.add(c, nil)
+			u4 := g.merge(d, c, b, a)
+			return testCase{
+				name:            "multiple predecessors",
+				identity:        d,
+				fetch:           g.fetch,
+				expectedUniques: []string{u4, u3, u2, u1},
+				expectedStatus:  status.Success,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			expected := make([]string, 0)
+			var previous identity.Contract
+			for n := 0; n < 200; n++ {
+				id := newIdentity()
+				expected = append([]string{g.add(id, previous)}, expected...)
+				previous = id
+			}
+			return testCase{
+				name:            "deep chain",
+				identity:        previous,
+				fetch:           g.fetch,
+				expectedUniques: expected,
+				expectedStatus:  status.Success,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a := newIdentity()
+			u1 := g.add(a, newIdentity())
+			return testCase{
+				name:            "missing predecessor skipped",
+				identity:        a,
+				fetch:           g.fetch,
+				expectedUniques: []string{u1},
+				expectedStatus:  status.Success,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a, b := newIdentity(), newIdentity()
+			g.add(a, nil)
+			u2 := g.add(b, a)
+			return testCase{
+				name:     "failure aborts",
+				identity: b,
+				fetch: func(id identity.Contract) ([]*annotation.Instance, status.Value) {
+					if id.Printable() == a.Printable() {
+						return nil, status.Unknown
+					}
+					return g.fetch(id)
+				},
+				expectedUniques: []string{u2},
+				expectedStatus:  status.Unknown,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				annotations, result := Find(cases[i].identity, cases[i].fetch)
+
+				assert.Equal(t, cases[i].expectedStatus, result)
+				assert.Equal(t, cases[i].expectedUniques, uniques(annotations))
+			},
+		)
+	}
+}
+
+// tree is a simplified representation of a lineage.Node used for comparison.
+type tree struct {
+	Identity string
+	Uniques  []string
+	Children []tree
+}
+
+// flatten converts node into a tree.
+func flatten(node *Node) *tree {
+	if node == nil {
+		return nil
+	}
+	t := &tree{Identity: node.Identity.Printable(), Uniques: uniques(node.Annotations), Children: []tree{}}
+	for c := range node.Children {
+		t.Children = append(t.Children, *flatten(node.Children[c]))
+	}
+	return t
+}
+
+// TestDescendants tests Descendants.
+func TestDescendants(t *testing.T) {
+	type testCase struct {
+		name           string
+		identity       identity.Contract
+		fetch          Fetch
+		derived        Derived
+		expectedTree   *tree
+		expectedStatus status.Value
+	}
+
+	cases := []testCase{
+		func() testCase {
+			g := &graph{}
+			return testCase{
+				name:           "does not exist",
+				identity:       newIdentity(),
+				fetch:          g.fetch,
+				derived:        g.derived,
+				expectedTree:   nil,
+				expectedStatus: status.NotFound,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a := newIdentity()
+			u1 := g.add(a, nil)
+			u2 := g.add(a, a)
+			return testCase{
+				name:           "no descendants",
+				identity:       a,
+				fetch:          g.fetch,
+				derived:        g.derived,
+				expectedTree:   &tree{Identity: a.Printable(), Uniques: []string{u1, u2}, Children: []tree{}},
+				expectedStatus: status.Success,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a, b, c, d := newIdentity(), newIdentity(), newIdentity(), newIdentity()
+			u1 := g.add(a, nil)
+			u2 := g.add(b, a)
+			u3 := g.add(c, a)
+			u4 := g.add(d, c)
+			u5 := g.add(d, b)
+			return testCase{
+				name:     "diamond",
+				identity: a,
+				fetch:    g.fetch,
+				derived:  g.derived,
+				expectedTree: &tree{
+					Identity: a.Printable(),
+					Uniques:  []string{u1},
+					Children: []tree{
+						{
+							Identity: b.Printable(),
+							Uniques:  []string{u2},
+							Children: []tree{{Identity: d.Printable(), Uniques: []string{u4, u5}, Children: []tree{}}},
+						},
+						{Identity: c.Printable(), Uniques: []string{u3}, Children: []tree{}},
+					},
+				},
+				expectedStatus: status.Success,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a, b, c := newIdentity(), newIdentity(), newIdentity()
+			g.add(a, nil)
+			u2 := g.add(b, nil)
+			u3 := g.merge(c, a, b)
+			return testCase{
+				name:     "multiple predecessors",
+				identity: b,
+				fetch:    g.fetch,
+				derived:  g.derived,
+				expectedTree: &tree{
+					Identity: b.Printable(),
+					Uniques:  []string{u2},
+					Children: []tree{{Identity: c.Printable(), Uniques: []string{u3}, Children: []tree{}}},
+				},
+				expectedStatus: status.Success,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a, b := newIdentity(), newIdentity()
+			u1 := g.add(a, nil)
+			u2 := g.add(b, a)
+			u3 := g.add(a, b)
+			return testCase{
+				name:     "revert",
+				identity: a,
+				fetch:    g.fetch,
+				derived:  g.derived,
+				expectedTree: &tree{
+					Identity: a.Printable(),
+					Uniques:  []string{u1, u3},
+					Children: []tree{{Identity: b.Printable(), Uniques: []string{u2}, Children: []tree{}}},
+				},
+				expectedStatus: status.Success,
+			}
+		}(),
+		func() testCase {
+			g := &graph{}
+			a, b := newIdentity(), newIdentity()
+			g.add(a, nil)
+			g.add(b, a)
+			return testCase{
+				name:     "failure aborts",
+				identity: a,
+				fetch:    g.fetch,
+				derived: func(id identity.Contract) ([]identity.Contract, status.Value) {
+					if id.Printable() == b.Printable() {
+						return nil, status.Unknown
+					}
+					return g.derived(id)
+				},
+				expectedTree:   nil,
+				expectedStatus: status.Unknown,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				node, result := Descendants(cases[i].identity, cases[i].fetch, cases[i].derived)
+
+				assert.Equal(t, cases[i].expectedStatus, result)
+				assert.Equal(t, cases[i].expectedTree, flatten(node))
+			},
+		)
+	}
+}
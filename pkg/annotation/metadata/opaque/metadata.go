@@ -0,0 +1,78 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// opaque implements metadata of a kind the decoding metadata factories do not recognize (for example, one
+// introduced by a newer SDK release or a third-party annotator).
+//
+// The metadata retains its kind and JSON and re-marshals to the same bytes, so annotations containing it pass through
+// stores and publishers unchanged.  Retained JSON is reproduced as encoding/json compacts it; JSON that is already
+// compact (and, as encoding/json would, escapes '<', '>', and '&') is reproduced byte-for-byte.
+package opaque
+
+import (
+	"encoding/json"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+)
+
+// Instance is metadata of an unrecognized kind.
+type Instance struct {
+	kind string
+	data json.RawMessage
+}
+
+// New is a factory function that returns an initialized Instance.
+func New(kind string, data json.RawMessage) *Instance {
+	return &Instance{
+		kind: kind,
+		data: data,
+	}
+}
+
+// Create returns the metadata created by the first of factories to recognize kind; metadata no factory recognizes is
+// returned as opaque metadata retaining data, and null data is returned as nil.
+func Create(factories []factory.Contract, kind string, data json.RawMessage) metadata.Contract {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+
+	for f := range factories {
+		if result := factories[f].Create(kind, data); result != nil {
+			return result
+		}
+	}
+	return New(kind, data)
+}
+
+// Kind returns the type of concrete implementation.
+func (i *Instance) Kind() string {
+	return i.kind
+}
+
+// Raw returns the metadata's JSON.
+func (i *Instance) Raw() json.RawMessage {
+	return i.data
+}
+
+// MarshalJSON returns the metadata's retained JSON.
+func (i *Instance) MarshalJSON() ([]byte, error) {
+	return i.data, nil
+}
+
+// MarshalCBOR returns a CBOR data item embedding the metadata's retained JSON so it survives CBOR encoding unchanged.
+func (i *Instance) MarshalCBOR() ([]byte, error) {
+	return cbor.EmbedJSON(i.data)
+}
@@ -0,0 +1,104 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package opaque
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	stubFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub/factory"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInstance tests Instance.
+func TestInstance(t *testing.T) {
+	kind := test.FactoryRandomString()
+	data := json.RawMessage(`{"result":"success","value":"` + test.FactoryRandomString() + `"}`)
+
+	sut := New(kind, data)
+
+	assert.Equal(t, kind, sut.Kind())
+	assert.Equal(t, data, sut.Raw())
+	result, err := json.Marshal(sut)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(result))
+}
+
+// TestInstance_MarshalCBOR tests Instance.MarshalCBOR.
+func TestInstance_MarshalCBOR(t *testing.T) {
+	data := json.RawMessage(`{"z":"` + test.FactoryRandomString() + `","a":[1.50,null]}`)
+
+	encoded, err := cbor.Marshal(New(test.FactoryRandomString(), data))
+	require.NoError(t, err)
+	result, err := cbor.ToJSON(encoded)
+
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(result))
+}
+
+// TestCreate tests Create.
+func TestCreate(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	stub := metadataStub.New(test.FactoryRandomString(), test.FactoryRandomString())
+	factories := []factory.Contract{stubFactory.New(stub)}
+
+	cases := []testCase{
+		{
+			name: "recognized kind",
+			test: func(t *testing.T) {
+				result := Create(factories, stub.Kind(), json.RawMessage(`{"value":"`+test.FactoryRandomString()+`"}`))
+
+				assert.IsType(t, &metadataStub.Instance{}, result)
+			},
+		},
+		{
+			name: "unrecognized kind",
+			test: func(t *testing.T) {
+				kind := test.FactoryRandomString()
+				data := json.RawMessage(`{"value":"` + test.FactoryRandomString() + `"}`)
+
+				result := Create(factories, kind, data)
+
+				assert.Equal(t, New(kind, data), result)
+			},
+		},
+		{
+			name: "null",
+			test: func(t *testing.T) {
+				assert.Nil(t, Create(factories, test.FactoryRandomString(), json.RawMessage("null")))
+			},
+		},
+		{
+			name: "empty",
+			test: func(t *testing.T) {
+				assert.Nil(t, Create(factories, test.FactoryRandomString(), nil))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
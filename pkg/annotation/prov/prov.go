@@ -0,0 +1,377 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// prov implements export of annotation lineage as W3C PROV documents serialized as PROV-JSON and as PROV-O
+// (JSON-LD).
+//
+// Each identity is a prov:Entity and each annotation a prov:Activity (typed by its metadata kind) that used the
+// identity it annotates.  Previous identities are recorded as qualified derivations, annotation authors as agents
+// associated with the activity, and data owners as agents the entity is attributed to.  Metadata is carried as
+// attributes in the alvarium namespace.
+package prov
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	assessMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/metadata"
+	pkiMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
+	publishMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/canonical"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+const (
+	Prefix    = "alvarium"
+	Namespace = "urn:alvarium:"
+
+	provNamespace = "http://www.w3.org/ns/prov#"
+	xsdNamespace  = "http://www.w3.org/2001/XMLSchema#"
+
+	qualifiedName = "prov:QUALIFIED_NAME"
+	dateTime      = "xsd:dateTime"
+)
+
+// value is a typed attribute value; values without a datatype are strings.
+type value struct {
+	literal  string
+	datatype string
+}
+
+// attributes defines the map used to retain the attributes of an element by qualified name.
+type attributes map[string]value
+
+// derivation records that generated was derived from used by activity.
+type derivation struct {
+	generated string
+	used      string
+	activity  string
+}
+
+// relation records a binary relation (used, wasAssociatedWith, or wasAttributedTo) between two elements.
+type relation struct {
+	subject string
+	object  string
+}
+
+// Document is a PROV document describing the lineage recorded by a set of annotations.
+type Document struct {
+	entities     map[string]attributes
+	activities   map[string]attributes
+	agents       map[string]attributes
+	used         []relation
+	derivations  []derivation
+	associations []relation
+	attributions []relation
+	seen         map[string]struct{}
+}
+
+// New is a factory function that returns a Document describing annotations.
+func New(annotations []*annotation.Instance) *Document {
+	d := &Document{
+		entities:   make(map[string]attributes),
+		activities: make(map[string]attributes),
+		agents:     make(map[string]attributes),
+		seen:       make(map[string]struct{}),
+	}
+	for i := range annotations {
+		d.add(annotations[i])
+	}
+	return d
+}
+
+// Export returns a Document describing the annotations returned by FindByIdentity for identity and status.
+func Export(s store.Contract, id identity.Contract) (*Document, status.Value) {
+	annotations, result := s.FindByIdentity(id)
+	if result != status.Success {
+		return nil, result
+	}
+	return New(annotations), status.Success
+}
+
+// qualify returns the qualified name of an element of the given type with local name local.
+func qualify(elementType, local string) string {
+	return Prefix + ":" + elementType + "/" + url.PathEscape(local)
+}
+
+// marshal returns the canonical JSON encoding of v as a string.
+func marshal(v interface{}) string {
+	data, err := canonical.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// once returns whether the relation identified by key has not been recorded before (and records it).
+func (d *Document) once(key string) bool {
+	if _, exists := d.seen[key]; exists {
+		return false
+	}
+	d.seen[key] = struct{}{}
+	return true
+}
+
+// entity records id as an entity and returns its qualified name.
+func (d *Document) entity(id identity.Contract) string {
+	name := qualify("identity", id.Printable())
+	if _, exists := d.entities[name]; !exists {
+		d.entities[name] = attributes{Prefix + ":identityType": {literal: id.Kind()}}
+	}
+	return name
+}
+
+// agent records id as an agent and returns its qualified name.
+func (d *Document) agent(id identity.Contract) string {
+	name := qualify("agent", id.Printable())
+	if _, exists := d.agents[name]; !exists {
+		d.agents[name] = attributes{Prefix + ":identityType": {literal: id.Kind()}}
+	}
+	return name
+}
+
+// metadata adds the attributes describing an annotation's metadata to a.
+func metadata(a attributes, m *annotation.Instance) {
+	a[Prefix+":metadata"] = value{literal: marshal(m.Metadata)}
+	if source, ok := m.Metadata.(provenance.Source); ok {
+		a[Prefix+":provenance"] = value{literal: marshal(source.GetProvenance())}
+	}
+
+	switch concrete := m.Metadata.(type) {
+	case *pkiMetadata.Instance:
+		a[Prefix+":signerType"] = value{literal: concrete.SignerKind}
+		a[Prefix+":publicKey"] = value{literal: base64.StdEncoding.EncodeToString(concrete.PublicKey)}
+		a[Prefix+":identitySignature"] = value{literal: base64.StdEncoding.EncodeToString(concrete.IdentitySignature)}
+		a[Prefix+":dataSignature"] = value{literal: base64.StdEncoding.EncodeToString(concrete.DataSignature)}
+	case *assessMetadata.Instance:
+		a[Prefix+":assessorType"] = value{literal: concrete.AssessorKind}
+		a[Prefix+":assessment"] = value{literal: marshal(concrete.AssessorMetadata)}
+	case *publishMetadata.Instance:
+		a[Prefix+":publisherType"] = value{literal: concrete.PublisherKind}
+		a[Prefix+":publication"] = value{literal: marshal(concrete.PublisherMetadata)}
+	}
+
+	if m.Signature != nil {
+		a[Prefix+":signature"] = value{literal: base64.StdEncoding.EncodeToString(m.Signature.Value)}
+		a[Prefix+":signaturePublicKey"] = value{literal: base64.StdEncoding.EncodeToString(m.Signature.PublicKey)}
+		a[Prefix+":signatureSignerType"] = value{literal: m.Signature.SignerKind}
+	}
+}
+
+// add records an annotation and its relations.
+func (d *Document) add(m *annotation.Instance) {
+	if m.CurrentIdentity == nil {
+		return
+	}
+
+	entity := d.entity(m.CurrentIdentity)
+	activity := qualify("annotation", m.Unique)
+	a := attributes{
+		"prov:type":              {literal: Prefix + ":" + m.MetadataKind, datatype: qualifiedName},
+		"prov:startTime":         {literal: m.Created, datatype: dateTime},
+		Prefix + ":unique":       {literal: m.Unique},
+		Prefix + ":metadataType": {literal: m.MetadataKind},
+	}
+	metadata(a, m)
+	d.activities[activity] = a
+
+	if d.once("used " + activity + " " + entity) {
+		d.used = append(d.used, relation{subject: activity, object: entity})
+	}
+	predecessors := m.Predecessors()
+	for p := range predecessors {
+		if predecessors[p] == nil || predecessors[p].Printable() == m.CurrentIdentity.Printable() {
+			continue
+		}
+		previous := d.entity(predecessors[p])
+		if d.once("derived " + entity + " " + previous + " " + activity) {
+			d.derivations = append(d.derivations, derivation{generated: entity, used: previous, activity: activity})
+		}
+	}
+	if m.Author != nil {
+		author := d.agent(m.Author)
+		if d.once("associated " + activity + " " + author) {
+			d.associations = append(d.associations, relation{subject: activity, object: author})
+		}
+	}
+	if m.Owner != nil {
+		owner := d.agent(m.Owner)
+		if d.once("attributed " + entity + " " + owner) {
+			d.attributions = append(d.attributions, relation{subject: entity, object: owner})
+		}
+	}
+}
+
+// provJSON returns v as a PROV-JSON attribute value.
+func (v value) provJSON() interface{} {
+	if v.datatype == "" || v.datatype == dateTime {
+		return v.literal
+	}
+	return map[string]string{"$": v.literal, "type": v.datatype}
+}
+
+// provJSON returns the attributes as a PROV-JSON element.
+func (a attributes) provJSON() map[string]interface{} {
+	result := make(map[string]interface{}, len(a))
+	for name, v := range a {
+		result[name] = v.provJSON()
+	}
+	return result
+}
+
+// JSON returns the document serialized as PROV-JSON (https://www.w3.org/Submission/prov-json/).
+func (d *Document) JSON() ([]byte, error) {
+	document := map[string]interface{}{
+		"prefix": map[string]string{Prefix: Namespace},
+	}
+
+	elements := func(kind string, elements map[string]attributes) {
+		if len(elements) == 0 {
+			return
+		}
+		result := make(map[string]interface{}, len(elements))
+		for name, a := range elements {
+			result[name] = a.provJSON()
+		}
+		document[kind] = result
+	}
+	elements("entity", d.entities)
+	elements("activity", d.activities)
+	elements("agent", d.agents)
+
+	relations := func(kind, subject, object string, relations []relation) {
+		if len(relations) == 0 {
+			return
+		}
+		result := make(map[string]interface{}, len(relations))
+		for r := range relations {
+			result[fmt.Sprintf("_:%s%d", kind, r+1)] = map[string]string{
+				subject: relations[r].subject,
+				object:  relations[r].object,
+			}
+		}
+		document[kind] = result
+	}
+	relations("used", "prov:activity", "prov:entity", d.used)
+	relations("wasAssociatedWith", "prov:activity", "prov:agent", d.associations)
+	relations("wasAttributedTo", "prov:entity", "prov:agent", d.attributions)
+
+	if len(d.derivations) > 0 {
+		result := make(map[string]interface{}, len(d.derivations))
+		for r := range d.derivations {
+			result[fmt.Sprintf("_:wasDerivedFrom%d", r+1)] = map[string]string{
+				"prov:generatedEntity": d.derivations[r].generated,
+				"prov:usedEntity":      d.derivations[r].used,
+				"prov:activity":        d.derivations[r].activity,
+			}
+		}
+		document["wasDerivedFrom"] = result
+	}
+
+	return json.Marshal(document)
+}
+
+// reference returns a JSON-LD node reference.
+func reference(id string) map[string]string {
+	return map[string]string{"@id": id}
+}
+
+// node returns the attributes as a JSON-LD node of type nodeType.
+func (a attributes) node(id, nodeType string) map[string]interface{} {
+	types := []string{nodeType}
+	result := map[string]interface{}{"@id": id}
+	for name, v := range a {
+		switch {
+		case name == "prov:type":
+			types = append(types, v.literal)
+		case name == "prov:startTime":
+			result["prov:startedAtTime"] = map[string]string{"@value": v.literal, "@type": v.datatype}
+		case v.datatype != "":
+			result[name] = map[string]string{"@value": v.literal, "@type": v.datatype}
+		default:
+			result[name] = v.literal
+		}
+	}
+	result["@type"] = types
+	return result
+}
+
+// JSONLD returns the document serialized as PROV-O (https://www.w3.org/TR/prov-o/) in JSON-LD.
+func (d *Document) JSONLD() ([]byte, error) {
+	nodes := make(map[string]map[string]interface{})
+	for id, a := range d.entities {
+		nodes[id] = a.node(id, "prov:Entity")
+	}
+	for id, a := range d.activities {
+		nodes[id] = a.node(id, "prov:Activity")
+	}
+	for id, a := range d.agents {
+		nodes[id] = a.node(id, "prov:Agent")
+	}
+
+	link := func(subject, property string, object interface{}) {
+		references, _ := nodes[subject][property].([]interface{})
+		nodes[subject][property] = append(references, object)
+	}
+	for r := range d.used {
+		link(d.used[r].subject, "prov:used", reference(d.used[r].object))
+	}
+	for r := range d.associations {
+		link(d.associations[r].subject, "prov:wasAssociatedWith", reference(d.associations[r].object))
+	}
+	for r := range d.attributions {
+		link(d.attributions[r].subject, "prov:wasAttributedTo", reference(d.attributions[r].object))
+	}
+	for r := range d.derivations {
+		generated := d.derivations[r].generated
+		link(generated, "prov:wasDerivedFrom", reference(d.derivations[r].used))
+		link(
+			generated,
+			"prov:qualifiedDerivation",
+			map[string]interface{}{
+				"@type":            "prov:Derivation",
+				"prov:entity":      reference(d.derivations[r].used),
+				"prov:hadActivity": reference(d.derivations[r].activity),
+			},
+		)
+	}
+
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	graph := make([]interface{}, len(ids))
+	for i := range ids {
+		graph[i] = nodes[ids[i]]
+	}
+
+	return json.Marshal(
+		map[string]interface{}{
+			"@context": map[string]string{
+				"prov": provNamespace,
+				"xsd":  xsdNamespace,
+				Prefix: Namespace,
+			},
+			"@graph": graph,
+		},
+	)
+}
@@ -0,0 +1,283 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package prov
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	pkiAssessorMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/metadata"
+	assessMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/metadata"
+	pkiMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	publishMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/metadata"
+	examplePublisherMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/example/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/identity/principal"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixture is a lineage of annotations: data created (id1), mutated (id2), assessed, and published.
+type fixture struct {
+	id1, id2                      identity.Contract
+	author, owner                 identity.Contract
+	created, mutated              *annotation.Instance
+	assessed, published           *annotation.Instance
+	provenance, publicKey, signed string
+}
+
+// newFixture returns a new fixture stored in a memory store.
+func newFixture(t *testing.T) (*fixture, *Document) {
+	f := &fixture{
+		id1:        identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32)),
+		id2:        identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32)),
+		author:     principal.New(test.FactoryRandomString()),
+		owner:      principal.New(test.FactoryRandomString()),
+		provenance: test.FactoryRandomString(),
+	}
+	signer := stub.New(test.FactoryRandomString(), test.FactoryRandomString())
+	publicKey := test.FactoryRandomByteSlice()
+	f.publicKey = base64.StdEncoding.EncodeToString(publicKey)
+
+	f.created = annotation.New(
+		test.FactoryRandomString(),
+		f.id1,
+		nil,
+		pkiMetadata.New(f.provenance, test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice(), publicKey, signer),
+	)
+	f.created.SetAuthor(f.author)
+	f.created.SetOwner(f.owner)
+	signature := test.FactoryRandomByteSlice()
+	f.signed = base64.StdEncoding.EncodeToString(signature)
+	f.created.Signature = &annotation.Signature{Value: signature, PublicKey: publicKey, SignerKind: signer.Kind()}
+	f.mutated = annotation.New(
+		test.FactoryRandomString(),
+		f.id2,
+		f.id1,
+		pkiMetadata.New(f.provenance, test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice(), publicKey, signer),
+	)
+	f.assessed = annotation.New(
+		test.FactoryRandomString(),
+		f.id2,
+		nil,
+		assessMetadata.New(f.provenance, pkiAssessorMetadata.NewSuccess(true, []string{f.created.Unique})),
+	)
+	f.published = annotation.New(
+		test.FactoryRandomString(),
+		f.id2,
+		nil,
+		publishMetadata.New(f.provenance, examplePublisherMetadata.NewSuccess()),
+	)
+
+	s := memory.New()
+	require.Equal(t, status.Success, s.Create(f.id1, f.created))
+	require.Equal(t, status.Success, s.Create(f.id2, f.mutated))
+	require.Equal(t, status.Success, s.Append(f.id2, f.assessed))
+	require.Equal(t, status.Success, s.Append(f.id2, f.published))
+
+	d, result := Export(s, f.id2)
+	require.Equal(t, status.Success, result)
+	return f, d
+}
+
+// name returns the qualified name of an element.
+func name(elementType, local string) string {
+	return Prefix + ":" + elementType + "/" + url.PathEscape(local)
+}
+
+// decode returns the JSON object encoded in data.
+func decode(t *testing.T, data []byte, err error) map[string]interface{} {
+	require.NoError(t, err)
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &result))
+	return result
+}
+
+// TestDocument_JSON tests Document.JSON.
+func TestDocument_JSON(t *testing.T) {
+	f, sut := newFixture(t)
+
+	data, err := sut.JSON()
+	result := decode(t, data, err)
+
+	assert.Equal(t, map[string]interface{}{Prefix: Namespace}, result["prefix"])
+	entities := result["entity"].(map[string]interface{})
+	assert.Equal(t, 2, len(entities))
+	assert.Equal(
+		t,
+		map[string]interface{}{Prefix + ":identityType": identityHash.Kind},
+		entities[name("identity", f.id1.Printable())],
+	)
+	activities := result["activity"].(map[string]interface{})
+	assert.Equal(t, 4, len(activities))
+
+	created := activities[name("annotation", f.created.Unique)].(map[string]interface{})
+	assert.Equal(
+		t,
+		map[string]interface{}{"$": Prefix + ":" + pkiMetadata.Kind, "type": "prov:QUALIFIED_NAME"},
+		created["prov:type"],
+	)
+	assert.Equal(t, f.created.Created, created["prov:startTime"])
+	assert.Equal(t, `"`+f.provenance+`"`, created[Prefix+":provenance"])
+	assert.Equal(t, f.publicKey, created[Prefix+":publicKey"])
+	assert.Equal(t, f.signed, created[Prefix+":signature"])
+
+	assessed := activities[name("annotation", f.assessed.Unique)].(map[string]interface{})
+	assert.Equal(t, pkiAssessorMetadata.Kind, assessed[Prefix+":assessorType"])
+	assert.Contains(t, assessed[Prefix+":assessment"], f.created.Unique)
+	published := activities[name("annotation", f.published.Unique)].(map[string]interface{})
+	assert.Equal(t, examplePublisherMetadata.Kind, published[Prefix+":publisherType"])
+
+	assert.Equal(t, 2, len(result["agent"].(map[string]interface{})))
+	assert.Equal(t, 4, len(result["used"].(map[string]interface{})))
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"_:wasDerivedFrom1": map[string]interface{}{
+				"prov:generatedEntity": name("identity", f.id2.Printable()),
+				"prov:usedEntity":      name("identity", f.id1.Printable()),
+				"prov:activity":        name("annotation", f.mutated.Unique),
+			},
+		},
+		result["wasDerivedFrom"],
+	)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"_:wasAssociatedWith1": map[string]interface{}{
+				"prov:activity": name("annotation", f.created.Unique),
+				"prov:agent":    name("agent", f.author.Printable()),
+			},
+		},
+		result["wasAssociatedWith"],
+	)
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"_:wasAttributedTo1": map[string]interface{}{
+				"prov:entity": name("identity", f.id1.Printable()),
+				"prov:agent":  name("agent", f.owner.Printable()),
+			},
+		},
+		result["wasAttributedTo"],
+	)
+
+	again, err := sut.JSON()
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+}
+
+// TestDocument_JSONLD tests Document.JSONLD.
+func TestDocument_JSONLD(t *testing.T) {
+	f, sut := newFixture(t)
+
+	data, err := sut.JSONLD()
+	result := decode(t, data, err)
+
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"prov": "http://www.w3.org/ns/prov#",
+			"xsd":  "http://www.w3.org/2001/XMLSchema#",
+			Prefix: Namespace,
+		},
+		result["@context"],
+	)
+	nodes := make(map[string]map[string]interface{})
+	for _, n := range result["@graph"].([]interface{}) {
+		node := n.(map[string]interface{})
+		nodes[node["@id"].(string)] = node
+	}
+	assert.Equal(t, 8, len(nodes))
+
+	id2 := nodes[name("identity", f.id2.Printable())]
+	assert.Equal(t, []interface{}{"prov:Entity"}, id2["@type"])
+	assert.Equal(
+		t,
+		[]interface{}{map[string]interface{}{"@id": name("identity", f.id1.Printable())}},
+		id2["prov:wasDerivedFrom"],
+	)
+	assert.Equal(
+		t,
+		[]interface{}{
+			map[string]interface{}{
+				"@type":            "prov:Derivation",
+				"prov:entity":      map[string]interface{}{"@id": name("identity", f.id1.Printable())},
+				"prov:hadActivity": map[string]interface{}{"@id": name("annotation", f.mutated.Unique)},
+			},
+		},
+		id2["prov:qualifiedDerivation"],
+	)
+	id1 := nodes[name("identity", f.id1.Printable())]
+	assert.Equal(
+		t,
+		[]interface{}{map[string]interface{}{"@id": name("agent", f.owner.Printable())}},
+		id1["prov:wasAttributedTo"],
+	)
+
+	created := nodes[name("annotation", f.created.Unique)]
+	assert.Equal(t, []interface{}{"prov:Activity", Prefix + ":" + pkiMetadata.Kind}, created["@type"])
+	assert.Equal(
+		t,
+		map[string]interface{}{"@value": f.created.Created, "@type": "xsd:dateTime"},
+		created["prov:startedAtTime"],
+	)
+	assert.Equal(
+		t,
+		[]interface{}{map[string]interface{}{"@id": name("identity", f.id1.Printable())}},
+		created["prov:used"],
+	)
+	assert.Equal(
+		t,
+		[]interface{}{map[string]interface{}{"@id": name("agent", f.author.Printable())}},
+		created["prov:wasAssociatedWith"],
+	)
+	assert.Equal(t, f.publicKey, created[Prefix+":publicKey"])
+
+	agent := nodes[name("agent", f.author.Printable())]
+	assert.Equal(t, []interface{}{"prov:Agent"}, agent["@type"])
+	assert.Equal(t, principal.Kind, agent[Prefix+":identityType"])
+
+	again, err := sut.JSONLD()
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+}
+
+// TestExport tests Export.
+func TestExport(t *testing.T) {
+	d, result := Export(memory.New(), identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32)))
+
+	assert.Nil(t, d)
+	assert.Equal(t, status.NotFound, result)
+}
+
+// TestNew tests New with no annotations.
+func TestNew(t *testing.T) {
+	sut := New(nil)
+
+	data, err := sut.JSON()
+	assert.Equal(t, map[string]interface{}{"prefix": map[string]interface{}{Prefix: Namespace}}, decode(t, data, err))
+	data, err = sut.JSONLD()
+	assert.Equal(t, []interface{}{}, decode(t, data, err)["@graph"])
+}
@@ -0,0 +1,235 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// registry implements a registry of the identity and metadata kinds known to this process.
+//
+// Packages that define identity or metadata kinds register a factory for each kind (typically from an init function
+// in their factory package).  Metadata kinds are registered within a scope because the kinds embedded within
+// metadata (for example, a signer's metadata within PKI metadata) are distinct from -- and may collide with --
+// annotation metadata kinds.  The factories returned by the registry decode every registered kind and preserve
+// unregistered kinds as opaque identities or metadata rather than discarding them.
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	opaqueMetadata "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+)
+
+const (
+	// Identity is the scope of identity kinds.
+	Identity = "identity"
+
+	// Metadata is the scope of annotation metadata kinds.
+	Metadata = "metadata"
+
+	// Signer is the scope of signer metadata kinds (embedded in PKI metadata and annotation signatures).
+	Signer = "signer"
+
+	// Assessor is the scope of assessor metadata kinds (embedded in assess metadata).
+	Assessor = "assessor"
+
+	// Publisher is the scope of publisher metadata kinds (embedded in publish metadata).
+	Publisher = "publisher"
+)
+
+// IdentityFactory defines the identity factory abstraction (see identity/factory.Contract).
+type IdentityFactory interface {
+	// Create returns a contract implementation based on the provided identity.
+	Create(kind string, data json.RawMessage) identity.Contract
+}
+
+// Contract defines the kind registry abstraction.
+type Contract interface {
+	// RegisterIdentity adds the factory that decodes identities of kind.
+	RegisterIdentity(kind string, factory IdentityFactory) error
+
+	// RegisterMetadata adds the factory that decodes metadata of kind within scope.
+	RegisterMetadata(scope, kind string, factory metadataFactory.Contract) error
+
+	// Kinds returns the kinds registered within scope in sorted order.
+	Kinds(scope string) []string
+
+	// IdentityFactory returns a factory that decodes every registered identity kind.
+	IdentityFactory() IdentityFactory
+
+	// MetadataFactory returns a factory that decodes every metadata kind registered within scopes, consulting the
+	// scopes in order.
+	MetadataFactory(scopes ...string) metadataFactory.Contract
+}
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	m          sync.RWMutex
+	identities map[string]IdentityFactory
+	metadata   map[string]map[string]metadataFactory.Contract
+}
+
+// New is a factory function that returns an initialized instance.
+func New() *instance {
+	return &instance{
+		identities: make(map[string]IdentityFactory),
+		metadata:   make(map[string]map[string]metadataFactory.Contract),
+	}
+}
+
+// RegisterIdentity adds the factory that decodes identities of kind.
+func (i *instance) RegisterIdentity(kind string, factory IdentityFactory) error {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if _, exists := i.identities[kind]; exists {
+		return fmt.Errorf("%s kind %q already registered", Identity, kind)
+	}
+	i.identities[kind] = factory
+	return nil
+}
+
+// RegisterMetadata adds the factory that decodes metadata of kind within scope.
+func (i *instance) RegisterMetadata(scope, kind string, factory metadataFactory.Contract) error {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if _, exists := i.metadata[scope][kind]; exists {
+		return fmt.Errorf("%s kind %q already registered", scope, kind)
+	}
+	if i.metadata[scope] == nil {
+		i.metadata[scope] = make(map[string]metadataFactory.Contract)
+	}
+	i.metadata[scope][kind] = factory
+	return nil
+}
+
+// Kinds returns the kinds registered within scope in sorted order.
+func (i *instance) Kinds(scope string) []string {
+	i.m.RLock()
+	defer i.m.RUnlock()
+
+	kinds := make([]string, 0)
+	if scope == Identity {
+		for kind := range i.identities {
+			kinds = append(kinds, kind)
+		}
+	}
+	for kind := range i.metadata[scope] {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// IdentityFactory returns a factory that decodes every registered identity kind.
+func (i *instance) IdentityFactory() IdentityFactory {
+	return &identityFactory{registry: i}
+}
+
+// MetadataFactory returns a factory that decodes every metadata kind registered within scopes.
+func (i *instance) MetadataFactory(scopes ...string) metadataFactory.Contract {
+	return &metadataFactoryView{registry: i, scopes: scopes}
+}
+
+// isNull returns whether data represents an absent value.
+func isNull(data json.RawMessage) bool {
+	return len(data) == 0 || string(data) == "null"
+}
+
+// identityFactory is a receiver that decodes identities using the factories registered when Create is called.
+type identityFactory struct {
+	registry *instance
+}
+
+// Create returns a contract implementation based on the provided identity; an unregistered kind (or an identity its
+// factory cannot decode) is returned as an opaque identity.
+func (f *identityFactory) Create(kind string, data json.RawMessage) identity.Contract {
+	if isNull(data) {
+		return nil
+	}
+
+	f.registry.m.RLock()
+	factory, exists := f.registry.identities[kind]
+	f.registry.m.RUnlock()
+
+	if exists {
+		if result := factory.Create(kind, data); result != nil {
+			return result
+		}
+	}
+	return opaqueIdentity.New(kind, data)
+}
+
+// metadataFactoryView is a receiver that decodes metadata using the factories registered within scopes when Create
+// is called.
+type metadataFactoryView struct {
+	registry *instance
+	scopes   []string
+}
+
+// Create returns a contract implementation based on the provided metadata; an unregistered kind (or metadata its
+// factory cannot decode) is returned as opaque metadata.
+func (f *metadataFactoryView) Create(kind string, data json.RawMessage) metadata.Contract {
+	if isNull(data) {
+		return nil
+	}
+
+	for s := range f.scopes {
+		f.registry.m.RLock()
+		factory, exists := f.registry.metadata[f.scopes[s]][kind]
+		f.registry.m.RUnlock()
+
+		if exists {
+			if result := factory.Create(kind, data); result != nil {
+				return result
+			}
+		}
+	}
+	return opaqueMetadata.New(kind, data)
+}
+
+// registry is the process-wide registry to which the SDK's identity and metadata kinds are registered.
+var registry = New()
+
+// RegisterIdentity adds an identity factory to the process-wide registry; it is intended to be called from init
+// functions.
+func RegisterIdentity(kind string, factory IdentityFactory) error {
+	return registry.RegisterIdentity(kind, factory)
+}
+
+// RegisterMetadata adds a metadata factory to the process-wide registry; it is intended to be called from init
+// functions.
+func RegisterMetadata(scope, kind string, factory metadataFactory.Contract) error {
+	return registry.RegisterMetadata(scope, kind, factory)
+}
+
+// Kinds returns the kinds registered within scope in the process-wide registry.
+func Kinds(scope string) []string {
+	return registry.Kinds(scope)
+}
+
+// NewIdentityFactory returns a factory that decodes every identity kind registered in the process-wide registry.
+func NewIdentityFactory() IdentityFactory {
+	return registry.IdentityFactory()
+}
+
+// NewMetadataFactory returns a factory that decodes every metadata kind registered within scopes in the process-wide
+// registry.
+func NewMetadataFactory(scopes ...string) metadataFactory.Contract {
+	return registry.MetadataFactory(scopes...)
+}
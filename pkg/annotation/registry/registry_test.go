@@ -0,0 +1,230 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package registry
+
+import (
+	"encoding/json"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	opaqueMetadata "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/opaque"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	metadataStubFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub/factory"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hashFactory is an identity factory that decodes hash identities.
+type hashFactory struct{}
+
+// Create returns a contract implementation based on the provided identity.
+func (hashFactory) Create(_ string, data json.RawMessage) identity.Contract {
+	var concrete identityHash.Identity
+	if err := json.Unmarshal(data, &concrete); err != nil {
+		return nil
+	}
+	return &concrete
+}
+
+// TestInstance_Register tests instance.RegisterIdentity, instance.RegisterMetadata, and instance.Kinds.
+func TestInstance_Register(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "kinds sorted by scope",
+			test: func(t *testing.T) {
+				sut := New()
+				require.NoError(t, sut.RegisterIdentity("b", hashFactory{}))
+				require.NoError(t, sut.RegisterIdentity("a", hashFactory{}))
+				require.NoError(t, sut.RegisterMetadata(Metadata, "d", metadataFactory.New(nil)))
+				require.NoError(t, sut.RegisterMetadata(Metadata, "c", metadataFactory.New(nil)))
+				require.NoError(t, sut.RegisterMetadata(Signer, "c", metadataFactory.New(nil)))
+
+				assert.Equal(t, []string{"a", "b"}, sut.Kinds(Identity))
+				assert.Equal(t, []string{"c", "d"}, sut.Kinds(Metadata))
+				assert.Equal(t, []string{"c"}, sut.Kinds(Signer))
+				assert.Equal(t, []string{}, sut.Kinds(Publisher))
+			},
+		},
+		{
+			name: "duplicate identity kind",
+			test: func(t *testing.T) {
+				sut := New()
+				require.NoError(t, sut.RegisterIdentity(identityHash.Kind, hashFactory{}))
+
+				assert.Error(t, sut.RegisterIdentity(identityHash.Kind, hashFactory{}))
+			},
+		},
+		{
+			name: "duplicate metadata kind within scope",
+			test: func(t *testing.T) {
+				sut := New()
+				require.NoError(t, sut.RegisterMetadata(Assessor, "kind", metadataFactory.New(nil)))
+
+				assert.Error(t, sut.RegisterMetadata(Assessor, "kind", metadataFactory.New(nil)))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_IdentityFactory tests instance.IdentityFactory.
+func TestInstance_IdentityFactory(t *testing.T) {
+	id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	data, err := json.Marshal(id)
+	require.NoError(t, err)
+
+	type testCase struct {
+		name     string
+		kind     string
+		data     json.RawMessage
+		expected interface{}
+	}
+
+	cases := []testCase{
+		{
+			name:     "registered kind",
+			kind:     identityHash.Kind,
+			data:     data,
+			expected: id,
+		},
+		{
+			name:     "unregistered kind",
+			kind:     "unregistered",
+			data:     data,
+			expected: opaqueIdentity.New("unregistered", data),
+		},
+		{
+			name:     "undecodable registered kind",
+			kind:     identityHash.Kind,
+			data:     json.RawMessage(`[]`),
+			expected: opaqueIdentity.New(identityHash.Kind, json.RawMessage(`[]`)),
+		},
+		{
+			name:     "null",
+			kind:     identityHash.Kind,
+			data:     json.RawMessage(`null`),
+			expected: nil,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := New()
+				require.NoError(t, sut.RegisterIdentity(identityHash.Kind, hashFactory{}))
+
+				result := sut.IdentityFactory().Create(cases[i].kind, cases[i].data)
+
+				if cases[i].expected == nil {
+					assert.Nil(t, result)
+					return
+				}
+				assert.IsType(t, cases[i].expected, result)
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expected), testInternal.Marshal(t, result))
+			},
+		)
+	}
+}
+
+// TestInstance_MetadataFactory tests instance.MetadataFactory.
+func TestInstance_MetadataFactory(t *testing.T) {
+	metadata := metadataStub.New("kind", test.FactoryRandomString())
+	signer := metadataStub.New("signer", test.FactoryRandomString())
+	data, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	signerData, err := json.Marshal(signer)
+	require.NoError(t, err)
+
+	type testCase struct {
+		name     string
+		scopes   []string
+		kind     string
+		data     json.RawMessage
+		expected interface{}
+	}
+
+	cases := []testCase{
+		{
+			name:     "registered kind",
+			scopes:   []string{Metadata},
+			kind:     metadata.Kind(),
+			data:     data,
+			expected: metadata,
+		},
+		{
+			name:     "registered kind in later scope",
+			scopes:   []string{Metadata, Signer},
+			kind:     signer.Kind(),
+			data:     signerData,
+			expected: signer,
+		},
+		{
+			name:     "kind registered in another scope",
+			scopes:   []string{Metadata},
+			kind:     signer.Kind(),
+			data:     signerData,
+			expected: opaqueMetadata.New(signer.Kind(), signerData),
+		},
+		{
+			name:     "unregistered kind",
+			scopes:   []string{Metadata, Signer},
+			kind:     "unregistered",
+			data:     data,
+			expected: opaqueMetadata.New("unregistered", data),
+		},
+		{
+			name:     "null",
+			scopes:   []string{Metadata},
+			kind:     metadata.Kind(),
+			data:     json.RawMessage(`null`),
+			expected: nil,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := New()
+				require.NoError(t, sut.RegisterMetadata(Metadata, metadata.Kind(), metadataStubFactory.New(metadata)))
+				require.NoError(t, sut.RegisterMetadata(Signer, signer.Kind(), metadataStubFactory.New(signer)))
+
+				result := sut.MetadataFactory(cases[i].scopes...).Create(cases[i].kind, cases[i].data)
+
+				if cases[i].expected == nil {
+					assert.Nil(t, result)
+					return
+				}
+				assert.IsType(t, cases[i].expected, result)
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expected), testInternal.Marshal(t, result))
+			},
+		)
+	}
+}
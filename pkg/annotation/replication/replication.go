@@ -0,0 +1,347 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// replication implements idempotent, order-independent replication of annotations between stores.
+package replication
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/datetime"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/replication/transport"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// Contract defines the store capabilities required to replicate annotations.
+type Contract interface {
+	store.Contract
+	query.Contract
+}
+
+// Report describes the result of synchronizing with a peer.
+type Report struct {
+	Pulled int `json:"pulled"`
+	Pushed int `json:"pushed"`
+}
+
+// entry defines the structure in which an annotation is exchanged with a peer; IdentityKind and Identity hold the
+// kind and JSON of the identity it is stored against.
+type entry struct {
+	IdentityKind string          `json:"identityType"`
+	Identity     json.RawMessage `json:"identity"`
+	Annotation   json.RawMessage `json:"annotation"`
+}
+
+// batchSize is the maximum number of annotations Sync fetches or sends in a single request.
+const batchSize = 100
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	m               sync.Mutex
+	store           Contract
+	identityFactory identityFactory.Contract
+	metadataFactory metadataFactory.Contract
+	index           map[string]time.Time
+}
+
+// New is a factory function that returns an initialized instance replicating the annotations held by store.
+//
+// The instance re-indexes the store's unique values on every call, so annotations written to store by other means
+// (e.g. imported from an archive or merged by another instance) are replicated whatever their created values.
+func New(
+	store Contract,
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract) *instance {
+
+	return &instance{
+		m:               sync.Mutex{},
+		store:           store,
+		identityFactory: identityFactory,
+		metadataFactory: metadataFactory,
+		index:           make(map[string]time.Time),
+	}
+}
+
+// created returns the annotation's created value (or the zero time if it cannot be parsed).
+func created(m *annotation.Instance) time.Time {
+	if t := datetime.TimeFromCreated(m.Created); t != nil {
+		return *t
+	}
+	return time.Time{}
+}
+
+// refresh rebuilds the index of the store's unique values and created values; it must be called with the lock held.
+func (i *instance) refresh() error {
+	annotations, result := query.All(i.store, query.Criteria{})
+	if result != status.Success {
+		return fmt.Errorf("store returned %d", result)
+	}
+
+	index := make(map[string]time.Time, len(annotations))
+	for a := range annotations {
+		index[annotations[a].Unique] = created(annotations[a])
+	}
+	i.index = index
+	return nil
+}
+
+// Uniques returns the unique values of every annotation held by the store.
+func (i *instance) Uniques() ([]string, error) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if err := i.refresh(); err != nil {
+		return nil, err
+	}
+
+	uniques := make([]string, 0, len(i.index))
+	for unique := range i.index {
+		uniques = append(uniques, unique)
+	}
+	sort.Strings(uniques)
+	return uniques, nil
+}
+
+// Fetch returns the store's annotations with the given unique values encoded as JSON (each with the identity it is
+// stored against; see query.Locate); unknown unique values are ignored.
+//
+// Only annotations created within the range spanned by the requested unique values are read from the store.
+func (i *instance) Fetch(uniques []string) ([]json.RawMessage, error) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if err := i.refresh(); err != nil {
+		return nil, err
+	}
+
+	var from, to time.Time
+	wanted := make(map[string]bool, len(uniques))
+	for u := range uniques {
+		t, exists := i.index[uniques[u]]
+		if !exists {
+			continue
+		}
+		if len(wanted) == 0 || t.Before(from) {
+			from = t
+		}
+		if len(wanted) == 0 || t.After(to) {
+			to = t
+		}
+		wanted[uniques[u]] = true
+	}
+	if len(wanted) == 0 {
+		return []json.RawMessage{}, nil
+	}
+
+	to = to.Add(time.Nanosecond)
+	found, result := query.All(i.store, query.Criteria{From: &from, To: &to})
+	if result != status.Success {
+		return nil, fmt.Errorf("store returned %d", result)
+	}
+
+	byUnique := make(map[string]*annotation.Instance, len(wanted))
+	selected := make([]*annotation.Instance, 0, len(wanted))
+	for a := range found {
+		if _, exists := byUnique[found[a].Unique]; wanted[found[a].Unique] && !exists {
+			byUnique[found[a].Unique] = found[a]
+			selected = append(selected, found[a])
+		}
+	}
+	storedAgainst, result := query.Locate(i.store, selected)
+	if result != status.Success {
+		return nil, fmt.Errorf("locating stored identities returned %d", result)
+	}
+
+	annotations := make([]json.RawMessage, 0, len(byUnique))
+	for u := range uniques {
+		m, exists := byUnique[uniques[u]]
+		if !exists {
+			continue
+		}
+		delete(byUnique, uniques[u])
+		data, err := i.encode(storedAgainst[m.Unique], m)
+		if err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, data)
+	}
+	return annotations, nil
+}
+
+// encode converts an annotation and the identity it is stored against into JSON.
+func (i *instance) encode(id identity.Contract, m *annotation.Instance) ([]byte, error) {
+	marshaledIdentity, err := json.Marshal(id)
+	if err != nil {
+		return nil, err
+	}
+	marshaledAnnotation, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(entry{IdentityKind: id.Kind(), Identity: marshaledIdentity, Annotation: marshaledAnnotation})
+}
+
+// decode converts JSON into an annotation and the identity it is stored against using the injected factories; the
+// identity is created as an opaque identity if the identity factory does not recognize its kind.
+func (i *instance) decode(data []byte) (identity.Contract, *annotation.Instance, error) {
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, nil, err
+	}
+	if e.IdentityKind == "" || len(e.Identity) == 0 {
+		return nil, nil, errors.New("missing identity")
+	}
+	id := i.identityFactory.Create(e.IdentityKind, e.Identity)
+	if id == nil {
+		id = opaqueIdentity.New(e.IdentityKind, e.Identity)
+	}
+
+	var m annotation.Instance
+	m.SetIdentityFactory(i.identityFactory)
+	m.SetMetadataFactory(i.metadataFactory)
+	if err := json.Unmarshal(e.Annotation, &m); err != nil {
+		return nil, nil, err
+	}
+	if m.Unique == "" || m.CurrentIdentity == nil || m.Metadata == nil {
+		return nil, nil, errors.New("incomplete annotation")
+	}
+	return id, &m, nil
+}
+
+// Send merges the given JSON-encoded annotations into the store and returns the number it did not already hold.
+//
+// Annotations are keyed by unique value, so merging the same annotation more than once has no effect; new
+// annotations are stored against the identity recorded with them (the identity they are stored against by the
+// sender) in created (then unique) order.
+func (i *instance) Send(annotations []json.RawMessage) (int, error) {
+	decoded := make([]*annotation.Instance, 0, len(annotations))
+	storedAgainst := make(map[string]identity.Contract, len(annotations))
+	for a := range annotations {
+		id, m, err := i.decode(annotations[a])
+		if err != nil {
+			return 0, err
+		}
+		decoded = append(decoded, m)
+		storedAgainst[m.Unique] = id
+	}
+
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if err := i.refresh(); err != nil {
+		return 0, err
+	}
+
+	page, _ := query.Apply(query.Criteria{}, decoded)
+	merged := 0
+	for _, m := range page.Annotations {
+		if _, exists := i.index[m.Unique]; exists {
+			continue
+		}
+
+		id := storedAgainst[m.Unique]
+		result := i.store.Append(id, m)
+		if result == status.NotFound {
+			result = i.store.Create(id, m)
+		}
+		if result != status.Success {
+			return merged, fmt.Errorf("store returned %d", result)
+		}
+		i.index[m.Unique] = created(m)
+		merged++
+	}
+	return merged, nil
+}
+
+// missing returns the unique values in from that are not in to.
+func missing(from, to []string) []string {
+	present := make(map[string]bool, len(to))
+	for u := range to {
+		present[to[u]] = true
+	}
+
+	result := make([]string, 0)
+	for u := range from {
+		if !present[from[u]] {
+			result = append(result, from[u])
+		}
+	}
+	return result
+}
+
+// batches splits uniques into consecutive batches of at most batchSize unique values.
+func batches(uniques []string) [][]string {
+	result := make([][]string, 0, (len(uniques)+batchSize-1)/batchSize)
+	for len(uniques) > batchSize {
+		result = append(result, uniques[:batchSize])
+		uniques = uniques[batchSize:]
+	}
+	if len(uniques) > 0 {
+		result = append(result, uniques)
+	}
+	return result
+}
+
+// Sync exchanges annotations with peer so that both hold the union of their annotations and returns a report;
+// annotations are fetched and sent in batches of at most batchSize.
+func (i *instance) Sync(peer transport.Contract) (*Report, error) {
+	local, err := i.Uniques()
+	if err != nil {
+		return nil, err
+	}
+	remote, err := peer.Uniques()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	pull := batches(missing(remote, local))
+	for b := range pull {
+		annotations, err := peer.Fetch(pull[b])
+		if err != nil {
+			return report, err
+		}
+		n, err := i.Send(annotations)
+		report.Pulled += n
+		if err != nil {
+			return report, err
+		}
+	}
+
+	push := batches(missing(local, remote))
+	for b := range push {
+		annotations, err := i.Fetch(push[b])
+		if err != nil {
+			return report, err
+		}
+		n, err := peer.Send(annotations)
+		report.Pushed += n
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,402 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package replication
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	metadataStubFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/replication/transport"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/replication/transport/inprocess"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/replication/transport/rest"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	pkiFactory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signerMetadata is the signer metadata embedded in every test annotation.
+var signerMetadata = metadataStub.NewNullObject()
+
+// newSUT returns a new system under test replicating store.
+func newSUT(store Contract) *instance {
+	return New(
+		store,
+		identityFactory.New(),
+		metadataFactory.New(
+			[]metadataFactory.Contract{
+				pkiFactory.New([]metadataFactory.Contract{metadataStubFactory.New(signerMetadata)}),
+			},
+		),
+	)
+}
+
+// newIdentity returns a new random identity.
+func newIdentity() identity.Contract {
+	return identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+}
+
+// newAnnotation returns a new annotation for the given identities.
+func newAnnotation(id, previous identity.Contract) *annotation.Instance {
+	return annotation.New(
+		test.FactoryRandomFixedLengthAlphanumericString(26),
+		id,
+		previous,
+		metadata.New(
+			test.FactoryRandomString(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			signerMetadata,
+		),
+	)
+}
+
+// marshal returns the JSON encoding of each annotation stored against its current identity.
+func marshal(t *testing.T, annotations ...*annotation.Instance) []json.RawMessage {
+	result := make([]json.RawMessage, len(annotations))
+	for a := range annotations {
+		result[a] = json.RawMessage(testInternal.Marshal(t, entry{
+			IdentityKind: annotations[a].CurrentIdentity.Kind(),
+			Identity:     json.RawMessage(testInternal.Marshal(t, annotations[a].CurrentIdentity)),
+			Annotation:   json.RawMessage(testInternal.Marshal(t, annotations[a])),
+		}))
+	}
+	return result
+}
+
+// uniques returns the sorted unique values of annotations.
+func uniques(annotations []*annotation.Instance) []string {
+	result := make([]string, len(annotations))
+	for a := range annotations {
+		result[a] = annotations[a].Unique
+	}
+	sort.Strings(result)
+	return result
+}
+
+// failingPeer is a transport whose operations fail.
+type failingPeer struct{}
+
+// Uniques returns an error.
+func (failingPeer) Uniques() ([]string, error) {
+	return nil, errors.New("unavailable")
+}
+
+// Fetch returns an error.
+func (failingPeer) Fetch(_ []string) ([]json.RawMessage, error) {
+	return nil, errors.New("unavailable")
+}
+
+// Send returns an error.
+func (failingPeer) Send(_ []json.RawMessage) (int, error) {
+	return 0, errors.New("unavailable")
+}
+
+// recordingPeer is a transport that records the number of unique values and annotations in each request.
+type recordingPeer struct {
+	transport.Contract
+	fetched []int
+	sent    []int
+}
+
+// Fetch records the number of unique values and delegates to the wrapped transport.
+func (r *recordingPeer) Fetch(uniques []string) ([]json.RawMessage, error) {
+	r.fetched = append(r.fetched, len(uniques))
+	return r.Contract.Fetch(uniques)
+}
+
+// Send records the number of annotations and delegates to the wrapped transport.
+func (r *recordingPeer) Send(annotations []json.RawMessage) (int, error) {
+	r.sent = append(r.sent, len(annotations))
+	return r.Contract.Send(annotations)
+}
+
+// TestInstance_Sync tests instance.Sync.
+func TestInstance_Sync(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "union of both stores",
+			test: func(t *testing.T) {
+				local, remote := memory.New(), memory.New()
+				id1, id2, id3 := newIdentity(), newIdentity(), newIdentity()
+				m1, m2, m3 := newAnnotation(id1, nil), newAnnotation(id2, id1), newAnnotation(id3, nil)
+				require.Equal(t, status.Success, local.Create(id1, m1))
+				require.Equal(t, status.Success, local.Create(id2, m2))
+				require.Equal(t, status.Success, remote.Create(id3, m3))
+				sut := newSUT(local)
+
+				report, err := sut.Sync(inprocess.New(newSUT(remote)))
+
+				assert.NoError(t, err)
+				assert.Equal(t, Report{Pulled: 1, Pushed: 2}, *report)
+				for _, s := range []Contract{local, remote} {
+					for _, id := range []identity.Contract{id1, id2, id3} {
+						expected, _ := local.FindByIdentity(id)
+						actual, result := s.FindByIdentity(id)
+						assert.Equal(t, status.Success, result)
+						assert.Equal(t, testInternal.Marshal(t, expected), testInternal.Marshal(t, actual))
+					}
+				}
+				lineage, _ := remote.FindByIdentity(id2)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m2, m1}), testInternal.Marshal(t, lineage))
+			},
+		},
+		{
+			name: "over HTTP",
+			test: func(t *testing.T) {
+				local, remote := memory.New(), memory.New()
+				id1, id2 := newIdentity(), newIdentity()
+				m1, m2, m3 := newAnnotation(id1, nil), newAnnotation(id1, id1), newAnnotation(id2, id1)
+				require.Equal(t, status.Success, local.Create(id1, m1))
+				require.Equal(t, status.Success, remote.Create(id1, m1))
+				require.Equal(t, status.Success, remote.Append(id1, m2))
+				require.Equal(t, status.Success, remote.Create(id2, m3))
+				server := httptest.NewServer(rest.NewHandler(newSUT(remote)))
+				defer server.Close()
+				sut := newSUT(local)
+
+				report, err := sut.Sync(rest.New(server.URL, server.Client()))
+
+				assert.NoError(t, err)
+				assert.Equal(t, Report{Pulled: 2, Pushed: 0}, *report)
+				lineage, result := local.FindByIdentity(id2)
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m3, m1, m2}), testInternal.Marshal(t, lineage))
+			},
+		},
+		{
+			name: "idempotent",
+			test: func(t *testing.T) {
+				local, remote := memory.New(), memory.New()
+				id := newIdentity()
+				require.Equal(t, status.Success, local.Create(id, newAnnotation(id, nil)))
+				require.Equal(t, status.Success, remote.Create(id, newAnnotation(id, nil)))
+				sut := newSUT(local)
+				peer := inprocess.New(newSUT(remote))
+				_, err := sut.Sync(peer)
+				require.NoError(t, err)
+
+				report, err := sut.Sync(peer)
+
+				assert.NoError(t, err)
+				assert.Equal(t, Report{}, *report)
+				all, _ := local.FindByIdentity(id)
+				assert.Equal(t, 2, len(all))
+			},
+		},
+		{
+			name: "order independent",
+			test: func(t *testing.T) {
+				id := newIdentity()
+				m1, m2, m3 := newAnnotation(id, nil), newAnnotation(id, id), newAnnotation(id, id)
+				forward, reverse := memory.New(), memory.New()
+				for _, m := range []*annotation.Instance{m1, m2, m3} {
+					_, err := newSUT(forward).Send(marshal(t, m))
+					require.NoError(t, err)
+				}
+				for _, m := range []*annotation.Instance{m3, m2, m1} {
+					_, err := newSUT(reverse).Send(marshal(t, m))
+					require.NoError(t, err)
+				}
+
+				f, _ := forward.FindByIdentity(id)
+				r, _ := reverse.FindByIdentity(id)
+				assert.Equal(t, uniques([]*annotation.Instance{m1, m2, m3}), uniques(f))
+				assert.Equal(t, uniques(f), uniques(r))
+			},
+		},
+		{
+			name: "peer failure",
+			test: func(t *testing.T) {
+				sut := newSUT(memory.New())
+
+				report, err := sut.Sync(failingPeer{})
+
+				assert.Error(t, err)
+				assert.Nil(t, report)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_Send tests instance.Send.
+func TestInstance_Send(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "duplicate ignored",
+			test: func(t *testing.T) {
+				s := memory.New()
+				id := newIdentity()
+				m := newAnnotation(id, nil)
+				sut := newSUT(s)
+
+				first, err1 := sut.Send(marshal(t, m, m))
+				second, err2 := sut.Send(marshal(t, m))
+
+				assert.NoError(t, err1)
+				assert.NoError(t, err2)
+				assert.Equal(t, 1, first)
+				assert.Equal(t, 0, second)
+				stored, _ := s.FindByIdentity(id)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m}), testInternal.Marshal(t, stored))
+			},
+		},
+		{
+			name: "missing identity",
+			test: func(t *testing.T) {
+				s := memory.New()
+				id := newIdentity()
+				m := newAnnotation(id, nil)
+				sut := newSUT(s)
+
+				merged, err := sut.Send(
+					[]json.RawMessage{json.RawMessage(testInternal.Marshal(t, entry{Annotation: marshal(t, m)[0]}))},
+				)
+
+				assert.Error(t, err)
+				assert.Equal(t, 0, merged)
+				_, result := s.FindByIdentity(id)
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+		{
+			name: "invalid annotation",
+			test: func(t *testing.T) {
+				s := memory.New()
+				id := newIdentity()
+				m := newAnnotation(id, nil)
+				sut := newSUT(s)
+
+				merged, err := sut.Send(append(marshal(t, m), json.RawMessage(`{"unique":"x"}`)))
+
+				assert.Error(t, err)
+				assert.Equal(t, 0, merged)
+				_, result := s.FindByIdentity(id)
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_Fetch tests instance.Fetch.
+func TestInstance_Fetch(t *testing.T) {
+	s := memory.New()
+	id := newIdentity()
+	m := newAnnotation(id, nil)
+	require.Equal(t, status.Success, s.Create(id, m))
+	sut := newSUT(s)
+
+	annotations, err := sut.Fetch([]string{test.FactoryRandomString(), m.Unique})
+
+	assert.NoError(t, err)
+	assert.Equal(t, testInternal.Marshal(t, marshal(t, m)), testInternal.Marshal(t, annotations))
+}
+
+// TestInstance_StoredAgainst tests that annotations are replicated against the identity they are stored against
+// rather than their current identity.
+func TestInstance_StoredAgainst(t *testing.T) {
+	local, remote := memory.New(), memory.New()
+	previous, current := newIdentity(), newIdentity()
+	require.Equal(t, status.Success, remote.Create(previous, newAnnotation(previous, nil)))
+	require.Equal(t, status.Success, remote.Append(previous, newAnnotation(current, previous)))
+
+	report, err := newSUT(local).Sync(inprocess.New(newSUT(remote)))
+
+	require.NoError(t, err)
+	assert.Equal(t, Report{Pulled: 2}, *report)
+	expected, _ := remote.FindByIdentity(previous)
+	actual, result := local.FindByIdentity(previous)
+	assert.Equal(t, status.Success, result)
+	assert.Equal(t, testInternal.Marshal(t, expected), testInternal.Marshal(t, actual))
+	_, result = local.FindByIdentity(current)
+	assert.Equal(t, status.NotFound, result)
+}
+
+// TestInstance_OlderCreated tests that annotations written to the store after it was indexed are replicated whatever
+// their created values.
+func TestInstance_OlderCreated(t *testing.T) {
+	local, remote := memory.New(), memory.New()
+	id := newIdentity()
+	first := newAnnotation(id, nil)
+	require.Equal(t, status.Success, local.Create(id, first))
+	sut := newSUT(local)
+	_, err := sut.Uniques()
+	require.NoError(t, err)
+
+	imported := newAnnotation(id, nil)
+	imported.Created = time.Now().Add(-24 * time.Hour).Format(time.RFC3339Nano)
+	require.Equal(t, status.Success, local.Append(id, imported))
+	report, err := sut.Sync(inprocess.New(newSUT(remote)))
+
+	require.NoError(t, err)
+	assert.Equal(t, Report{Pushed: 2}, *report)
+	result, err := newSUT(remote).Uniques()
+	require.NoError(t, err)
+	assert.Equal(t, uniques([]*annotation.Instance{first, imported}), result)
+}
+
+// TestInstance_SyncBatches tests that Sync fetches and sends annotations in batches of at most batchSize.
+func TestInstance_SyncBatches(t *testing.T) {
+	local, remote := memory.New(), memory.New()
+	for n := 0; n < batchSize+1; n++ {
+		id := newIdentity()
+		require.Equal(t, status.Success, local.Create(id, newAnnotation(id, nil)))
+		id = newIdentity()
+		require.Equal(t, status.Success, remote.Create(id, newAnnotation(id, nil)))
+	}
+	peer := &recordingPeer{Contract: inprocess.New(newSUT(remote))}
+
+	report, err := newSUT(local).Sync(peer)
+
+	require.NoError(t, err)
+	assert.Equal(t, Report{Pulled: batchSize + 1, Pushed: batchSize + 1}, *report)
+	assert.Equal(t, []int{batchSize, 1}, peer.fetched)
+	assert.Equal(t, []int{batchSize, 1}, peer.sent)
+}
@@ -0,0 +1,31 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package transport
+
+import "encoding/json"
+
+// Contract defines the replication transport abstraction used to exchange annotations with a peer.
+type Contract interface {
+	// Uniques returns the unique values of every annotation held by the peer.
+	Uniques() ([]string, error)
+
+	// Fetch returns the peer's annotations with the given unique values encoded as JSON (each with the identity it
+	// is stored against); unknown unique values are ignored.
+	Fetch(uniques []string) ([]json.RawMessage, error)
+
+	// Send merges the given JSON-encoded annotations (each with the identity it is stored against) into the peer and
+	// returns the number it did not already hold.
+	Send(annotations []json.RawMessage) (int, error)
+}
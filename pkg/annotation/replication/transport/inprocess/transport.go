@@ -0,0 +1,70 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// inprocess implements a replication transport to a peer within the same process.
+package inprocess
+
+import (
+	"encoding/json"
+
+	replicationTransport "github.com/project-alvarium/go-sdk/pkg/annotation/replication/transport"
+)
+
+// transport is a receiver that encapsulates required dependencies.
+type transport struct {
+	peer replicationTransport.Contract
+}
+
+// New is a factory function that returns an initialized transport to peer.
+func New(peer replicationTransport.Contract) *transport {
+	return &transport{
+		peer: peer,
+	}
+}
+
+// clone returns a deep copy of annotations so neither side retains the other's buffers.
+func clone(annotations []json.RawMessage) []json.RawMessage {
+	if annotations == nil {
+		return nil
+	}
+
+	result := make([]json.RawMessage, len(annotations))
+	for a := range annotations {
+		result[a] = append(json.RawMessage(nil), annotations[a]...)
+	}
+	return result
+}
+
+// Uniques returns the unique values of every annotation held by the peer.
+func (t *transport) Uniques() ([]string, error) {
+	uniques, err := t.peer.Uniques()
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), uniques...), nil
+}
+
+// Fetch returns the peer's annotations with the given unique values encoded as JSON.
+func (t *transport) Fetch(uniques []string) ([]json.RawMessage, error) {
+	annotations, err := t.peer.Fetch(append([]string(nil), uniques...))
+	if err != nil {
+		return nil, err
+	}
+	return clone(annotations), nil
+}
+
+// Send merges the given JSON-encoded annotations into the peer and returns the number it did not already hold.
+func (t *transport) Send(annotations []json.RawMessage) (int, error) {
+	return t.peer.Send(clone(annotations))
+}
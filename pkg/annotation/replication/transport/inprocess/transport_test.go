@@ -0,0 +1,118 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package inprocess
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// peer is a transport that records and returns fixed values.
+type peer struct {
+	uniques     []string
+	annotations []json.RawMessage
+	sent        []json.RawMessage
+	err         error
+}
+
+// Uniques returns the peer's unique values.
+func (p *peer) Uniques() ([]string, error) {
+	return p.uniques, p.err
+}
+
+// Fetch returns the peer's annotations.
+func (p *peer) Fetch(_ []string) ([]json.RawMessage, error) {
+	return p.annotations, p.err
+}
+
+// Send records annotations.
+func (p *peer) Send(annotations []json.RawMessage) (int, error) {
+	p.sent = annotations
+	return len(annotations), p.err
+}
+
+// TestTransport tests transport.
+func TestTransport(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "delegates to peer",
+			test: func(t *testing.T) {
+				p := &peer{
+					uniques:     []string{test.FactoryRandomString()},
+					annotations: []json.RawMessage{json.RawMessage(`{"unique":"a"}`)},
+				}
+				sut := New(p)
+
+				uniques, err1 := sut.Uniques()
+				annotations, err2 := sut.Fetch(p.uniques)
+				merged, err3 := sut.Send(p.annotations)
+
+				assert.NoError(t, err1)
+				assert.NoError(t, err2)
+				assert.NoError(t, err3)
+				assert.Equal(t, p.uniques, uniques)
+				assert.Equal(t, p.annotations, annotations)
+				assert.Equal(t, p.annotations, p.sent)
+				assert.Equal(t, 1, merged)
+			},
+		},
+		{
+			name: "does not share buffers",
+			test: func(t *testing.T) {
+				p := &peer{annotations: []json.RawMessage{json.RawMessage(`{"unique":"a"}`)}}
+				sut := New(p)
+				sent := []json.RawMessage{json.RawMessage(`{"unique":"b"}`)}
+
+				annotations, _ := sut.Fetch(nil)
+				_, _ = sut.Send(sent)
+				annotations[0][0] = ' '
+				sent[0][0] = ' '
+
+				assert.Equal(t, `{"unique":"a"}`, string(p.annotations[0]))
+				assert.Equal(t, `{"unique":"b"}`, string(p.sent[0]))
+			},
+		},
+		{
+			name: "peer failure",
+			test: func(t *testing.T) {
+				sut := New(&peer{err: errors.New("unavailable")})
+
+				uniques, err1 := sut.Uniques()
+				annotations, err2 := sut.Fetch(nil)
+				_, err3 := sut.Send(nil)
+
+				assert.Error(t, err1)
+				assert.Error(t, err2)
+				assert.Error(t, err3)
+				assert.Nil(t, uniques)
+				assert.Nil(t, annotations)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
@@ -0,0 +1,143 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	replicationTransport "github.com/project-alvarium/go-sdk/pkg/annotation/replication/transport"
+)
+
+const (
+	// UniquesPath returns the unique values held by the peer (GET).
+	UniquesPath = "/uniques"
+
+	// FetchPath returns the peer's annotations for a JSON array of unique values (POST).
+	FetchPath = "/fetch"
+
+	// SendPath merges a JSON array of annotations into the peer (POST).
+	SendPath = "/send"
+)
+
+// MaxRequestSize is the maximum size in bytes of a request body accepted by the handler returned by NewHandler.
+const MaxRequestSize = 16 << 20
+
+// sendResponse is the response body of SendPath.
+type sendResponse struct {
+	Merged int `json:"merged"`
+}
+
+// handler is a receiver that encapsulates required dependencies.
+type handler struct {
+	peer  replicationTransport.Contract
+	limit int64
+}
+
+// NewHandler is a factory function that returns an http.Handler serving the replication endpoints for peer; request
+// bodies larger than MaxRequestSize are rejected.
+func NewHandler(peer replicationTransport.Contract) http.Handler {
+	return newHandler(peer, MaxRequestSize)
+}
+
+// newHandler returns an http.Handler serving the replication endpoints for peer that rejects request bodies larger
+// than limit bytes.
+func newHandler(peer replicationTransport.Contract, limit int64) http.Handler {
+	h := &handler{
+		peer:  peer,
+		limit: limit,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(UniquesPath, h.uniques)
+	mux.HandleFunc(FetchPath, h.fetch)
+	mux.HandleFunc(SendPath, h.send)
+	return mux
+}
+
+// respond writes value as a JSON response body.
+func respond(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// decode decodes the JSON request body (of at most h.limit bytes) into v; if it cannot, it writes an error response
+// and returns false.
+func (h *handler) decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.ContentLength > h.limit {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return false
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.limit)).Decode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// uniques serves UniquesPath.
+func (h *handler) uniques(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	uniques, err := h.peer.Uniques()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respond(w, uniques)
+}
+
+// fetch serves FetchPath.
+func (h *handler) fetch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var uniques []string
+	if !h.decode(w, r, &uniques) {
+		return
+	}
+
+	annotations, err := h.peer.Fetch(uniques)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respond(w, annotations)
+}
+
+// send serves SendPath.
+func (h *handler) send(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var annotations []json.RawMessage
+	if !h.decode(w, r, &annotations) {
+		return
+	}
+
+	merged, err := h.peer.Send(annotations)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	respond(w, sendResponse{Merged: merged})
+}
@@ -0,0 +1,104 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// rest implements a replication transport to a peer over HTTP, and the handler that serves a peer.
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// transport is a receiver that encapsulates required dependencies.
+type transport struct {
+	url    string
+	client *http.Client
+}
+
+// New is a factory function that returns an initialized transport to the peer served at url; a nil client uses
+// http.DefaultClient.
+func New(url string, client *http.Client) *transport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &transport{
+		url:    strings.TrimSuffix(url, "/"),
+		client: client,
+	}
+}
+
+// do sends a request to the peer and decodes its JSON response body into result.
+func (t *transport) do(method, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequest(method, t.url+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		message, _ := ioutil.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, path, response.Status, strings.TrimSpace(string(message)))
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+// Uniques returns the unique values of every annotation held by the peer.
+func (t *transport) Uniques() ([]string, error) {
+	var uniques []string
+	if err := t.do(http.MethodGet, UniquesPath, nil, &uniques); err != nil {
+		return nil, err
+	}
+	return uniques, nil
+}
+
+// Fetch returns the peer's annotations with the given unique values encoded as JSON.
+func (t *transport) Fetch(uniques []string) ([]json.RawMessage, error) {
+	var annotations []json.RawMessage
+	if err := t.do(http.MethodPost, FetchPath, uniques, &annotations); err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
+
+// Send merges the given JSON-encoded annotations into the peer and returns the number it did not already hold.
+func (t *transport) Send(annotations []json.RawMessage) (int, error) {
+	var response sendResponse
+	if err := t.do(http.MethodPost, SendPath, annotations, &response); err != nil {
+		return 0, err
+	}
+	return response.Merged, nil
+}
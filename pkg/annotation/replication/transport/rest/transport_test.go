@@ -0,0 +1,259 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// peer is a transport that records and returns fixed values.
+type peer struct {
+	uniques     []string
+	annotations []json.RawMessage
+	requested   []string
+	sent        []json.RawMessage
+	err         error
+}
+
+// Uniques returns the peer's unique values.
+func (p *peer) Uniques() ([]string, error) {
+	return p.uniques, p.err
+}
+
+// Fetch records uniques and returns the peer's annotations.
+func (p *peer) Fetch(uniques []string) ([]json.RawMessage, error) {
+	p.requested = uniques
+	return p.annotations, p.err
+}
+
+// Send records annotations.
+func (p *peer) Send(annotations []json.RawMessage) (int, error) {
+	p.sent = annotations
+	return len(annotations), p.err
+}
+
+// newSUT returns a new system under test connected to a server for p, and a function to stop the server.
+func newSUT(p *peer) (*transport, func()) {
+	server := httptest.NewServer(NewHandler(p))
+	return New(server.URL+"/", server.Client()), server.Close
+}
+
+// TestTransport tests transport.
+func TestTransport(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "uniques",
+			test: func(t *testing.T) {
+				p := &peer{uniques: []string{test.FactoryRandomString(), test.FactoryRandomString()}}
+				sut, closer := newSUT(p)
+				defer closer()
+
+				uniques, err := sut.Uniques()
+
+				assert.NoError(t, err)
+				assert.Equal(t, p.uniques, uniques)
+			},
+		},
+		{
+			name: "fetch",
+			test: func(t *testing.T) {
+				p := &peer{annotations: []json.RawMessage{json.RawMessage(`{"unique":"a"}`)}}
+				sut, closer := newSUT(p)
+				defer closer()
+				requested := []string{test.FactoryRandomString()}
+
+				annotations, err := sut.Fetch(requested)
+
+				assert.NoError(t, err)
+				assert.Equal(t, requested, p.requested)
+				assert.Equal(t, p.annotations, annotations)
+			},
+		},
+		{
+			name: "send",
+			test: func(t *testing.T) {
+				p := &peer{}
+				sut, closer := newSUT(p)
+				defer closer()
+				sent := []json.RawMessage{json.RawMessage(`{"unique":"a"}`), json.RawMessage(`{"unique":"b"}`)}
+
+				merged, err := sut.Send(sent)
+
+				assert.NoError(t, err)
+				assert.Equal(t, 2, merged)
+				assert.Equal(t, sent, p.sent)
+			},
+		},
+		{
+			name: "peer failure",
+			test: func(t *testing.T) {
+				p := &peer{err: errors.New("unavailable")}
+				sut, closer := newSUT(p)
+				defer closer()
+
+				uniques, err1 := sut.Uniques()
+				annotations, err2 := sut.Fetch(nil)
+				merged, err3 := sut.Send(nil)
+
+				assert.Error(t, err1)
+				assert.Error(t, err2)
+				assert.Error(t, err3)
+				assert.True(t, strings.Contains(err1.Error(), "unavailable"))
+				assert.Nil(t, uniques)
+				assert.Nil(t, annotations)
+				assert.Equal(t, 0, merged)
+			},
+		},
+		{
+			name: "unreachable peer",
+			test: func(t *testing.T) {
+				sut, closer := newSUT(&peer{})
+				closer()
+
+				_, err := sut.Uniques()
+
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestHandler tests NewHandler's handling of invalid requests.
+func TestHandler(t *testing.T) {
+	type testCase struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}
+
+	cases := []testCase{
+		{
+			name:           "uniques with wrong method",
+			method:         http.MethodPost,
+			path:           UniquesPath,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "fetch with wrong method",
+			method:         http.MethodGet,
+			path:           FetchPath,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "send with wrong method",
+			method:         http.MethodGet,
+			path:           SendPath,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "fetch with invalid body",
+			method:         http.MethodPost,
+			path:           FetchPath,
+			body:           test.FactoryRandomString(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "send with invalid body",
+			method:         http.MethodPost,
+			path:           SendPath,
+			body:           "{",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown path",
+			method:         http.MethodGet,
+			path:           "/" + test.FactoryRandomFixedLengthAlphanumericString(8),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := NewHandler(&peer{})
+				request := httptest.NewRequest(cases[i].method, cases[i].path, strings.NewReader(cases[i].body))
+				recorder := httptest.NewRecorder()
+
+				sut.ServeHTTP(recorder, request)
+
+				require.Equal(t, cases[i].expectedStatus, recorder.Code)
+			},
+		)
+	}
+}
+
+// TestHandler_RequestSize tests that the handler rejects request bodies larger than its limit.
+func TestHandler_RequestSize(t *testing.T) {
+	type testCase struct {
+		name          string
+		path          string
+		contentLength bool
+		expectedCode  int
+	}
+
+	cases := []testCase{
+		{name: "fetch declared length", path: FetchPath, contentLength: true, expectedCode: http.StatusRequestEntityTooLarge},
+		{name: "fetch undeclared length", path: FetchPath, contentLength: false, expectedCode: http.StatusBadRequest},
+		{name: "send declared length", path: SendPath, contentLength: true, expectedCode: http.StatusRequestEntityTooLarge},
+		{name: "send undeclared length", path: SendPath, contentLength: false, expectedCode: http.StatusBadRequest},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				p := &peer{}
+				sut := newHandler(p, 16)
+				request := httptest.NewRequest(
+					http.MethodPost,
+					cases[i].path,
+					strings.NewReader(`["`+strings.Repeat("A", 32)+`"]`),
+				)
+				if !cases[i].contentLength {
+					request.ContentLength = -1
+				}
+				recorder := httptest.NewRecorder()
+
+				sut.ServeHTTP(recorder, request)
+
+				assert.Equal(t, cases[i].expectedCode, recorder.Code)
+				assert.Nil(t, p.requested)
+				assert.Nil(t, p.sent)
+			},
+		)
+	}
+}
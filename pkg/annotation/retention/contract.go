@@ -0,0 +1,37 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package retention
+
+import (
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// Contract defines the optional store capability required to apply retention policies.
+type Contract interface {
+	store.Contract
+	query.Contract
+
+	// Identities returns the identities annotations are stored against and status.
+	Identities() ([]identity.Contract, status.Value)
+
+	// Replace replaces the annotations stored directly against identity and returns status; it returns
+	// status.Conflict without changing the store unless the unique values of the annotations stored directly against
+	// identity are exactly expected, so annotations stored since they were read are never lost.
+	Replace(id identity.Contract, expected []string, m []*annotation.Instance) status.Value
+}
@@ -0,0 +1,73 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package factory
+
+import (
+	"encoding/json"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/registry"
+	retentionMetadata "github.com/project-alvarium/go-sdk/pkg/annotation/retention/metadata"
+	signpkcs1v15Factory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15/metadata/factory"
+	signtpmv2Factory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signtpmv2/metadata/factory"
+)
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	signerFactories []factory.Contract
+}
+
+// New is a factory function that returns an initialized instance.
+func New(signerFactories []factory.Contract) *instance {
+	return &instance{
+		signerFactories: signerFactories,
+	}
+}
+
+// NewDefault is a factory function that returns an instance initialized with the SDK's signer metadata factories.
+func NewDefault() *instance {
+	return New(
+		[]factory.Contract{
+			signpkcs1v15Factory.New(),
+			signtpmv2Factory.New(),
+		},
+	)
+}
+
+// init registers the retention metadata kind; its signer metadata is decoded with the registered signer kinds.
+func init() {
+	f := New([]factory.Contract{registry.NewMetadataFactory(registry.Signer)})
+	if err := registry.RegisterMetadata(registry.Metadata, retentionMetadata.Kind, f); err != nil {
+		panic(err)
+	}
+}
+
+// Create returns a contract implementation based on the provided metadata.
+func (i *instance) Create(kind string, data json.RawMessage) metadata.Contract {
+	switch kind {
+	case retentionMetadata.Kind:
+		if string(data) == "null" {
+			return nil
+		}
+
+		var concrete retentionMetadata.Instance
+		concrete.SetSignerFactories(i.signerFactories)
+		if err := json.Unmarshal(data, &concrete); err == nil {
+			return &concrete
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/verifier"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer"
+	"github.com/project-alvarium/go-sdk/pkg/canonical"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+)
+
+const Kind = "retention"
+
+// Instance is the metadata of a summary annotation stored in place of compacted annotations.
+type Instance struct {
+	Removed           []string          `json:"removed"`
+	Kinds             []string          `json:"kinds"`
+	From              string            `json:"from"`
+	To                string            `json:"to"`
+	IdentitySignature []byte            `json:"identitySignature"`
+	DataSignature     []byte            `json:"dataSignature"`
+	PublicKey         []byte            `json:"publicKey"`
+	SignerKind        string            `json:"signerType"`
+	SignerMetadata    metadata.Contract `json:"signerMetadata"`
+
+	signerFactories []metadataFactory.Contract
+}
+
+// New is a factory function that returns an unsigned Instance summarizing the removed annotations' unique values,
+// distinct metadata kinds, and the created values of the oldest and newest of them.
+func New(removed []string, kinds []string, from, to string) *Instance {
+	return &Instance{
+		Removed: removed,
+		Kinds:   kinds,
+		From:    from,
+		To:      to,
+	}
+}
+
+// Kind returns the type of concrete implementation.
+func (*Instance) Kind() string {
+	return Kind
+}
+
+// SignedData returns the summary content (as canonical JSON) covered by the data signature.
+func (i *Instance) SignedData() []byte {
+	data, _ := canonical.Marshal(
+		struct {
+			Removed []string `json:"removed"`
+			Kinds   []string `json:"kinds"`
+			From    string   `json:"from"`
+			To      string   `json:"to"`
+		}{
+			Removed: i.Removed,
+			Kinds:   i.Kinds,
+			From:    i.From,
+			To:      i.To,
+		},
+	)
+	return data
+}
+
+// Sign signs the summary on behalf of the identity it is stored against.
+func (i *Instance) Sign(id identity.Contract, signer signer.Contract) {
+	i.IdentitySignature, i.DataSignature = signer.Sign(id.Binary(), i.SignedData())
+	i.PublicKey = signer.PublicKey()
+	i.SignerMetadata = signer.Metadata()
+	if i.SignerMetadata != nil {
+		i.SignerKind = i.SignerMetadata.Kind()
+	}
+}
+
+// Verify returns whether the summary's signatures are valid for the identity it is stored against.
+func (i *Instance) Verify(id identity.Contract, verifier verifier.Contract) bool {
+	return verifier != nil &&
+		verifier.VerifyIdentity(id.Binary(), i.IdentitySignature, i.PublicKey) &&
+		verifier.VerifyData(i.SignedData(), i.DataSignature, i.PublicKey)
+}
+
+// SetSignerFactories provides for method injection of required factory to unmarshal metadata JSON.
+func (i *Instance) SetSignerFactories(signerFactories []metadataFactory.Contract) {
+	i.signerFactories = signerFactories
+}
+
+// UnmarshalJSON converts JSON into appropriate contract implementations.
+func (i *Instance) UnmarshalJSON(data []byte) error {
+	if i.signerFactories == nil {
+		return errors.New("uninitialized signer factories")
+	}
+
+	type instance struct {
+		Removed           []string        `json:"removed"`
+		Kinds             []string        `json:"kinds"`
+		From              string          `json:"from"`
+		To                string          `json:"to"`
+		IdentitySignature []byte          `json:"identitySignature"`
+		DataSignature     []byte          `json:"dataSignature"`
+		PublicKey         []byte          `json:"publicKey"`
+		SignerKind        string          `json:"signerType"`
+		SignerMetadata    json.RawMessage `json:"signerMetadata"`
+	}
+
+	var value instance
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	i.Removed = value.Removed
+	i.Kinds = value.Kinds
+	i.From = value.From
+	i.To = value.To
+	i.IdentitySignature = value.IdentitySignature
+	i.DataSignature = value.DataSignature
+	i.PublicKey = value.PublicKey
+	i.SignerKind = value.SignerKind
+
+	i.SignerMetadata = opaque.Create(i.signerFactories, value.SignerKind, value.SignerMetadata)
+
+	return nil
+}
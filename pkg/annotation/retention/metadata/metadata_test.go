@@ -0,0 +1,126 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metadata
+
+import (
+	"crypto"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/factory/verifier"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newSUT returns a new system under test.
+func newSUT() *Instance {
+	return New(
+		[]string{test.FactoryRandomString(), test.FactoryRandomString()},
+		[]string{test.FactoryRandomString()},
+		test.FactoryRandomString(),
+		test.FactoryRandomString(),
+	)
+}
+
+// TestInstance_Kind tests instance.Kind.
+func TestInstance_Kind(t *testing.T) {
+	sut := newSUT()
+
+	assert.Equal(t, Kind, sut.Kind())
+}
+
+// TestInstance_Verify tests instance.Verify.
+func TestInstance_Verify(t *testing.T) {
+	h := sha256.New()
+	signer := signpkcs1v15.New(crypto.SHA256, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, h)
+
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "valid",
+			test: func(t *testing.T) {
+				id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				sut := newSUT()
+				sut.Sign(id, signer)
+
+				result := sut.Verify(id, verifier.New().Create(sut.SignerMetadata))
+
+				assert.True(t, result)
+				assert.Equal(t, signer.Metadata().Kind(), sut.SignerKind)
+			},
+		},
+		{
+			name: "removed values altered",
+			test: func(t *testing.T) {
+				id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				sut := newSUT()
+				sut.Sign(id, signer)
+				sut.Removed = sut.Removed[1:]
+
+				result := sut.Verify(id, verifier.New().Create(sut.SignerMetadata))
+
+				assert.False(t, result)
+			},
+		},
+		{
+			name: "different identity",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				sut.Sign(identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32)), signer)
+
+				result := sut.Verify(
+					identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32)),
+					verifier.New().Create(sut.SignerMetadata),
+				)
+
+				assert.False(t, result)
+			},
+		},
+		{
+			name: "nil verifier",
+			test: func(t *testing.T) {
+				id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				sut := newSUT()
+				sut.Sign(id, signer)
+
+				result := sut.Verify(id, nil)
+
+				assert.False(t, result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_UnmarshalJSON tests instance.UnmarshalJSON without signer factories.
+func TestInstance_UnmarshalJSON(t *testing.T) {
+	sut := newSUT()
+	var result Instance
+
+	err := result.UnmarshalJSON([]byte(testInternal.Marshal(t, sut)))
+
+	assert.Error(t, err)
+}
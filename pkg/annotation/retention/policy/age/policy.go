@@ -0,0 +1,47 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package age
+
+import (
+	"time"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/datetime"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+)
+
+// policy is a receiver that encapsulates required dependencies.
+type policy struct {
+	maxAge time.Duration
+}
+
+// New is a factory function that returns a policy selecting annotations created more than maxAge ago.
+func New(maxAge time.Duration) *policy {
+	return &policy{
+		maxAge: maxAge,
+	}
+}
+
+// Select returns the annotations created more than maxAge before now; annotations with an unparseable created value
+// are retained.
+func (p *policy) Select(now time.Time, annotations []*annotation.Instance) []*annotation.Instance {
+	cutoff := now.Add(-p.maxAge)
+	selected := make([]*annotation.Instance, 0)
+	for i := range annotations {
+		if created := datetime.TimeFromCreated(annotations[i].Created); created != nil && created.Before(cutoff) {
+			selected = append(selected, annotations[i])
+		}
+	}
+	return selected
+}
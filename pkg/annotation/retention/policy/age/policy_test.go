@@ -0,0 +1,82 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package age
+
+import (
+	"testing"
+	"time"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newAnnotation returns an annotation with the given created value.
+func newAnnotation(created string) *annotation.Instance {
+	id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	m := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+	m.Created = created
+	return m
+}
+
+// TestPolicy_Select tests policy.Select.
+func TestPolicy_Select(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	old := newAnnotation(now.Add(-2 * time.Hour).Format(time.RFC3339Nano))
+	cutoff := newAnnotation(now.Add(-time.Hour).Format(time.RFC3339Nano))
+	recent := newAnnotation(now.Add(-time.Minute).Format(time.RFC3339Nano))
+	invalid := newAnnotation(test.FactoryRandomString())
+
+	type testCase struct {
+		name        string
+		annotations []*annotation.Instance
+		expected    []*annotation.Instance
+	}
+
+	cases := []testCase{
+		{
+			name:        "none",
+			annotations: []*annotation.Instance{},
+			expected:    []*annotation.Instance{},
+		},
+		{
+			name:        "older than maximum age",
+			annotations: []*annotation.Instance{old, cutoff, recent},
+			expected:    []*annotation.Instance{old},
+		},
+		{
+			name:        "unparseable created value",
+			annotations: []*annotation.Instance{invalid, recent},
+			expected:    []*annotation.Instance{},
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := New(time.Hour)
+
+				result := sut.Select(now, cases[i].annotations)
+
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expected), testInternal.Marshal(t, result))
+			},
+		)
+	}
+}
@@ -0,0 +1,27 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package policy
+
+import (
+	"time"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+)
+
+// Contract defines the retention policy abstraction.
+type Contract interface {
+	// Select returns the annotations (stored directly against a single identity, oldest first) to compact as of now.
+	Select(now time.Time, annotations []*annotation.Instance) []*annotation.Instance
+}
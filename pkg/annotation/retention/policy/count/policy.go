@@ -0,0 +1,41 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package count
+
+import (
+	"time"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+)
+
+// policy is a receiver that encapsulates required dependencies.
+type policy struct {
+	max int
+}
+
+// New is a factory function that returns a policy retaining at most max annotations per identity.
+func New(max int) *policy {
+	return &policy{
+		max: max,
+	}
+}
+
+// Select returns all but the newest max annotations.
+func (p *policy) Select(_ time.Time, annotations []*annotation.Instance) []*annotation.Instance {
+	if len(annotations) <= p.max {
+		return make([]*annotation.Instance, 0)
+	}
+	return annotations[:len(annotations)-p.max]
+}
@@ -0,0 +1,78 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package count
+
+import (
+	"testing"
+	"time"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPolicy_Select tests policy.Select.
+func TestPolicy_Select(t *testing.T) {
+	id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	m1 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+	m2 := annotation.New(test.FactoryRandomString(), id, id, metadataStub.NewNullObject())
+	m3 := annotation.New(test.FactoryRandomString(), id, id, metadataStub.NewNullObject())
+
+	type testCase struct {
+		name     string
+		max      int
+		expected []*annotation.Instance
+	}
+
+	cases := []testCase{
+		{
+			name:     "fewer than maximum",
+			max:      4,
+			expected: []*annotation.Instance{},
+		},
+		{
+			name:     "equal to maximum",
+			max:      3,
+			expected: []*annotation.Instance{},
+		},
+		{
+			name:     "more than maximum",
+			max:      1,
+			expected: []*annotation.Instance{m1, m2},
+		},
+		{
+			name:     "zero maximum",
+			max:      0,
+			expected: []*annotation.Instance{m1, m2, m3},
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := New(cases[i].max)
+
+				result := sut.Select(time.Now(), []*annotation.Instance{m1, m2, m3})
+
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expected), testInternal.Marshal(t, result))
+			},
+		)
+	}
+}
@@ -0,0 +1,61 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package kind
+
+import (
+	"time"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	retentionPolicy "github.com/project-alvarium/go-sdk/pkg/annotation/retention/policy"
+)
+
+// policy is a receiver that encapsulates required dependencies.
+type policy struct {
+	kinds  []string
+	scoped retentionPolicy.Contract
+}
+
+// New is a factory function that returns a policy restricted to annotations whose metadata is one of kinds; scoped
+// further selects among those annotations (nil selects all of them).
+func New(kinds []string, scoped retentionPolicy.Contract) *policy {
+	return &policy{
+		kinds:  kinds,
+		scoped: scoped,
+	}
+}
+
+// matches returns whether the annotation's metadata is one of the policy's kinds.
+func (p *policy) matches(m *annotation.Instance) bool {
+	for i := range p.kinds {
+		if p.kinds[i] == m.MetadataKind {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the annotations of the policy's kinds selected by the scoped policy.
+func (p *policy) Select(now time.Time, annotations []*annotation.Instance) []*annotation.Instance {
+	matching := make([]*annotation.Instance, 0)
+	for i := range annotations {
+		if p.matches(annotations[i]) {
+			matching = append(matching, annotations[i])
+		}
+	}
+	if p.scoped == nil {
+		return matching
+	}
+	return p.scoped.Select(now, matching)
+}
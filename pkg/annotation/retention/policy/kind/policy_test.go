@@ -0,0 +1,78 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package kind
+
+import (
+	"testing"
+	"time"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/retention/policy/count"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPolicy_Select tests policy.Select.
+func TestPolicy_Select(t *testing.T) {
+	id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	a1 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.New("a", nil))
+	b1 := annotation.New(test.FactoryRandomString(), id, id, metadataStub.New("b", nil))
+	a2 := annotation.New(test.FactoryRandomString(), id, id, metadataStub.New("a", nil))
+	c1 := annotation.New(test.FactoryRandomString(), id, id, metadataStub.New("c", nil))
+
+	type testCase struct {
+		name     string
+		sut      *policy
+		expected []*annotation.Instance
+	}
+
+	cases := []testCase{
+		{
+			name:     "single kind",
+			sut:      New([]string{"a"}, nil),
+			expected: []*annotation.Instance{a1, a2},
+		},
+		{
+			name:     "multiple kinds",
+			sut:      New([]string{"b", "c"}, nil),
+			expected: []*annotation.Instance{b1, c1},
+		},
+		{
+			name:     "unknown kind",
+			sut:      New([]string{test.FactoryRandomString()}, nil),
+			expected: []*annotation.Instance{},
+		},
+		{
+			name:     "scoped policy",
+			sut:      New([]string{"a"}, count.New(1)),
+			expected: []*annotation.Instance{a1},
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				result := cases[i].sut.Select(time.Now(), []*annotation.Instance{a1, b1, a2, c1})
+
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expected), testInternal.Marshal(t, result))
+			},
+		)
+	}
+}
@@ -0,0 +1,268 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// retention implements policy-driven compaction of a store's annotations that preserves lineage.
+package retention
+
+import (
+	"sort"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/datetime"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/retention/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/retention/policy"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// Compaction describes the annotations removed from a single identity.
+type Compaction struct {
+	Identity identity.Contract `json:"identity"`
+	Removed  []string          `json:"removed"`
+	Summary  string            `json:"summary"`
+}
+
+// Report describes the result of applying retention policies to a store.
+type Report struct {
+	Identities  int          `json:"identities"`
+	Compactions []Compaction `json:"compactions"`
+}
+
+// Removed returns the total number of annotations removed.
+func (r *Report) Removed() int {
+	count := 0
+	for c := range r.Compactions {
+		count += len(r.Compactions[c].Removed)
+	}
+	return count
+}
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	store          Contract
+	policies       []policy.Contract
+	signer         signer.Contract
+	uniqueProvider uniqueprovider.Contract
+	archive        store.Contract
+}
+
+// New is a factory function that returns an initialized instance; annotations removed from store are written to
+// archive (nil discards them).
+func New(
+	store Contract,
+	policies []policy.Contract,
+	signer signer.Contract,
+	uniqueProvider uniqueprovider.Contract,
+	archive store.Contract) *instance {
+
+	return &instance{
+		store:          store,
+		policies:       policies,
+		signer:         signer,
+		uniqueProvider: uniqueProvider,
+		archive:        archive,
+	}
+}
+
+// attempts is the number of times an identity is compacted before a conflicting concurrent write is reported.
+const attempts = 3
+
+// Compact applies the retention policies to every identity in the store and returns a report and status.
+//
+// Annotations selected by any policy are replaced by a single signed summary annotation that records their unique
+// values and inherits their previous identities, so lineage traversal through the identity is unaffected.  Summary
+// annotations are never selected; an earlier summary is folded into a later one.
+//
+// Annotations stored while an identity is compacted are never lost: the identity is compacted again (up to attempts
+// times) and status.Conflict is returned if it keeps changing.
+func (i *instance) Compact() (*Report, status.Value) {
+	identities, result := i.store.Identities()
+	if result != status.Success {
+		return nil, result
+	}
+	sort.Slice(identities, func(a, b int) bool { return identities[a].Printable() < identities[b].Printable() })
+
+	now := time.Now().UTC()
+	report := &Report{Identities: len(identities), Compactions: make([]Compaction, 0)}
+	for n := range identities {
+		written := make(map[string]bool)
+		compaction, result := i.compact(now, identities[n], written)
+		for attempt := 1; result == status.Conflict && attempt < attempts; attempt++ {
+			compaction, result = i.compact(now, identities[n], written)
+		}
+		if result != status.Success {
+			return report, result
+		}
+		if compaction != nil {
+			report.Compactions = append(report.Compactions, *compaction)
+		}
+	}
+	return report, status.Success
+}
+
+// archived writes a removed annotation to the archive store and returns status.
+func (i *instance) archived(id identity.Contract, m *annotation.Instance) status.Value {
+	result := i.archive.Append(id, m)
+	if result == status.NotFound {
+		result = i.archive.Create(id, m)
+	}
+	return result
+}
+
+// summary returns a signed annotation summarizing the removed annotations and the earlier summaries it replaces.
+func (i *instance) summary(id identity.Contract, summaries, removed []*annotation.Instance) *annotation.Instance {
+	var from, to *time.Time
+	uniques := make([]string, 0, len(removed))
+	kinds := make([]string, 0)
+	seenKinds := make(map[string]bool)
+	addKind := func(kind string) {
+		if !seenKinds[kind] {
+			seenKinds[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+	addCreated := func(created string) {
+		t := datetime.TimeFromCreated(created)
+		if t == nil {
+			return
+		}
+		if from == nil || t.Before(*from) {
+			from = t
+		}
+		if to == nil || t.After(*to) {
+			to = t
+		}
+	}
+
+	predecessors := make([]identity.Contract, 0)
+	seenPredecessors := map[string]bool{id.Printable(): true}
+	addPredecessors := func(m *annotation.Instance) {
+		p := m.Predecessors()
+		for n := range p {
+			if !seenPredecessors[p[n].Printable()] {
+				seenPredecessors[p[n].Printable()] = true
+				predecessors = append(predecessors, p[n])
+			}
+		}
+	}
+
+	for s := range summaries {
+		if m, ok := summaries[s].Metadata.(*metadata.Instance); ok {
+			uniques = append(uniques, m.Removed...)
+			for k := range m.Kinds {
+				addKind(m.Kinds[k])
+			}
+			addCreated(m.From)
+			addCreated(m.To)
+		}
+		addPredecessors(summaries[s])
+	}
+	for r := range removed {
+		uniques = append(uniques, removed[r].Unique)
+		addKind(removed[r].MetadataKind)
+		addCreated(removed[r].Created)
+		addPredecessors(removed[r])
+	}
+
+	m := metadata.New(uniques, kinds, format(from), format(to))
+	m.Sign(id, i.signer)
+	if len(predecessors) > 1 {
+		return annotation.NewDerived(i.uniqueProvider.Get(), id, predecessors, m)
+	}
+
+	var previous identity.Contract = nil
+	if len(predecessors) == 1 {
+		previous = predecessors[0]
+	}
+	return annotation.New(i.uniqueProvider.Get(), id, previous, m)
+}
+
+// format returns t as an annotation created value (or an empty string if t is nil).
+func format(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// compact applies the retention policies to a single identity and returns the compaction (nil if nothing was
+// removed) and status; annotations whose unique values are in written are not written to the archive again.
+func (i *instance) compact(now time.Time, id identity.Contract, written map[string]bool) (*Compaction, status.Value) {
+	annotations, result := query.All(i.store, query.Criteria{Identity: id})
+	if result != status.Success {
+		return nil, result
+	}
+
+	expected := make([]string, len(annotations))
+	summaries := make([]*annotation.Instance, 0)
+	candidates := make([]*annotation.Instance, 0, len(annotations))
+	for a := range annotations {
+		expected[a] = annotations[a].Unique
+		if annotations[a].MetadataKind == metadata.Kind {
+			summaries = append(summaries, annotations[a])
+			continue
+		}
+		candidates = append(candidates, annotations[a])
+	}
+
+	selected := make(map[string]bool)
+	for p := range i.policies {
+		s := i.policies[p].Select(now, candidates)
+		for a := range s {
+			selected[s[a].Unique] = true
+		}
+	}
+	if len(selected) == 0 {
+		return nil, status.Success
+	}
+
+	removed := make([]*annotation.Instance, 0, len(selected))
+	retained := make([]*annotation.Instance, 0, len(candidates)-len(selected))
+	for a := range candidates {
+		if selected[candidates[a].Unique] {
+			removed = append(removed, candidates[a])
+			continue
+		}
+		retained = append(retained, candidates[a])
+	}
+
+	if i.archive != nil {
+		for _, m := range append(summaries, removed...) {
+			if written[m.Unique] {
+				continue
+			}
+			if result := i.archived(id, m); result != status.Success {
+				return nil, result
+			}
+			written[m.Unique] = true
+		}
+	}
+
+	summary := i.summary(id, summaries, removed)
+	replacement := append([]*annotation.Instance{summary}, retained...)
+	if result := i.store.Replace(id, expected, replacement); result != status.Success {
+		return nil, result
+	}
+
+	compaction := &Compaction{Identity: id, Removed: make([]string, len(removed)), Summary: summary.Unique}
+	for a := range removed {
+		compaction.Removed[a] = removed[a].Unique
+	}
+	return compaction, status.Success
+}
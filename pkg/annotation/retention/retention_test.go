@@ -0,0 +1,385 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package retention
+
+import (
+	"crypto"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/retention/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/retention/policy"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/retention/policy/count"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/retention/policy/kind"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider/ulid"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/factory/verifier"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSUT returns a new system under test.
+func newSUT(store Contract, policies []policy.Contract, archive store.Contract) *instance {
+	h := sha256.New()
+	return New(
+		store,
+		policies,
+		signpkcs1v15.New(crypto.SHA256, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, h),
+		ulid.New(),
+		archive,
+	)
+}
+
+// newIdentity returns a new random identity.
+func newIdentity() identity.Contract {
+	return identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+}
+
+// newAnnotation returns a new annotation of kind for identity.
+func newAnnotation(id, previous identity.Contract, kind string) *annotation.Instance {
+	return annotation.New(test.FactoryRandomFixedLengthAlphanumericString(26), id, previous, metadataStub.New(kind, nil))
+}
+
+// direct returns the annotations stored directly against identity.
+func direct(t *testing.T, s Contract, id identity.Contract) []*annotation.Instance {
+	page, result := s.Query(query.Criteria{Identity: id})
+	require.Equal(t, status.Success, result)
+	return page.Annotations
+}
+
+// split returns the single summary annotation stored directly against identity and the remaining annotations.
+func split(t *testing.T, s Contract, id identity.Contract) (*annotation.Instance, []*annotation.Instance) {
+	var m *annotation.Instance
+	others := make([]*annotation.Instance, 0)
+	stored := direct(t, s, id)
+	for i := range stored {
+		if stored[i].MetadataKind != metadata.Kind {
+			others = append(others, stored[i])
+			continue
+		}
+		require.Nil(t, m)
+		m = stored[i]
+	}
+	require.NotNil(t, m)
+	return m, others
+}
+
+// summary asserts m is a valid summary of removed for identity and returns its metadata.
+func summary(t *testing.T, id identity.Contract, m *annotation.Instance, removed []string) *metadata.Instance {
+	require.Equal(t, metadata.Kind, m.MetadataKind)
+	s, ok := m.Metadata.(*metadata.Instance)
+	require.True(t, ok)
+	assert.Equal(t, removed, s.Removed)
+	assert.True(t, s.Verify(id, verifier.New().Create(s.SignerMetadata)))
+	return s
+}
+
+// TestInstance_Compact tests instance.Compact.
+func TestInstance_Compact(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "nothing selected",
+			test: func(t *testing.T) {
+				s := memory.New()
+				id := newIdentity()
+				m1 := newAnnotation(id, nil, "a")
+				m2 := newAnnotation(id, id, "a")
+				require.Equal(t, status.Success, s.Create(id, m1))
+				require.Equal(t, status.Success, s.Append(id, m2))
+				sut := newSUT(s, []policy.Contract{count.New(2)}, nil)
+
+				report, result := sut.Compact()
+
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, 1, report.Identities)
+				assert.Equal(t, 0, len(report.Compactions))
+				assert.Equal(t, 0, report.Removed())
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m1, m2}), testInternal.Marshal(t, direct(t, s, id)))
+			},
+		},
+		{
+			name: "count preserves lineage",
+			test: func(t *testing.T) {
+				s := memory.New()
+				source, id := newIdentity(), newIdentity()
+				m0 := newAnnotation(source, nil, "a")
+				m1 := newAnnotation(id, source, "a")
+				m2 := newAnnotation(id, id, "a")
+				m3 := newAnnotation(id, id, "a")
+				require.Equal(t, status.Success, s.Create(source, m0))
+				require.Equal(t, status.Success, s.Create(id, m1))
+				require.Equal(t, status.Success, s.Append(id, m2))
+				require.Equal(t, status.Success, s.Append(id, m3))
+				sut := newSUT(s, []policy.Contract{count.New(1)}, nil)
+
+				report, result := sut.Compact()
+
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, 2, report.Identities)
+				require.Equal(t, 1, len(report.Compactions))
+				assert.Equal(t, id.Printable(), report.Compactions[0].Identity.Printable())
+				assert.Equal(t, []string{m1.Unique, m2.Unique}, report.Compactions[0].Removed)
+				assert.Equal(t, 2, report.Removed())
+
+				m, others := split(t, s, id)
+				assert.Equal(t, report.Compactions[0].Summary, m.Unique)
+				assert.Equal(t, source.Printable(), m.PreviousIdentity.Printable())
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m3}), testInternal.Marshal(t, others))
+				s1 := summary(t, id, m, []string{m1.Unique, m2.Unique})
+				assert.Equal(t, []string{"a"}, s1.Kinds)
+
+				lineage, result := s.FindByIdentity(id)
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m, m3, m0}), testInternal.Marshal(t, lineage))
+				node, _ := s.FindDescendants(source)
+				require.Equal(t, 1, len(node.Children))
+				assert.Equal(t, id.Printable(), node.Children[0].Identity.Printable())
+			},
+		},
+		{
+			name: "multiple predecessors",
+			test: func(t *testing.T) {
+				s := memory.New()
+				source1, source2, id := newIdentity(), newIdentity(), newIdentity()
+				m1 := annotation.NewDerived(
+					test.FactoryRandomFixedLengthAlphanumericString(26),
+					id,
+					[]identity.Contract{source1, source2},
+					metadataStub.New("a", nil),
+				)
+				m2 := newAnnotation(id, id, "a")
+				require.Equal(t, status.Success, s.Create(source1, newAnnotation(source1, nil, "a")))
+				require.Equal(t, status.Success, s.Create(source2, newAnnotation(source2, nil, "a")))
+				require.Equal(t, status.Success, s.Create(id, m1))
+				require.Equal(t, status.Success, s.Append(id, m2))
+				sut := newSUT(s, []policy.Contract{count.New(1)}, nil)
+
+				_, result := sut.Compact()
+
+				assert.Equal(t, status.Success, result)
+				m, _ := split(t, s, id)
+				predecessors := m.Predecessors()
+				require.Equal(t, 2, len(predecessors))
+				assert.Equal(t, source1.Printable(), predecessors[0].Printable())
+				assert.Equal(t, source2.Printable(), predecessors[1].Printable())
+				lineage, _ := s.FindByIdentity(id)
+				assert.Equal(t, 4, len(lineage))
+			},
+		},
+		{
+			name: "multiple policies",
+			test: func(t *testing.T) {
+				s := memory.New()
+				id := newIdentity()
+				m1 := newAnnotation(id, nil, "a")
+				m2 := newAnnotation(id, id, "b")
+				m3 := newAnnotation(id, id, "a")
+				m4 := newAnnotation(id, id, "b")
+				require.Equal(t, status.Success, s.Create(id, m1))
+				require.Equal(t, status.Success, s.Append(id, m2))
+				require.Equal(t, status.Success, s.Append(id, m3))
+				require.Equal(t, status.Success, s.Append(id, m4))
+				sut := newSUT(s, []policy.Contract{count.New(3), kind.New([]string{"b"}, nil)}, nil)
+
+				report, result := sut.Compact()
+
+				assert.Equal(t, status.Success, result)
+				require.Equal(t, 1, len(report.Compactions))
+				assert.Equal(t, []string{m1.Unique, m2.Unique, m4.Unique}, report.Compactions[0].Removed)
+				m, others := split(t, s, id)
+				s1 := summary(t, id, m, []string{m1.Unique, m2.Unique, m4.Unique})
+				assert.Equal(t, []string{"a", "b"}, s1.Kinds)
+				assert.Equal(t, m1.Created, s1.From)
+				assert.Equal(t, m4.Created, s1.To)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m3}), testInternal.Marshal(t, others))
+			},
+		},
+		{
+			name: "earlier summary folded",
+			test: func(t *testing.T) {
+				s := memory.New()
+				id := newIdentity()
+				m1 := newAnnotation(id, nil, "a")
+				m2 := newAnnotation(id, id, "a")
+				m3 := newAnnotation(id, id, "a")
+				require.Equal(t, status.Success, s.Create(id, m1))
+				require.Equal(t, status.Success, s.Append(id, m2))
+				sut := newSUT(s, []policy.Contract{count.New(1)}, nil)
+				_, result := sut.Compact()
+				require.Equal(t, status.Success, result)
+				require.Equal(t, status.Success, s.Append(id, m3))
+
+				report, result := sut.Compact()
+
+				assert.Equal(t, status.Success, result)
+				require.Equal(t, 1, len(report.Compactions))
+				assert.Equal(t, []string{m2.Unique}, report.Compactions[0].Removed)
+				m, others := split(t, s, id)
+				assert.Equal(t, report.Compactions[0].Summary, m.Unique)
+				summary(t, id, m, []string{m1.Unique, m2.Unique})
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m3}), testInternal.Marshal(t, others))
+			},
+		},
+		{
+			name: "archive",
+			test: func(t *testing.T) {
+				s, archive := memory.New(), memory.New()
+				id := newIdentity()
+				m1 := newAnnotation(id, nil, "a")
+				m2 := newAnnotation(id, id, "a")
+				m3 := newAnnotation(id, id, "a")
+				require.Equal(t, status.Success, s.Create(id, m1))
+				require.Equal(t, status.Success, s.Append(id, m2))
+				require.Equal(t, status.Success, s.Append(id, m3))
+				sut := newSUT(s, []policy.Contract{count.New(1)}, archive)
+
+				_, result := sut.Compact()
+
+				assert.Equal(t, status.Success, result)
+				archived, result := archive.FindByIdentity(id)
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m1, m2}), testInternal.Marshal(t, archived))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// interleaving is a store that appends an annotation immediately before the first replacement.
+type interleaving struct {
+	Contract
+	id       identity.Contract
+	m        *annotation.Instance
+	appended bool
+}
+
+// Replace appends the annotation on its first call and delegates to the wrapped store.
+func (i *interleaving) Replace(id identity.Contract, expected []string, m []*annotation.Instance) status.Value {
+	if !i.appended {
+		i.appended = true
+		if result := i.Contract.Append(i.id, i.m); result != status.Success {
+			return result
+		}
+	}
+	return i.Contract.Replace(id, expected, m)
+}
+
+// compacted returns the unique values stored directly against identity and recorded as removed by its summary.
+func compacted(t *testing.T, s Contract, id identity.Contract) map[string]bool {
+	result := make(map[string]bool)
+	for _, m := range direct(t, s, id) {
+		result[m.Unique] = true
+		if summary, ok := m.Metadata.(*metadata.Instance); ok {
+			for r := range summary.Removed {
+				result[summary.Removed[r]] = true
+			}
+		}
+	}
+	return result
+}
+
+// TestInstance_CompactConcurrentAppend tests that annotations appended during compaction are not lost.
+func TestInstance_CompactConcurrentAppend(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "append before replace",
+			test: func(t *testing.T) {
+				id := newIdentity()
+				m1 := newAnnotation(id, nil, "a")
+				m2 := newAnnotation(id, id, "a")
+				m3 := newAnnotation(id, id, "a")
+				s := &interleaving{Contract: memory.New(), id: id, m: m3}
+				require.Equal(t, status.Success, s.Create(id, m1))
+				require.Equal(t, status.Success, s.Append(id, m2))
+				sut := newSUT(s, []policy.Contract{count.New(1)}, nil)
+
+				report, result := sut.Compact()
+
+				assert.Equal(t, status.Success, result)
+				require.Equal(t, 1, len(report.Compactions))
+				assert.Equal(t, []string{m1.Unique, m2.Unique}, report.Compactions[0].Removed)
+				_, others := split(t, s, id)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m3}), testInternal.Marshal(t, others))
+			},
+		},
+		{
+			name: "append while compacting",
+			test: func(t *testing.T) {
+				s := memory.New()
+				id := newIdentity()
+				first := newAnnotation(id, nil, "a")
+				require.Equal(t, status.Success, s.Create(id, first))
+				sut := newSUT(s, []policy.Contract{count.New(2)}, nil)
+
+				appended := []string{first.Unique}
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					for n := 0; n < 200; n++ {
+						m := newAnnotation(id, id, "a")
+						if s.Append(id, m) == status.Success {
+							appended = append(appended, m.Unique)
+						}
+					}
+				}()
+			compacting:
+				for {
+					select {
+					case <-done:
+						break compacting
+					default:
+						_, result := sut.Compact()
+						require.Contains(t, []status.Value{status.Success, status.Conflict}, result)
+					}
+				}
+				_, result := sut.Compact()
+				require.Equal(t, status.Success, result)
+
+				stored := compacted(t, s, id)
+				for a := range appended {
+					assert.True(t, stored[appended[a]], appended[a])
+				}
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
@@ -0,0 +1,129 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// schema implements versioning of annotation and metadata JSON and a registry of migrations that upgrade JSON
+// written by earlier SDK releases to the current schema.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+const (
+	// Annotation is the kind under which migrations of the annotation envelope are registered.
+	Annotation = "annotation"
+
+	// Initial is the first schema version of every kind.  JSON without a schema version predates versioning and is
+	// read as Initial.
+	Initial = 1
+)
+
+// Migration upgrades JSON from one schema version to the next.
+type Migration func(data json.RawMessage) (json.RawMessage, error)
+
+// Contract defines the schema registry abstraction.
+type Contract interface {
+	// Register adds a migration that upgrades JSON of kind from version from to from+1; from must be the kind's
+	// current version.
+	Register(kind string, from int, migration Migration) error
+
+	// Current returns the current schema version of kind.
+	Current(kind string) int
+
+	// Upgrade applies the migrations registered for kind to JSON written at version and returns the upgraded JSON
+	// and its version.  JSON that is already current -- or was written at a newer version by a later release -- is
+	// returned unchanged (as is its version, including zero); an error is returned for versions below Initial.
+	Upgrade(kind string, version int, data json.RawMessage) (json.RawMessage, int, error)
+}
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	m          sync.RWMutex
+	migrations map[string][]Migration
+}
+
+// New is a factory function that returns an initialized instance.
+func New() *instance {
+	return &instance{
+		migrations: make(map[string][]Migration),
+	}
+}
+
+// Register adds a migration that upgrades JSON of kind from version from to from+1.
+func (i *instance) Register(kind string, from int, migration Migration) error {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if current := Initial + len(i.migrations[kind]); from != current {
+		return fmt.Errorf("%s migration from version %d registered at version %d", kind, from, current)
+	}
+	i.migrations[kind] = append(i.migrations[kind], migration)
+	return nil
+}
+
+// Current returns the current schema version of kind.
+func (i *instance) Current(kind string) int {
+	i.m.RLock()
+	defer i.m.RUnlock()
+
+	return Initial + len(i.migrations[kind])
+}
+
+// Upgrade applies the migrations registered for kind to JSON written at version and returns the result; JSON written
+// at a newer version than this registry knows is passed through so it can be preserved rather than rejected.
+func (i *instance) Upgrade(kind string, version int, data json.RawMessage) (json.RawMessage, int, error) {
+	i.m.RLock()
+	migrations := i.migrations[kind]
+	i.m.RUnlock()
+
+	from := version
+	if from == 0 {
+		from = Initial
+	}
+	current := Initial + len(migrations)
+	switch {
+	case from < Initial:
+		return nil, version, fmt.Errorf("unsupported %s schema version %d", kind, version)
+	case from >= current:
+		return data, version, nil
+	}
+
+	for v := from; v < current; v++ {
+		var err error
+		if data, err = migrations[v-Initial](data); err != nil {
+			return nil, version, fmt.Errorf("%s migration from version %d: %v", kind, v, err)
+		}
+	}
+	return data, current, nil
+}
+
+// registry is the process-wide registry used when unmarshalling annotations.
+var registry = New()
+
+// Register adds a migration to the process-wide registry; it is intended to be called from init functions.
+func Register(kind string, from int, migration Migration) error {
+	return registry.Register(kind, from, migration)
+}
+
+// Current returns the current schema version of kind in the process-wide registry.
+func Current(kind string) int {
+	return registry.Current(kind)
+}
+
+// Upgrade applies the migrations in the process-wide registry to JSON of kind written at version.
+func Upgrade(kind string, version int, data json.RawMessage) (json.RawMessage, int, error) {
+	return registry.Upgrade(kind, version, data)
+}
@@ -0,0 +1,209 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rename returns a migration that renames JSON object key from to key to.
+func rename(from, to string) Migration {
+	return func(data json.RawMessage) (json.RawMessage, error) {
+		var value map[string]json.RawMessage
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		value[to] = value[from]
+		delete(value, from)
+		return json.Marshal(value)
+	}
+}
+
+// TestInstance_Register tests instance.Register.
+func TestInstance_Register(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "unregistered kind is initial",
+			test: func(t *testing.T) {
+				sut := New()
+
+				assert.Equal(t, Initial, sut.Current(test.FactoryRandomString()))
+			},
+		},
+		{
+			name: "sequential migrations advance current version",
+			test: func(t *testing.T) {
+				sut := New()
+				kind := test.FactoryRandomString()
+
+				assert.NoError(t, sut.Register(kind, 1, rename("a", "b")))
+				assert.NoError(t, sut.Register(kind, 2, rename("b", "c")))
+
+				assert.Equal(t, 3, sut.Current(kind))
+				assert.Equal(t, Initial, sut.Current(test.FactoryRandomString()))
+			},
+		},
+		{
+			name: "out of order migration",
+			test: func(t *testing.T) {
+				sut := New()
+				kind := test.FactoryRandomString()
+
+				err := sut.Register(kind, 2, rename("a", "b"))
+
+				assert.Error(t, err)
+				assert.Equal(t, Initial, sut.Current(kind))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_Upgrade tests instance.Upgrade.
+func TestInstance_Upgrade(t *testing.T) {
+	kind := test.FactoryRandomString()
+	sut := New()
+	require.NoError(t, sut.Register(kind, 1, rename("a", "b")))
+	require.NoError(t, sut.Register(kind, 2, rename("b", "c")))
+
+	type testCase struct {
+		name            string
+		kind            string
+		version         int
+		data            string
+		expectedData    string
+		expectedVersion int
+		expectedError   bool
+	}
+
+	cases := []testCase{
+		{
+			name:            "unversioned",
+			kind:            kind,
+			version:         0,
+			data:            `{"a":1}`,
+			expectedData:    `{"c":1}`,
+			expectedVersion: 3,
+		},
+		{
+			name:            "intermediate version",
+			kind:            kind,
+			version:         2,
+			data:            `{"b":1}`,
+			expectedData:    `{"c":1}`,
+			expectedVersion: 3,
+		},
+		{
+			name:            "current version",
+			kind:            kind,
+			version:         3,
+			data:            `{"c": 1}`,
+			expectedData:    `{"c": 1}`,
+			expectedVersion: 3,
+		},
+		{
+			name:            "unversioned without migrations",
+			kind:            test.FactoryRandomString(),
+			version:         0,
+			data:            `{"a": 1}`,
+			expectedData:    `{"a": 1}`,
+			expectedVersion: 0,
+		},
+		{
+			name:            "newer version",
+			kind:            kind,
+			version:         4,
+			data:            `{"d":1}`,
+			expectedData:    `{"d":1}`,
+			expectedVersion: 4,
+		},
+		{
+			name:          "negative version",
+			kind:          kind,
+			version:       -1,
+			data:          `{"a":1}`,
+			expectedError: true,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				data, version, err := sut.Upgrade(cases[i].kind, cases[i].version, json.RawMessage(cases[i].data))
+
+				if cases[i].expectedError {
+					assert.Error(t, err)
+					assert.Nil(t, data)
+					assert.Equal(t, cases[i].version, version)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, cases[i].expectedData, string(data))
+				assert.Equal(t, cases[i].expectedVersion, version)
+			},
+		)
+	}
+
+	t.Run(
+		"failing migration",
+		func(t *testing.T) {
+			failing := New()
+			kind := test.FactoryRandomString()
+			require.NoError(
+				t,
+				failing.Register(kind, 1, func(json.RawMessage) (json.RawMessage, error) {
+					return nil, errors.New("failed")
+				}),
+			)
+
+			data, version, err := failing.Upgrade(kind, 1, json.RawMessage(`{}`))
+
+			assert.Error(t, err)
+			assert.True(t, strings.Contains(err.Error(), "failed"))
+			assert.Nil(t, data)
+			assert.Equal(t, 1, version)
+		},
+	)
+}
+
+// TestRegister tests the process-wide registry.
+func TestRegister(t *testing.T) {
+	kind := test.FactoryRandomString()
+
+	require.NoError(t, Register(kind, 1, rename("a", "b")))
+	data, version, err := Upgrade(kind, 0, json.RawMessage(`{"a":1}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, Current(kind))
+	assert.Equal(t, `{"b":1}`, string(data))
+	assert.Equal(t, 2, version)
+}
@@ -0,0 +1,115 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// signature implements annotation-level author signatures, a store decorator that signs every stored annotation,
+// and verification of the annotations a store returns.
+package signature
+
+import (
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	verifierFactory "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// Sign signs the annotation's signed data (see annotation.Instance.SignedData) and sets its signature; status.Unknown
+// is returned (and the annotation is left unsigned) if the annotation has no current identity, cannot be encoded, or
+// cannot be signed.
+func Sign(m *annotation.Instance, s signer.Contract) status.Value {
+	m.Signature = nil
+	if m.CurrentIdentity == nil {
+		return status.Unknown
+	}
+	data, err := m.SignedData()
+	if err != nil {
+		return status.Unknown
+	}
+	value, err := signer.SignData(s, data)
+	if err != nil {
+		return status.Unknown
+	}
+
+	m.Signature = &annotation.Signature{
+		Value:          value,
+		PublicKey:      s.PublicKey(),
+		SignerMetadata: s.Metadata(),
+	}
+	if m.Signature.SignerMetadata != nil {
+		m.Signature.SignerKind = m.Signature.SignerMetadata.Kind()
+	}
+	return status.Success
+}
+
+// Verify returns whether the annotation is signed and its signature is valid for its embedded public key.
+//
+// Verify establishes that the annotation was not altered after it was signed; callers establish authorship by
+// comparing the signature's public key with the keys they trust.
+func Verify(m *annotation.Instance, factory verifierFactory.Contract) bool {
+	if m.Signature == nil || m.Signature.SignerMetadata == nil {
+		return false
+	}
+	data, err := m.SignedData()
+	if err != nil {
+		return false
+	}
+	v := factory.Create(m.Signature.SignerMetadata)
+	return v != nil && v.VerifyData(data, m.Signature.Value, m.Signature.PublicKey)
+}
+
+// Result is the outcome of verifying a single annotation.
+type Result struct {
+	Unique    string `json:"unique"`
+	Signed    bool   `json:"signed"`
+	Valid     bool   `json:"valid"`
+	PublicKey []byte `json:"publicKey,omitempty"`
+}
+
+// Report describes the outcome of verifying every annotation returned for an identity.
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Valid returns whether every annotation in the report is signed and valid.
+func (r *Report) Valid() bool {
+	for i := range r.Results {
+		if !r.Results[i].Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyIdentity verifies every annotation returned by the store's FindByIdentity for identity and returns a report
+// (in FindByIdentity's order) and status.
+func VerifyIdentity(s store.Contract, id identity.Contract, factory verifierFactory.Contract) (*Report, status.Value) {
+	annotations, result := s.FindByIdentity(id)
+	if result != status.Success {
+		return nil, result
+	}
+
+	report := &Report{Results: make([]Result, len(annotations))}
+	for a := range annotations {
+		report.Results[a] = Result{
+			Unique: annotations[a].Unique,
+			Signed: annotations[a].Signature != nil,
+			Valid:  Verify(annotations[a], factory),
+		}
+		if annotations[a].Signature != nil {
+			report.Results[a].PublicKey = annotations[a].Signature.PublicKey
+		}
+	}
+	return report, status.Success
+}
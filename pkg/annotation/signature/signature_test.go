@@ -0,0 +1,332 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package signature
+
+import (
+	"crypto"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/decoder"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	metadataStubFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/file"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/factory/verifier"
+	ownershipMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/ownership/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/fail"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15"
+	signerFactory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// annotationMetadata is the metadata of every test annotation.
+var annotationMetadata = metadataStub.NewNullObject()
+
+// newSigner returns a signer using the test key pair.
+func newSigner() signer.Contract {
+	return signpkcs1v15.New(crypto.SHA256, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, sha256.New())
+}
+
+// newIdentity returns a new random identity.
+func newIdentity() identity.Contract {
+	return identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+}
+
+// newAnnotation returns a new unsigned annotation for the given identities.
+func newAnnotation(id, previous identity.Contract) *annotation.Instance {
+	return annotation.New(test.FactoryRandomFixedLengthAlphanumericString(26), id, previous, annotationMetadata)
+}
+
+// roundTrip returns the annotation decoded from its JSON encoding.
+func roundTrip(t *testing.T, m *annotation.Instance) *annotation.Instance {
+	var result annotation.Instance
+	result.SetIdentityFactory(identityFactory.New())
+	result.SetMetadataFactory(metadataFactory.New([]metadataFactory.Contract{metadataStubFactory.New(annotationMetadata)}))
+	result.SetSignerMetadataFactory(signerFactory.New())
+	require.NoError(t, json.Unmarshal([]byte(testInternal.Marshal(t, m)), &result))
+	return &result
+}
+
+// TestVerify tests Sign and Verify.
+func TestVerify(t *testing.T) {
+	type testCase struct {
+		name     string
+		m        func(t *testing.T) *annotation.Instance
+		expected bool
+	}
+
+	cases := []testCase{
+		{
+			name: "signed",
+			m: func(t *testing.T) *annotation.Instance {
+				m := newAnnotation(newIdentity(), nil)
+				Sign(m, newSigner())
+				return m
+			},
+			expected: true,
+		},
+		{
+			name: "signed after decoding",
+			m: func(t *testing.T) *annotation.Instance {
+				m := newAnnotation(newIdentity(), newIdentity())
+				Sign(m, newSigner())
+				return roundTrip(t, m)
+			},
+			expected: true,
+		},
+		{
+			name: "signed twice",
+			m: func(t *testing.T) *annotation.Instance {
+				m := newAnnotation(newIdentity(), nil)
+				Sign(m, newSigner())
+				Sign(m, newSigner())
+				return m
+			},
+			expected: true,
+		},
+		{
+			name: "unsigned",
+			m: func(t *testing.T) *annotation.Instance {
+				return newAnnotation(newIdentity(), nil)
+			},
+			expected: false,
+		},
+		{
+			name: "altered",
+			m: func(t *testing.T) *annotation.Instance {
+				m := newAnnotation(newIdentity(), nil)
+				Sign(m, newSigner())
+				m.PreviousIdentity = newIdentity()
+				return m
+			},
+			expected: false,
+		},
+		{
+			name: "altered after decoding",
+			m: func(t *testing.T) *annotation.Instance {
+				m := newAnnotation(newIdentity(), nil)
+				Sign(m, newSigner())
+				m.Created = test.FactoryRandomString()
+				return roundTrip(t, m)
+			},
+			expected: false,
+		},
+		{
+			name: "different public key",
+			m: func(t *testing.T) *annotation.Instance {
+				m := newAnnotation(newIdentity(), nil)
+				Sign(m, newSigner())
+				m.Signature.PublicKey = test.FactoryRandomByteSlice()
+				return m
+			},
+			expected: false,
+		},
+		{
+			name: "unknown signer",
+			m: func(t *testing.T) *annotation.Instance {
+				m := newAnnotation(newIdentity(), nil)
+				Sign(m, newSigner())
+				m.Signature.SignerMetadata = metadataStub.NewNullObject()
+				return m
+			},
+			expected: false,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				assert.Equal(t, cases[i].expected, Verify(cases[i].m(t), verifier.New()))
+			},
+		)
+	}
+}
+
+// TestSign tests Sign and the store decorator when an annotation cannot be signed.
+func TestSign(t *testing.T) {
+	type testCase struct {
+		name string
+		m    func() *annotation.Instance
+		sut  signer.Contract
+	}
+
+	cases := []testCase{
+		{
+			name: "no current identity",
+			m: func() *annotation.Instance {
+				m := newAnnotation(newIdentity(), nil)
+				m.CurrentIdentity = nil
+				return m
+			},
+			sut: newSigner(),
+		},
+		{
+			name: "cannot be encoded",
+			m: func() *annotation.Instance {
+				m := newAnnotation(newIdentity(), nil)
+				m.Metadata = metadataStub.New(test.FactoryRandomString(), make(chan int))
+				return m
+			},
+			sut: newSigner(),
+		},
+		{
+			name: "signer fails",
+			m:    func() *annotation.Instance { return newAnnotation(newIdentity(), nil) },
+			sut:  fail.New(),
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				m := cases[i].m()
+				m.Signature = &annotation.Signature{Value: test.FactoryRandomByteSlice()}
+
+				assert.Equal(t, status.Unknown, Sign(m, cases[i].sut))
+				assert.Nil(t, m.Signature)
+
+				underlying := memory.New()
+				sut := New(underlying, cases[i].sut)
+				id := newIdentity()
+				assert.Equal(t, status.Unknown, sut.Create(id, cases[i].m()))
+				_, result := underlying.FindByIdentity(id)
+				assert.Equal(t, status.NotFound, result)
+			},
+		)
+	}
+}
+
+// TestVerifyIdentity tests VerifyIdentity.
+func TestVerifyIdentity(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "all signed",
+			test: func(t *testing.T) {
+				sut := New(memory.New(), newSigner())
+				id1, id2 := newIdentity(), newIdentity()
+				m1, m2 := newAnnotation(id1, nil), newAnnotation(id2, id1)
+				require.Equal(t, status.Success, sut.Create(id1, m1))
+				require.Equal(t, status.Success, sut.Create(id2, m2))
+
+				report, result := VerifyIdentity(sut, id2, verifier.New())
+
+				require.Equal(t, status.Success, result)
+				assert.True(t, report.Valid())
+				assert.Equal(
+					t,
+					[]Result{
+						{Unique: m2.Unique, Signed: true, Valid: true, PublicKey: testInternal.ValidPublicKey},
+						{Unique: m1.Unique, Signed: true, Valid: true, PublicKey: testInternal.ValidPublicKey},
+					},
+					report.Results,
+				)
+			},
+		},
+		{
+			name: "unsigned and altered",
+			test: func(t *testing.T) {
+				underlying := memory.New()
+				sut := New(underlying, newSigner())
+				id := newIdentity()
+				m1, m2, m3 := newAnnotation(id, nil), newAnnotation(id, nil), newAnnotation(id, nil)
+				require.Equal(t, status.Success, sut.Create(id, m1))
+				require.Equal(t, status.Success, underlying.Append(id, m2))
+				require.Equal(t, status.Success, sut.Append(id, m3))
+				m3.Unique = test.FactoryRandomString()
+
+				report, result := VerifyIdentity(sut, id, verifier.New())
+
+				require.Equal(t, status.Success, result)
+				assert.False(t, report.Valid())
+				assert.Equal(
+					t,
+					[]Result{
+						{Unique: m1.Unique, Signed: true, Valid: true, PublicKey: testInternal.ValidPublicKey},
+						{Unique: m2.Unique, Signed: false, Valid: false},
+						{Unique: m3.Unique, Signed: true, Valid: false, PublicKey: testInternal.ValidPublicKey},
+					},
+					report.Results,
+				)
+			},
+		},
+		{
+			name: "persisted",
+			test: func(t *testing.T) {
+				dir, err := ioutil.TempDir("", "signature")
+				require.NoError(t, err)
+				defer func() { _ = os.RemoveAll(dir) }()
+				path := filepath.Join(dir, "annotations.log")
+				underlying, err := file.New(path, decoder.IdentityFactory(), decoder.MetadataFactory())
+				require.NoError(t, err)
+				sut := New(underlying, newSigner())
+				id := newIdentity()
+				m := annotation.New(
+					test.FactoryRandomFixedLengthAlphanumericString(26),
+					id,
+					nil,
+					ownershipMetadata.New(test.FactoryRandomString(), ownershipMetadata.ActionCreate, ""),
+				)
+				require.Equal(t, status.Success, sut.Create(id, m))
+				reopened, err := file.New(path, decoder.IdentityFactory(), decoder.MetadataFactory())
+				require.NoError(t, err)
+
+				report, result := VerifyIdentity(reopened, id, verifier.New())
+
+				require.Equal(t, status.Success, result)
+				assert.Equal(
+					t,
+					[]Result{{Unique: m.Unique, Signed: true, Valid: true, PublicKey: testInternal.ValidPublicKey}},
+					report.Results,
+				)
+			},
+		},
+		{
+			name: "identity does not exist",
+			test: func(t *testing.T) {
+				report, result := VerifyIdentity(memory.New(), newIdentity(), verifier.New())
+
+				assert.Nil(t, report)
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
@@ -0,0 +1,67 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package signature
+
+import (
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	store  store.Contract
+	signer signer.Contract
+}
+
+// New is a factory function that returns a store decorator signing every annotation (see Sign) with signer before
+// storing it in store.
+func New(store store.Contract, signer signer.Contract) *instance {
+	return &instance{
+		store:  store,
+		signer: signer,
+	}
+}
+
+// FindByIdentity returns annotations and status corresponding to identity.
+func (i *instance) FindByIdentity(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	return i.store.FindByIdentity(id)
+}
+
+// FindDescendants returns the tree of identities derived from identity (with their annotations) and status.
+func (i *instance) FindDescendants(id identity.Contract) (*lineage.Node, status.Value) {
+	return i.store.FindDescendants(id)
+}
+
+// Create signs the annotation, stores it corresponding to a new identity, and returns status; nothing is stored if
+// the annotation cannot be signed.
+func (i *instance) Create(id identity.Contract, m *annotation.Instance) status.Value {
+	if result := Sign(m, i.signer); result != status.Success {
+		return result
+	}
+	return i.store.Create(id, m)
+}
+
+// Append signs the annotation, stores it corresponding to identity, and returns status; nothing is stored if the
+// annotation cannot be signed.
+func (i *instance) Append(id identity.Contract, m *annotation.Instance) status.Value {
+	if result := Sign(m, i.signer); result != status.Success {
+		return result
+	}
+	return i.store.Append(id, m)
+}
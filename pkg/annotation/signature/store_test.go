@@ -0,0 +1,87 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package signature
+
+import (
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/factory/verifier"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStore tests the signing store decorator.
+func TestStore(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "create and append sign before storing",
+			test: func(t *testing.T) {
+				underlying := memory.New()
+				sut := New(underlying, newSigner())
+				id := newIdentity()
+				m1, m2 := newAnnotation(id, nil), newAnnotation(id, nil)
+
+				assert.Equal(t, status.Success, sut.Create(id, m1))
+				assert.Equal(t, status.Success, sut.Append(id, m2))
+
+				stored, result := underlying.FindByIdentity(id)
+				require.Equal(t, status.Success, result)
+				require.Len(t, stored, 2)
+				for i := range stored {
+					assert.True(t, Verify(stored[i], verifier.New()))
+				}
+			},
+		},
+		{
+			name: "status of underlying store",
+			test: func(t *testing.T) {
+				sut := New(memory.New(), newSigner())
+				id := newIdentity()
+
+				assert.Equal(t, status.NotFound, sut.Append(id, newAnnotation(id, nil)))
+				assert.Equal(t, status.Success, sut.Create(id, newAnnotation(id, nil)))
+				assert.Equal(t, status.Exists, sut.Create(id, newAnnotation(id, nil)))
+			},
+		},
+		{
+			name: "find descendants",
+			test: func(t *testing.T) {
+				sut := New(memory.New(), newSigner())
+				id1, id2 := newIdentity(), newIdentity()
+				require.Equal(t, status.Success, sut.Create(id1, newAnnotation(id1, nil)))
+				require.Equal(t, status.Success, sut.Create(id2, newAnnotation(id2, id1)))
+
+				node, result := sut.FindDescendants(id1)
+
+				require.Equal(t, status.Success, result)
+				require.Len(t, node.Children, 1)
+				assert.Equal(t, id2.Printable(), node.Children[0].Identity.Printable())
+				assert.True(t, Verify(node.Children[0].Annotations[0], verifier.New()))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
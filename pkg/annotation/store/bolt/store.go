@@ -0,0 +1,449 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// bolt implements an annotation store on top of an embedded bbolt key-value database with secondary indexes.
+package bolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/datetime"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding"
+	jsonEncoding "github.com/project-alvarium/go-sdk/pkg/annotation/encoding/json"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+var (
+	// annotationsBucket maps an annotation's unique value to its encoded form.
+	annotationsBucket = []byte("annotations")
+
+	// identityBucket indexes annotation uniques by identity printable value and insertion sequence.
+	identityBucket = []byte("identity")
+
+	// kindBucket indexes annotation uniques by metadata kind and insertion sequence.
+	kindBucket = []byte("kind")
+
+	// createdBucket indexes annotation uniques by created timestamp and insertion sequence.
+	createdBucket = []byte("created")
+
+	// derivedBucket indexes the printable values of the identities annotations are stored against by each of the
+	// annotations' predecessors' printable values and insertion sequence.
+	derivedBucket = []byte("derived")
+
+	// identitiesBucket maps the printable value of each identity annotations are stored against to its kind and JSON.
+	identitiesBucket = []byte("identities")
+
+	// metaBucket holds database-wide settings (e.g. the encoding annotations are stored with).
+	metaBucket = []byte("meta")
+
+	// encodingKey is the metaBucket key under which the kind of the database's encoding is recorded.
+	encodingKey = []byte("encoding")
+
+	buckets = [][]byte{
+		annotationsBucket,
+		identityBucket,
+		kindBucket,
+		createdBucket,
+		derivedBucket,
+		identitiesBucket,
+		metaBucket,
+	}
+)
+
+// storedIdentity defines the structure of an identity recorded in identitiesBucket.
+type storedIdentity struct {
+	Kind  string          `json:"kind"`
+	Value json.RawMessage `json:"value"`
+}
+
+const (
+	// separator delimits the variable-length prefix of an index key from its sequence number.
+	separator = 0x00
+
+	// signBit is the most significant bit of a created index key's timestamp (see createdKey).
+	signBit = uint64(1) << 63
+)
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	db              *bbolt.DB
+	identityFactory identityFactory.Contract
+	metadataFactory metadataFactory.Contract
+	encoding        encoding.Contract
+}
+
+// New is a factory function that opens (or creates) the database at path and returns instance; annotations are
+// stored as JSON.
+func New(
+	path string,
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract) (*instance, error) {
+
+	return NewWithEncoding(path, identityFactory, metadataFactory, jsonEncoding.New())
+}
+
+// NewWithEncoding is a factory function that opens (or creates) the database at path and returns instance;
+// annotations are stored with encoding.
+//
+// A new database records encoding's kind; an error is returned if an existing database has no recorded encoding or
+// was written with a different one.
+func NewWithEncoding(
+	path string,
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract,
+	encoding encoding.Contract) (*instance, error) {
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for b := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(buckets[b]); err != nil {
+				return err
+			}
+		}
+
+		meta := tx.Bucket(metaBucket)
+		recorded := meta.Get(encodingKey)
+		switch {
+		case recorded == nil && empty(tx):
+			return meta.Put(encodingKey, []byte(encoding.Kind()))
+		case recorded == nil:
+			return errors.New("database has no recorded encoding")
+		case string(recorded) != encoding.Kind():
+			return fmt.Errorf(
+				"database written with %s encoding cannot be opened with %s encoding",
+				recorded,
+				encoding.Kind(),
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &instance{
+		db:              db,
+		identityFactory: identityFactory,
+		metadataFactory: metadataFactory,
+		encoding:        encoding,
+	}, nil
+}
+
+// empty returns whether the database holds no annotations.
+func empty(tx *bbolt.Tx) bool {
+	k, _ := tx.Bucket(annotationsBucket).Cursor().First()
+	return k == nil
+}
+
+// Close releases the underlying database.
+func (i *instance) Close() error {
+	return i.db.Close()
+}
+
+// prefixKey returns the index key prefix for a variable-length value.
+func prefixKey(value string) []byte {
+	return append([]byte(value), separator)
+}
+
+// sequenceKey returns an index key made of prefix followed by a big-endian sequence number.
+func sequenceKey(prefix []byte, sequence uint64) []byte {
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], sequence)
+	return key
+}
+
+// createdKey returns the timestamp prefix used to order the created index; the sign bit of the timestamp is flipped
+// so that times before 1970 (negative UnixNano values) order before later ones.
+func createdKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano())^signBit)
+	return key
+}
+
+// decode converts a stored annotation into an annotation using the injected factories.
+func (i *instance) decode(data []byte) (*annotation.Instance, error) {
+	if data == nil {
+		return nil, errors.New("indexed annotation missing")
+	}
+
+	var a annotation.Instance
+	a.SetIdentityFactory(i.identityFactory)
+	a.SetMetadataFactory(i.metadataFactory)
+	if err := i.encoding.Unmarshal(data, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// scan returns the annotations referenced by index keys within [from, to); a nil to scans every key prefixed by from.
+// An error is returned if any referenced annotation cannot be decoded, so callers never see a partial result.
+func (i *instance) scan(tx *bbolt.Tx, bucket, from, to []byte) ([]*annotation.Instance, error) {
+	annotations := make([]*annotation.Instance, 0)
+	data := tx.Bucket(annotationsBucket)
+	c := tx.Bucket(bucket).Cursor()
+	for k, v := c.Seek(from); k != nil; k, v = c.Next() {
+		if (to == nil && !bytes.HasPrefix(k, from)) || (to != nil && bytes.Compare(k, to) >= 0) {
+			break
+		}
+		a, err := i.decode(data.Get(v))
+		if err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations, nil
+}
+
+// exists returns whether any annotations are indexed under identity.
+func exists(tx *bbolt.Tx, id identity.Contract) bool {
+	prefix := prefixKey(id.Printable())
+	k, _ := tx.Bucket(identityBucket).Cursor().Seek(prefix)
+	return k != nil && bytes.HasPrefix(k, prefix)
+}
+
+// fetch returns the annotations stored directly against identity.
+func (i *instance) fetch(tx *bbolt.Tx, id identity.Contract) ([]*annotation.Instance, status.Value) {
+	if !exists(tx, id) {
+		return nil, status.NotFound
+	}
+	annotations, err := i.scan(tx, identityBucket, prefixKey(id.Printable()), nil)
+	if err != nil {
+		return nil, status.Unknown
+	}
+	return annotations, status.Success
+}
+
+// FindByIdentity returns annotations and status corresponding to identity.
+func (i *instance) FindByIdentity(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	annotations := make([]*annotation.Instance, 0)
+	result := status.Unknown
+	_ = i.db.View(func(tx *bbolt.Tx) error {
+		annotations, result = lineage.Find(id, func(id identity.Contract) ([]*annotation.Instance, status.Value) {
+			return i.fetch(tx, id)
+		})
+		return nil
+	})
+	return annotations, result
+}
+
+// storeIdentity returns the identity recorded in identitiesBucket under printable value p, creating it with the
+// injected identity factory (or as an opaque identity if the factory does not recognize its kind).
+func (i *instance) storeIdentity(tx *bbolt.Tx, p []byte) identity.Contract {
+	data := tx.Bucket(identitiesBucket).Get(p)
+	if data == nil {
+		return nil
+	}
+
+	var stored storedIdentity
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil
+	}
+	if id := i.identityFactory.Create(stored.Kind, stored.Value); id != nil {
+		return id
+	}
+	return opaqueIdentity.New(stored.Kind, stored.Value)
+}
+
+// children returns the identities derived directly from identity.
+func (i *instance) children(tx *bbolt.Tx, id identity.Contract) ([]identity.Contract, status.Value) {
+	identities := make([]identity.Contract, 0)
+	prefix := prefixKey(id.Printable())
+	c := tx.Bucket(derivedBucket).Cursor()
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		derived := i.storeIdentity(tx, v)
+		if derived == nil {
+			return nil, status.Unknown
+		}
+		identities = append(identities, derived)
+	}
+	return identities, status.Success
+}
+
+// FindDescendants returns the tree of identities derived from identity (with their annotations) and status.
+func (i *instance) FindDescendants(id identity.Contract) (*lineage.Node, status.Value) {
+	var node *lineage.Node
+	result := status.Unknown
+	_ = i.db.View(func(tx *bbolt.Tx) error {
+		node, result = lineage.Descendants(
+			id,
+			func(id identity.Contract) ([]*annotation.Instance, status.Value) {
+				return i.fetch(tx, id)
+			},
+			func(id identity.Contract) ([]identity.Contract, status.Value) {
+				return i.children(tx, id)
+			},
+		)
+		return nil
+	})
+	return node, result
+}
+
+// FindByUnique returns the annotation and status corresponding to unique.
+func (i *instance) FindByUnique(unique string) (*annotation.Instance, status.Value) {
+	var a *annotation.Instance
+	result := status.NotFound
+	_ = i.db.View(func(tx *bbolt.Tx) error {
+		if data := tx.Bucket(annotationsBucket).Get([]byte(unique)); data != nil {
+			var err error
+			if a, err = i.decode(data); err != nil {
+				result = status.Unknown
+				return nil
+			}
+			result = status.Success
+		}
+		return nil
+	})
+	if result != status.Success {
+		return nil, result
+	}
+	return a, result
+}
+
+// find returns the annotations referenced by bucket's index keys within [from, to) (see scan) and status;
+// status.NotFound is returned if there are none.
+func (i *instance) find(bucket, from, to []byte) ([]*annotation.Instance, status.Value) {
+	var annotations []*annotation.Instance
+	var err error
+	_ = i.db.View(func(tx *bbolt.Tx) error {
+		annotations, err = i.scan(tx, bucket, from, to)
+		return nil
+	})
+	switch {
+	case err != nil:
+		return nil, status.Unknown
+	case len(annotations) == 0:
+		return annotations, status.NotFound
+	}
+	return annotations, status.Success
+}
+
+// FindByKind returns annotations whose metadata is of kind, in insertion order.
+func (i *instance) FindByKind(kind string) ([]*annotation.Instance, status.Value) {
+	return i.find(kindBucket, prefixKey(kind), nil)
+}
+
+// FindByCreated returns annotations created within [from, to), ordered by created timestamp.
+func (i *instance) FindByCreated(from, to time.Time) ([]*annotation.Instance, status.Value) {
+	return i.find(createdBucket, createdKey(from), createdKey(to))
+}
+
+// put stores an annotation and its index entries within a write transaction.
+func (i *instance) put(tx *bbolt.Tx, id identity.Contract, m *annotation.Instance) (status.Value, error) {
+	data := tx.Bucket(annotationsBucket)
+	unique := []byte(m.Unique)
+	if data.Get(unique) != nil {
+		return status.Exists, nil
+	}
+
+	marshaledAnnotation, err := i.encoding.Marshal(m)
+	if err != nil {
+		return status.Unknown, err
+	}
+	if err := data.Put(unique, marshaledAnnotation); err != nil {
+		return status.Unknown, err
+	}
+
+	sequence, err := data.NextSequence()
+	if err != nil {
+		return status.Unknown, err
+	}
+
+	entries := map[string][]byte{
+		string(identityBucket): sequenceKey(prefixKey(id.Printable()), sequence),
+		string(kindBucket):     sequenceKey(prefixKey(m.MetadataKind), sequence),
+	}
+	if created := datetime.TimeFromCreated(m.Created); created != nil {
+		entries[string(createdBucket)] = sequenceKey(createdKey(*created), sequence)
+	}
+	for bucket, key := range entries {
+		if err := tx.Bucket([]byte(bucket)).Put(key, unique); err != nil {
+			return status.Unknown, err
+		}
+	}
+
+	idAsString := []byte(id.Printable())
+	if tx.Bucket(identitiesBucket).Get(idAsString) == nil {
+		marshaledIdentity, err := json.Marshal(id)
+		if err != nil {
+			return status.Unknown, err
+		}
+		stored, err := json.Marshal(storedIdentity{Kind: id.Kind(), Value: marshaledIdentity})
+		if err != nil {
+			return status.Unknown, err
+		}
+		if err := tx.Bucket(identitiesBucket).Put(idAsString, stored); err != nil {
+			return status.Unknown, err
+		}
+	}
+
+	predecessors := m.Predecessors()
+	for p := range predecessors {
+		if predecessors[p].Printable() == id.Printable() {
+			continue
+		}
+		key := sequenceKey(prefixKey(predecessors[p].Printable()), sequence)
+		if err := tx.Bucket(derivedBucket).Put(key, idAsString); err != nil {
+			return status.Unknown, err
+		}
+	}
+	return status.Success, nil
+}
+
+// write stores an annotation if identity's existence matches mustExist and returns status.
+func (i *instance) write(id identity.Contract, m *annotation.Instance, mustExist bool) status.Value {
+	result := status.Unknown
+	_ = i.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		found := exists(tx, id)
+		switch {
+		case found && !mustExist:
+			result = status.Exists
+		case !found && mustExist:
+			result = status.NotFound
+		default:
+			result, err = i.put(tx, id, m)
+		}
+		return err
+	})
+	return result
+}
+
+// Create stores annotations corresponding to a new identity and returns status.
+func (i *instance) Create(id identity.Contract, m *annotation.Instance) status.Value {
+	return i.write(id, m, false)
+}
+
+// Append stores annotations corresponding to identity and returns status.
+func (i *instance) Append(id identity.Contract, m *annotation.Instance) status.Value {
+	return i.write(id, m, true)
+}
@@ -0,0 +1,734 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package bolt
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
+	jsonEncoding "github.com/project-alvarium/go-sdk/pkg/annotation/encoding/json"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	opaqueMetadata "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/opaque"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	metadataStubFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	pkiFactory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bbolt "go.etcd.io/bbolt"
+)
+
+// signerMetadata is the signer metadata embedded in every test annotation.
+var signerMetadata = metadataStub.NewNullObject()
+
+// newPath returns the path of a log file within a new temporary directory and a function to remove it.
+func newPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "store")
+	require.NoError(t, err)
+	return filepath.Join(dir, "annotations.db"), func() { _ = os.RemoveAll(dir) }
+}
+
+// newSUT returns a new system under test.
+func newSUT(t *testing.T, path string) *instance {
+	return newSUTWithEncoding(t, path, jsonEncoding.New())
+}
+
+// newSUTWithEncoding returns a new system under test that stores annotations with encoding.
+func newSUTWithEncoding(t *testing.T, path string, encoding encoding.Contract) *instance {
+	sut, err := NewWithEncoding(
+		path,
+		identityFactory.New(),
+		metadataFactory.New(
+			[]metadataFactory.Contract{
+				pkiFactory.New([]metadataFactory.Contract{metadataStubFactory.New(signerMetadata)}),
+			},
+		),
+		encoding,
+	)
+	require.NoError(t, err)
+	return sut
+}
+
+// newAnnotation returns a new annotation for the given identities.
+func newAnnotation(id, previous identity.Contract) *annotation.Instance {
+	return annotation.New(
+		test.FactoryRandomFixedLengthAlphanumericString(26),
+		id,
+		previous,
+		metadata.New(
+			test.FactoryRandomString(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			signerMetadata,
+		),
+	)
+}
+
+// TestStore_FindByIdentity tests store.FindByIdentity.
+func TestStore_FindByIdentity(t *testing.T) {
+	type testCase struct {
+		name                string
+		identity            identity.Contract
+		preCondition        func(t *testing.T, sut *instance)
+		expectedAnnotations []*annotation.Instance
+		expectedStatus      status.Value
+	}
+
+	cases := []testCase{
+		{
+			name:                "does not exist",
+			identity:            identityHash.New(test.FactoryRandomByteSlice()),
+			preCondition:        func(_ *testing.T, _ *instance) {},
+			expectedAnnotations: []*annotation.Instance{},
+			expectedStatus:      status.NotFound,
+		},
+		func() testCase {
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			m := newAnnotation(id, nil)
+			return testCase{
+				name:     "exists",
+				identity: id,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id, m))
+				},
+				expectedAnnotations: []*annotation.Instance{m},
+				expectedStatus:      status.Success,
+			}
+		}(),
+		func() testCase {
+			previousId := identityHash.New(test.FactoryRandomByteSlice())
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			m1 := newAnnotation(previousId, nil)
+			m2 := newAnnotation(id, previousId)
+			return testCase{
+				name:     "previous identity",
+				identity: id,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(previousId, m1))
+					assert.Equal(t, status.Success, sut.Create(id, m2))
+				},
+				expectedAnnotations: []*annotation.Instance{m2, m1},
+				expectedStatus:      status.Success,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				sut := newSUT(t, path)
+				defer sut.Close()
+				cases[i].preCondition(t, sut)
+
+				m, result := sut.FindByIdentity(cases[i].identity)
+
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expectedAnnotations), testInternal.Marshal(t, m))
+				assert.Equal(t, cases[i].expectedStatus, result)
+			},
+		)
+	}
+}
+
+// TestStore_FindDescendants tests store.FindDescendants.
+func TestStore_FindDescendants(t *testing.T) {
+	type testCase struct {
+		name   string
+		reopen bool
+	}
+
+	cases := []testCase{
+		{name: "open", reopen: false},
+		{name: "reopened", reopen: true},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id3 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m1 := newAnnotation(id1, nil)
+				m2 := newAnnotation(id2, id1)
+				m3 := newAnnotation(id3, id2)
+				m4 := newAnnotation(id1, id3)
+				sut := newSUT(t, path)
+				assert.Equal(t, status.Success, sut.Create(id1, m1))
+				assert.Equal(t, status.Success, sut.Create(id2, m2))
+				assert.Equal(t, status.Success, sut.Create(id3, m3))
+				assert.Equal(t, status.Success, sut.Append(id1, m4))
+				if cases[i].reopen {
+					assert.NoError(t, sut.Close())
+					sut = newSUT(t, path)
+				}
+				defer sut.Close()
+
+				node, result := sut.FindDescendants(id1)
+
+				assert.Equal(t, status.Success, result)
+				assert.Equal(
+					t,
+					testInternal.Marshal(
+						t,
+						&lineage.Node{
+							Identity:    id1,
+							Annotations: []*annotation.Instance{m1, m4},
+							Children: []*lineage.Node{
+								{
+									Identity:    id2,
+									Annotations: []*annotation.Instance{m2},
+									Children: []*lineage.Node{
+										{
+											Identity:    id3,
+											Annotations: []*annotation.Instance{m3},
+											Children:    []*lineage.Node{},
+										},
+									},
+								},
+							},
+						},
+					),
+					testInternal.Marshal(t, node),
+				)
+
+				_, result = sut.FindDescendants(identityHash.New(test.FactoryRandomByteSlice()))
+
+				assert.Equal(t, status.NotFound, result)
+			},
+		)
+	}
+}
+
+// TestStore_FindDescendantsStoreIdentity tests that derived identities are those annotations are stored against (not
+// the annotations' current identities) whether or not the store is reopened.
+func TestStore_FindDescendantsStoreIdentity(t *testing.T) {
+	type testCase struct {
+		name   string
+		reopen bool
+	}
+
+	cases := []testCase{
+		{name: "open", reopen: false},
+		{name: "reopened", reopen: true},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m1 := newAnnotation(id1, nil)
+				m2 := newAnnotation(identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32)), id1)
+				sut := newSUT(t, path)
+				assert.Equal(t, status.Success, sut.Create(id1, m1))
+				assert.Equal(t, status.Success, sut.Create(id2, m2))
+				if cases[i].reopen {
+					assert.NoError(t, sut.Close())
+					sut = newSUT(t, path)
+				}
+				defer sut.Close()
+
+				node, result := sut.FindDescendants(id1)
+
+				assert.Equal(t, status.Success, result)
+				assert.Equal(
+					t,
+					testInternal.Marshal(
+						t,
+						&lineage.Node{
+							Identity:    id1,
+							Annotations: []*annotation.Instance{m1},
+							Children: []*lineage.Node{
+								{Identity: id2, Annotations: []*annotation.Instance{m2}, Children: []*lineage.Node{}},
+							},
+						},
+					),
+					testInternal.Marshal(t, node),
+				)
+			},
+		)
+	}
+}
+
+// TestStore_Create tests store.Create.
+func TestStore_Create(t *testing.T) {
+	type testCase struct {
+		name                string
+		identity            identity.Contract
+		m                   *annotation.Instance
+		postCondition       func(t *testing.T, sut *instance)
+		expectedAnnotations []*annotation.Instance
+	}
+
+	cases := []testCase{
+		func() testCase {
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			m := newAnnotation(id, nil)
+			return testCase{
+				name:                "create once",
+				identity:            id,
+				m:                   m,
+				postCondition:       func(_ *testing.T, _ *instance) {},
+				expectedAnnotations: []*annotation.Instance{m},
+			}
+		}(),
+		func() testCase {
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			m1 := newAnnotation(id, nil)
+			m2 := newAnnotation(id, nil)
+			return testCase{
+				name:     "create twice",
+				identity: id,
+				m:        m1,
+				postCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Exists, sut.Create(id, m2))
+				},
+				expectedAnnotations: []*annotation.Instance{m1},
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				sut := newSUT(t, path)
+				defer sut.Close()
+
+				result := sut.Create(cases[i].identity, cases[i].m)
+
+				assert.Equal(t, status.Success, result)
+				cases[i].postCondition(t, sut)
+				savedModel, result := sut.FindByIdentity(cases[i].identity)
+				assert.Equal(t, status.Success, result)
+				assert.Equal(
+					t,
+					testInternal.Marshal(t, cases[i].expectedAnnotations),
+					testInternal.Marshal(t, savedModel),
+				)
+			},
+		)
+	}
+}
+
+// TestStore_Append tests store.Append.
+func TestStore_Append(t *testing.T) {
+	type testCase struct {
+		name                string
+		identity            identity.Contract
+		m                   *annotation.Instance
+		preCondition        func(t *testing.T, sut *instance)
+		expectedStatus      status.Value
+		expectedAnnotations []*annotation.Instance
+	}
+
+	cases := []testCase{
+		func() testCase {
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			return testCase{
+				name:                "append to missing identity",
+				identity:            id,
+				m:                   newAnnotation(id, nil),
+				preCondition:        func(_ *testing.T, _ *instance) {},
+				expectedStatus:      status.NotFound,
+				expectedAnnotations: []*annotation.Instance{},
+			}
+		}(),
+		func() testCase {
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			m1 := newAnnotation(id, nil)
+			m2 := newAnnotation(id, nil)
+			return testCase{
+				name:     "append once",
+				identity: id,
+				m:        m2,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id, m1))
+				},
+				expectedStatus:      status.Success,
+				expectedAnnotations: []*annotation.Instance{m1, m2},
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				sut := newSUT(t, path)
+				defer sut.Close()
+				cases[i].preCondition(t, sut)
+
+				result := sut.Append(cases[i].identity, cases[i].m)
+
+				assert.Equal(t, cases[i].expectedStatus, result)
+				savedModel, _ := sut.FindByIdentity(cases[i].identity)
+				assert.Equal(
+					t,
+					testInternal.Marshal(t, cases[i].expectedAnnotations),
+					testInternal.Marshal(t, savedModel),
+				)
+			},
+		)
+	}
+}
+
+// TestStore_Reopen tests that annotations survive closing and reopening the store.
+func TestStore_Reopen(t *testing.T) {
+	path, cleanUp := newPath(t)
+	defer cleanUp()
+	id := identityHash.New(test.FactoryRandomByteSlice())
+	m1 := newAnnotation(id, nil)
+	m2 := newAnnotation(id, nil)
+	sut := newSUT(t, path)
+	assert.Equal(t, status.Success, sut.Create(id, m1))
+	assert.Equal(t, status.Success, sut.Append(id, m2))
+	assert.NoError(t, sut.Close())
+
+	sut = newSUT(t, path)
+	defer sut.Close()
+	result, s := sut.FindByIdentity(id)
+
+	assert.Equal(t, status.Success, s)
+	assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m1, m2}), testInternal.Marshal(t, result))
+	for i := range result {
+		assert.IsType(t, &metadata.Instance{}, result[i].Metadata)
+	}
+	assert.Equal(t, status.Exists, sut.Create(id, newAnnotation(id, nil)))
+}
+
+// TestStore_ReopenWithEncoding tests that annotations stored as CBOR are restored with typed metadata.
+func TestStore_ReopenWithEncoding(t *testing.T) {
+	path, cleanUp := newPath(t)
+	defer cleanUp()
+	id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	m1 := newAnnotation(id1, nil)
+	m2 := newAnnotation(id2, id1)
+	sut := newSUTWithEncoding(t, path, cbor.New())
+	assert.Equal(t, status.Success, sut.Create(id1, m1))
+	assert.Equal(t, status.Success, sut.Create(id2, m2))
+	assert.NoError(t, sut.Close())
+
+	sut = newSUTWithEncoding(t, path, cbor.New())
+	defer sut.Close()
+	result, s := sut.FindByIdentity(id2)
+	byUnique, uniqueStatus := sut.FindByUnique(m1.Unique)
+
+	assert.Equal(t, status.Success, s)
+	assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m2, m1}), testInternal.Marshal(t, result))
+	for i := range result {
+		assert.IsType(t, &metadata.Instance{}, result[i].Metadata)
+	}
+	assert.Equal(t, status.Success, uniqueStatus)
+	assert.Equal(t, testInternal.Marshal(t, m1), testInternal.Marshal(t, byUnique))
+}
+
+// TestStore_EncodingMismatch tests that a database is not opened with an encoding other than the one it was written
+// with.
+func TestStore_EncodingMismatch(t *testing.T) {
+	path, cleanUp := newPath(t)
+	defer cleanUp()
+	id := identityHash.New(test.FactoryRandomByteSlice())
+	sut := newSUT(t, path)
+	assert.Equal(t, status.Success, sut.Create(id, newAnnotation(id, nil)))
+	assert.NoError(t, sut.Close())
+
+	_, err := NewWithEncoding(path, identityFactory.New(), metadataFactory.New(nil), cbor.New())
+
+	assert.Error(t, err)
+	sut = newSUT(t, path)
+	defer sut.Close()
+	_, s := sut.FindByIdentity(id)
+	assert.Equal(t, status.Success, s)
+}
+
+// TestStore_EncodingMissing tests that a database holding annotations without a recorded encoding is not opened.
+func TestStore_EncodingMissing(t *testing.T) {
+	path, cleanUp := newPath(t)
+	defer cleanUp()
+	id := identityHash.New(test.FactoryRandomByteSlice())
+	sut := newSUT(t, path)
+	assert.Equal(t, status.Success, sut.Create(id, newAnnotation(id, nil)))
+	require.NoError(t, sut.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(metaBucket).Delete(encodingKey)
+	}))
+	assert.NoError(t, sut.Close())
+
+	_, err := New(path, identityFactory.New(), metadataFactory.New(nil))
+
+	assert.Error(t, err)
+}
+
+// TestStore_Unrecognized tests that annotations with metadata the store's factories do not recognize are restored
+// unchanged.
+func TestStore_Unrecognized(t *testing.T) {
+	for _, encoding := range []encoding.Contract{jsonEncoding.New(), cbor.New()} {
+		t.Run(
+			encoding.Kind(),
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m := annotation.New(
+					test.FactoryRandomFixedLengthAlphanumericString(26),
+					id,
+					nil,
+					opaqueMetadata.New(
+						test.FactoryRandomString(),
+						json.RawMessage(`{"z":"`+test.FactoryRandomString()+`","a":[1.50,null]}`),
+					),
+				)
+				sut := newSUTWithEncoding(t, path, encoding)
+				assert.Equal(t, status.Success, sut.Create(id, m))
+				assert.NoError(t, sut.Close())
+
+				sut = newSUTWithEncoding(t, path, encoding)
+				defer sut.Close()
+				result, s := sut.FindByIdentity(id)
+
+				assert.Equal(t, status.Success, s)
+				require.Len(t, result, 1)
+				assert.IsType(t, &opaqueMetadata.Instance{}, result[0].Metadata)
+				assert.Equal(t, testInternal.Marshal(t, m), testInternal.Marshal(t, result[0]))
+			},
+		)
+	}
+}
+
+// TestStore_FindByUnique tests store.FindByUnique.
+func TestStore_FindByUnique(t *testing.T) {
+	type testCase struct {
+		name           string
+		unique         string
+		expected       *annotation.Instance
+		expectedStatus status.Value
+	}
+
+	id := identityHash.New(test.FactoryRandomByteSlice())
+	m := newAnnotation(id, nil)
+	cases := []testCase{
+		{
+			name:           "does not exist",
+			unique:         test.FactoryRandomFixedLengthAlphanumericString(26),
+			expected:       nil,
+			expectedStatus: status.NotFound,
+		},
+		{
+			name:           "exists",
+			unique:         m.Unique,
+			expected:       m,
+			expectedStatus: status.Success,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				sut := newSUT(t, path)
+				defer sut.Close()
+				assert.Equal(t, status.Success, sut.Create(id, m))
+
+				result, s := sut.FindByUnique(cases[i].unique)
+
+				assert.Equal(t, cases[i].expectedStatus, s)
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expected), testInternal.Marshal(t, result))
+			},
+		)
+	}
+}
+
+// TestStore_DuplicateUnique tests that an annotation cannot be stored twice.
+func TestStore_DuplicateUnique(t *testing.T) {
+	path, cleanUp := newPath(t)
+	defer cleanUp()
+	sut := newSUT(t, path)
+	defer sut.Close()
+	id := identityHash.New(test.FactoryRandomByteSlice())
+	m := newAnnotation(id, nil)
+	assert.Equal(t, status.Success, sut.Create(id, m))
+
+	result := sut.Append(id, m)
+
+	assert.Equal(t, status.Exists, result)
+	annotations, _ := sut.FindByIdentity(id)
+	assert.Equal(t, 1, len(annotations))
+}
+
+// TestStore_FindByKind tests store.FindByKind.
+func TestStore_FindByKind(t *testing.T) {
+	type testCase struct {
+		name           string
+		kind           string
+		expectedCount  int
+		expectedStatus status.Value
+	}
+
+	cases := []testCase{
+		{
+			name:           "unknown kind",
+			kind:           test.FactoryRandomFixedLengthAlphanumericString(16),
+			expectedCount:  0,
+			expectedStatus: status.NotFound,
+		},
+		{
+			name:           "known kind",
+			kind:           metadata.Kind,
+			expectedCount:  3,
+			expectedStatus: status.Success,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				sut := newSUT(t, path)
+				defer sut.Close()
+				for n := 0; n < 3; n++ {
+					id := identityHash.New(test.FactoryRandomByteSlice())
+					assert.Equal(t, status.Success, sut.Create(id, newAnnotation(id, nil)))
+				}
+
+				result, s := sut.FindByKind(cases[i].kind)
+
+				assert.Equal(t, cases[i].expectedStatus, s)
+				assert.Equal(t, cases[i].expectedCount, len(result))
+				for r := range result {
+					assert.Equal(t, cases[i].kind, result[r].MetadataKind)
+				}
+			},
+		)
+	}
+}
+
+// TestStore_FindByCreated tests store.FindByCreated.
+func TestStore_FindByCreated(t *testing.T) {
+	path, cleanUp := newPath(t)
+	defer cleanUp()
+	sut := newSUT(t, path)
+	defer sut.Close()
+	base := time.Date(2020, 5, 5, 15, 32, 0, 0, time.UTC)
+	uniques := make([]string, 0)
+	for n := 0; n < 5; n++ {
+		id := identityHash.New(test.FactoryRandomByteSlice())
+		m := newAnnotation(id, nil)
+		m.Created = base.Add(time.Duration(4-n) * time.Minute).Format(time.RFC3339Nano)
+		uniques = append(uniques, m.Unique)
+		assert.Equal(t, status.Success, sut.Create(id, m))
+	}
+
+	result, s := sut.FindByCreated(base.Add(time.Minute), base.Add(4*time.Minute))
+
+	assert.Equal(t, status.Success, s)
+	if assert.Equal(t, 3, len(result)) {
+		assert.Equal(t, uniques[3], result[0].Unique)
+		assert.Equal(t, uniques[2], result[1].Unique)
+		assert.Equal(t, uniques[1], result[2].Unique)
+	}
+	_, s = sut.FindByCreated(base.Add(time.Hour), base.Add(2*time.Hour))
+	assert.Equal(t, status.NotFound, s)
+}
+
+// TestStore_FindByCreatedBefore1970 tests that annotations created before 1970 are ordered before later ones.
+func TestStore_FindByCreatedBefore1970(t *testing.T) {
+	path, cleanUp := newPath(t)
+	defer cleanUp()
+	sut := newSUT(t, path)
+	defer sut.Close()
+	epoch := time.Unix(0, 0).UTC()
+	uniques := make([]string, 0)
+	for _, created := range []time.Time{epoch.Add(-time.Hour), epoch.Add(time.Hour), epoch.Add(-time.Minute)} {
+		id := identityHash.New(test.FactoryRandomByteSlice())
+		m := newAnnotation(id, nil)
+		m.Created = created.Format(time.RFC3339Nano)
+		uniques = append(uniques, m.Unique)
+		assert.Equal(t, status.Success, sut.Create(id, m))
+	}
+
+	result, s := sut.FindByCreated(epoch.Add(-2*time.Hour), epoch.Add(2*time.Hour))
+
+	assert.Equal(t, status.Success, s)
+	if assert.Equal(t, 3, len(result)) {
+		assert.Equal(t, uniques[0], result[0].Unique)
+		assert.Equal(t, uniques[2], result[1].Unique)
+		assert.Equal(t, uniques[1], result[2].Unique)
+	}
+}
+
+// TestStore_Undecodable tests that an annotation that cannot be decoded fails lookups rather than being skipped.
+func TestStore_Undecodable(t *testing.T) {
+	path, cleanUp := newPath(t)
+	defer cleanUp()
+	sut := newSUT(t, path)
+	defer sut.Close()
+	id := identityHash.New(test.FactoryRandomByteSlice())
+	m1 := newAnnotation(id, nil)
+	m2 := newAnnotation(id, nil)
+	assert.Equal(t, status.Success, sut.Create(id, m1))
+	assert.Equal(t, status.Success, sut.Append(id, m2))
+	require.NoError(t, sut.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(annotationsBucket).Put([]byte(m2.Unique), []byte("not an annotation"))
+	}))
+
+	_, s := sut.FindByIdentity(id)
+	assert.Equal(t, status.Unknown, s)
+
+	_, s = sut.FindByUnique(m2.Unique)
+	assert.Equal(t, status.Unknown, s)
+
+	_, s = sut.FindByKind(m2.MetadataKind)
+	assert.Equal(t, status.Unknown, s)
+
+	_, s = sut.FindDescendants(id)
+	assert.Equal(t, status.Unknown, s)
+
+	result, s := sut.FindByUnique(m1.Unique)
+	assert.Equal(t, status.Success, s)
+	assert.Equal(t, testInternal.Marshal(t, m1), testInternal.Marshal(t, result))
+}
@@ -0,0 +1,308 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// chain implements a tamper-evident store decorator that hash-chains every annotation written through it.
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/jsonlog"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/canonical"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// Link records an annotation's position within the chain.
+type Link struct {
+	Unique           string
+	Identity         identity.Contract
+	PreviousIdentity []byte
+	PreviousGlobal   []byte
+	Hash             []byte
+}
+
+// record is the persisted form of a link.
+type record struct {
+	Unique           string          `json:"unique"`
+	IdentityKind     string          `json:"identityType"`
+	Identity         json.RawMessage `json:"identity"`
+	PreviousIdentity []byte          `json:"previousIdentity,omitempty"`
+	PreviousGlobal   []byte          `json:"previousGlobal,omitempty"`
+	Hash             []byte          `json:"hash"`
+}
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	m            sync.Mutex
+	store        store.Contract
+	hashProvider hashprovider.Contract
+	log          *jsonlog.Log
+	links        []*Link
+	heads        map[string][]byte
+	uniques      map[string]struct{}
+}
+
+// New is a factory function that returns an initialized instance decorating store; the chain is held in memory only
+// (see Open).
+func New(store store.Contract, hashProvider hashprovider.Contract) *instance {
+	return &instance{
+		m:            sync.Mutex{},
+		store:        store,
+		hashProvider: hashProvider,
+		links:        make([]*Link, 0),
+		heads:        make(map[string][]byte),
+		uniques:      make(map[string]struct{}),
+	}
+}
+
+// Open is a factory function that returns an initialized instance decorating store whose chain is persisted to the
+// log at path (which is created if necessary).
+//
+// The chain is reloaded from the log and verified against store; an error is returned if the log cannot be read or
+// the chain is broken.  An annotation stored without its link being persisted (for example, by a crash between the
+// two writes) is not part of the chain.
+func Open(
+	path string,
+	store store.Contract,
+	hashProvider hashprovider.Contract,
+	identityFactory identityFactory.Contract) (*instance, error) {
+
+	i := New(store, hashProvider)
+	log, err := jsonlog.Open(path, func(data []byte) error {
+		var r record
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		id := identityFactory.Create(r.IdentityKind, r.Identity)
+		if id == nil {
+			id = opaqueIdentity.New(r.IdentityKind, r.Identity)
+		}
+		i.add(&Link{
+			Unique:           r.Unique,
+			Identity:         id,
+			PreviousIdentity: r.PreviousIdentity,
+			PreviousGlobal:   r.PreviousGlobal,
+			Hash:             r.Hash,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if broken := i.Verify(); broken != nil {
+		_ = log.Close()
+		return nil, fmt.Errorf("chain broken at annotation %s", broken.Unique)
+	}
+	i.log = log
+	return i, nil
+}
+
+// Close closes the persisted log (if any).
+func (i *instance) Close() error {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if i.log == nil {
+		return nil
+	}
+	return i.log.Close()
+}
+
+// add appends a link to the chain.
+func (i *instance) add(l *Link) {
+	i.links = append(i.links, l)
+	i.heads[l.Identity.Printable()] = l.Hash
+	i.uniques[l.Unique] = struct{}{}
+}
+
+// persist writes a link to the log (if any).
+func (i *instance) persist(l *Link) error {
+	if i.log == nil {
+		return nil
+	}
+	data, err := json.Marshal(l.Identity)
+	if err != nil {
+		return err
+	}
+	return i.log.Append(
+		record{
+			Unique:           l.Unique,
+			IdentityKind:     l.Identity.Kind(),
+			Identity:         data,
+			PreviousIdentity: l.PreviousIdentity,
+			PreviousGlobal:   l.PreviousGlobal,
+			Hash:             l.Hash,
+		},
+	)
+}
+
+// head returns the hash of the most recent link in the global chain.
+func (i *instance) head() []byte {
+	if len(i.links) == 0 {
+		return nil
+	}
+	return i.links[len(i.links)-1].Hash
+}
+
+// digest returns the hash linking an annotation (by its canonical JSON encoding) to its predecessors.
+func (i *instance) digest(previousIdentity, previousGlobal []byte, m *annotation.Instance) ([]byte, error) {
+	content, err := canonical.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	for _, field := range [][]byte{previousIdentity, previousGlobal, content} {
+		length := make([]byte, 8)
+		binary.BigEndian.PutUint64(length, uint64(len(field)))
+		b.Write(length)
+		b.Write(field)
+	}
+	return i.hashProvider.Derive(b.Bytes()), nil
+}
+
+// link delegates to write and, if successful, adds the annotation to the chain; status.Unknown is returned without
+// writing if the annotation cannot be encoded, and if its link cannot be persisted.
+//
+// The annotation is hashed after write returns so that the link covers any fields set by the stores beneath the chain
+// (for example, the signature set by a signature store).
+func (i *instance) link(
+	id identity.Contract,
+	m *annotation.Instance,
+	write func(id identity.Contract, m *annotation.Instance) status.Value) status.Value {
+
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if _, exists := i.uniques[m.Unique]; exists {
+		return status.Exists
+	}
+
+	if _, err := canonical.Marshal(m); err != nil {
+		return status.Unknown
+	}
+	result := write(id, m)
+	if result != status.Success {
+		return result
+	}
+
+	l := &Link{
+		Unique:           m.Unique,
+		Identity:         id,
+		PreviousIdentity: i.heads[id.Printable()],
+		PreviousGlobal:   i.head(),
+	}
+	var err error
+	if l.Hash, err = i.digest(l.PreviousIdentity, l.PreviousGlobal, m); err != nil {
+		return status.Unknown
+	}
+	if err := i.persist(l); err != nil {
+		return status.Unknown
+	}
+	i.add(l)
+	return result
+}
+
+// FindByIdentity returns annotations and status corresponding to identity.
+func (i *instance) FindByIdentity(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	return i.store.FindByIdentity(id)
+}
+
+// FindDescendants returns the tree of identities derived from identity (with their annotations) and status.
+func (i *instance) FindDescendants(id identity.Contract) (*lineage.Node, status.Value) {
+	return i.store.FindDescendants(id)
+}
+
+// Create stores annotations corresponding to a new identity and returns status.
+func (i *instance) Create(id identity.Contract, m *annotation.Instance) status.Value {
+	return i.link(id, m, i.store.Create)
+}
+
+// Append stores annotations corresponding to identity and returns status.
+func (i *instance) Append(id identity.Contract, m *annotation.Instance) status.Value {
+	return i.link(id, m, i.store.Append)
+}
+
+// Head returns the hash at the head of the global chain.
+func (i *instance) Head() []byte {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	return i.head()
+}
+
+// IdentityHead returns the hash at the head of identity's chain.
+func (i *instance) IdentityHead(id identity.Contract) []byte {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	return i.heads[id.Printable()]
+}
+
+// find returns the stored annotation for the given link; each identity's annotations are read from the store once
+// and cached in stored (keyed by identity, then unique value).
+func (i *instance) find(l *Link, stored map[string]map[string]*annotation.Instance) *annotation.Instance {
+	idAsString := l.Identity.Printable()
+	byUnique, exists := stored[idAsString]
+	if !exists {
+		byUnique = make(map[string]*annotation.Instance)
+		if annotations, result := i.store.FindByIdentity(l.Identity); result == status.Success {
+			for a := range annotations {
+				byUnique[annotations[a].Unique] = annotations[a]
+			}
+		}
+		stored[idAsString] = byUnique
+	}
+	return byUnique[l.Unique]
+}
+
+// Verify walks the chain in insertion order and returns the first broken link (or nil if the chain is intact).
+func (i *instance) Verify() *Link {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	var previousGlobal []byte
+	previousIdentity := make(map[string][]byte)
+	stored := make(map[string]map[string]*annotation.Instance)
+	for n := range i.links {
+		l := i.links[n]
+		idAsString := l.Identity.Printable()
+		if !bytes.Equal(l.PreviousGlobal, previousGlobal) || !bytes.Equal(l.PreviousIdentity, previousIdentity[idAsString]) {
+			return l
+		}
+
+		m := i.find(l, stored)
+		if m == nil {
+			return l
+		}
+		if hash, err := i.digest(l.PreviousIdentity, l.PreviousGlobal, m); err != nil || !bytes.Equal(l.Hash, hash) {
+			return l
+		}
+
+		previousGlobal = l.Hash
+		previousIdentity[idAsString] = l.Hash
+	}
+	return nil
+}
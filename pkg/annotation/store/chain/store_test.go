@@ -0,0 +1,355 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package chain
+
+import (
+	"crypto"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/signature"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSUT returns a new system under test.
+func newSUT() *instance {
+	return New(memory.New(), sha256.New())
+}
+
+// newAnnotation returns a new annotation for the given identity.
+func newAnnotation(id identity.Contract) *annotation.Instance {
+	return annotation.New(test.FactoryRandomFixedLengthAlphanumericString(26), id, nil, metadataStub.NewNullObject())
+}
+
+// TestInstance_Create tests instance.Create.
+func TestInstance_Create(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "delegates and links",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				m := newAnnotation(id)
+
+				result := sut.Create(id, m)
+
+				assert.Equal(t, status.Success, result)
+				annotations, _ := sut.FindByIdentity(id)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m}), testInternal.Marshal(t, annotations))
+				assert.NotNil(t, sut.Head())
+				assert.Equal(t, sut.Head(), sut.IdentityHead(id))
+			},
+		},
+		{
+			name: "underlying failure not linked",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				assert.Equal(t, status.Success, sut.Create(id, newAnnotation(id)))
+				head := sut.Head()
+
+				result := sut.Create(id, newAnnotation(id))
+
+				assert.Equal(t, status.Exists, result)
+				assert.Equal(t, head, sut.Head())
+			},
+		},
+		{
+			name: "unhashable annotation not written",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				m := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.New("kind", make(chan int)))
+
+				result := sut.Create(id, m)
+
+				assert.Equal(t, status.Unknown, result)
+				assert.Nil(t, sut.Head())
+				_, result = sut.FindByIdentity(id)
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_Append tests instance.Append.
+func TestInstance_Append(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "links to identity and global predecessors",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				id1 := identityHash.New(test.FactoryRandomByteSlice())
+				id2 := identityHash.New(test.FactoryRandomByteSlice())
+				assert.Equal(t, status.Success, sut.Create(id1, newAnnotation(id1)))
+				head1 := sut.IdentityHead(id1)
+				assert.Equal(t, status.Success, sut.Create(id2, newAnnotation(id2)))
+				head2 := sut.IdentityHead(id2)
+
+				result := sut.Append(id1, newAnnotation(id1))
+
+				assert.Equal(t, status.Success, result)
+				l := sut.links[len(sut.links)-1]
+				assert.Equal(t, head1, l.PreviousIdentity)
+				assert.Equal(t, head2, l.PreviousGlobal)
+				assert.Equal(t, l.Hash, sut.Head())
+			},
+		},
+		{
+			name: "overwrite rejected",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				m := newAnnotation(id)
+				assert.Equal(t, status.Success, sut.Create(id, m))
+
+				result := sut.Append(id, m)
+
+				assert.Equal(t, status.Exists, result)
+				annotations, _ := sut.FindByIdentity(id)
+				assert.Equal(t, 1, len(annotations))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_Verify tests instance.Verify.
+func TestInstance_Verify(t *testing.T) {
+	type testCase struct {
+		name         string
+		tamper       func(annotations []*annotation.Instance)
+		expectedSite int
+	}
+
+	cases := []testCase{
+		{
+			name:         "intact",
+			tamper:       func(_ []*annotation.Instance) {},
+			expectedSite: -1,
+		},
+		{
+			name: "altered metadata",
+			tamper: func(annotations []*annotation.Instance) {
+				annotations[2].Metadata = metadataStub.NewNullObject()
+			},
+			expectedSite: 2,
+		},
+		{
+			name: "altered created",
+			tamper: func(annotations []*annotation.Instance) {
+				annotations[1].Created = test.FactoryRandomString()
+			},
+			expectedSite: 1,
+		},
+		{
+			name: "altered unique",
+			tamper: func(annotations []*annotation.Instance) {
+				annotations[0].Unique = test.FactoryRandomFixedLengthAlphanumericString(27)
+			},
+			expectedSite: 0,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := newSUT()
+				annotations := make([]*annotation.Instance, 0)
+				id1 := identityHash.New(test.FactoryRandomByteSlice())
+				id2 := identityHash.New(test.FactoryRandomByteSlice())
+				for _, id := range []identity.Contract{id1, id2, id1, id2} {
+					m := newAnnotation(id)
+					annotations = append(annotations, m)
+					if sut.Append(id, m) == status.NotFound {
+						assert.Equal(t, status.Success, sut.Create(id, m))
+					}
+				}
+				cases[i].tamper(annotations)
+
+				result := sut.Verify()
+
+				if cases[i].expectedSite < 0 {
+					assert.Nil(t, result)
+					return
+				}
+				if assert.NotNil(t, result) {
+					assert.Equal(t, sut.links[cases[i].expectedSite], result)
+				}
+			},
+		)
+	}
+}
+
+// countingStore is a store that counts FindByIdentity calls.
+type countingStore struct {
+	store.Contract
+	count int
+}
+
+// FindByIdentity counts the call and delegates to the wrapped store.
+func (c *countingStore) FindByIdentity(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	c.count++
+	return c.Contract.FindByIdentity(id)
+}
+
+// TestInstance_VerifyReadsIdentitiesOnce tests that instance.Verify reads each identity from the store once.
+func TestInstance_VerifyReadsIdentitiesOnce(t *testing.T) {
+	s := &countingStore{Contract: memory.New()}
+	sut := New(s, sha256.New())
+	id1 := identityHash.New(test.FactoryRandomByteSlice())
+	id2 := identityHash.New(test.FactoryRandomByteSlice())
+	require.Equal(t, status.Success, sut.Create(id1, newAnnotation(id1)))
+	require.Equal(t, status.Success, sut.Create(id2, newAnnotation(id2)))
+	for n := 0; n < 10; n++ {
+		require.Equal(t, status.Success, sut.Append(id1, newAnnotation(id1)))
+	}
+	s.count = 0
+
+	result := sut.Verify()
+
+	assert.Nil(t, result)
+	assert.Equal(t, 2, s.count)
+}
+
+// TestInstance_Signed tests a chain decorating a signature store, which signs annotations as they are written.
+func TestInstance_Signed(t *testing.T) {
+	signer := signpkcs1v15.New(crypto.SHA256, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, sha256.New())
+	sut := New(signature.New(memory.New(), signer), sha256.New())
+	id := identityHash.New(test.FactoryRandomByteSlice())
+	m1, m2 := newAnnotation(id), newAnnotation(id)
+
+	require.Equal(t, status.Success, sut.Create(id, m1))
+	require.Equal(t, status.Success, sut.Append(id, m2))
+
+	assert.NotNil(t, m1.Signature)
+	assert.NotNil(t, m2.Signature)
+	assert.Nil(t, sut.Verify())
+}
+
+// newPath returns the path of a chain log in a new temporary directory and a function that removes it.
+func newPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "chain")
+	require.NoError(t, err)
+	return filepath.Join(dir, "chain.log"), func() { _ = os.RemoveAll(dir) }
+}
+
+// TestOpen tests Open.
+func TestOpen(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "chain reloaded",
+			test: func(t *testing.T) {
+				path, cleanup := newPath(t)
+				defer cleanup()
+				s := memory.New()
+				sut, err := Open(path, s, sha256.New(), identityFactory.New())
+				require.NoError(t, err)
+				id1 := identityHash.New(test.FactoryRandomByteSlice())
+				id2 := identityHash.New(test.FactoryRandomByteSlice())
+				m1 := newAnnotation(id1)
+				require.Equal(t, status.Success, sut.Create(id1, m1))
+				require.Equal(t, status.Success, sut.Create(id2, newAnnotation(id2)))
+				require.Equal(t, status.Success, sut.Append(id1, newAnnotation(id1)))
+				head, head1 := sut.Head(), sut.IdentityHead(id1)
+				require.NoError(t, sut.Close())
+
+				reopened, err := Open(path, s, sha256.New(), identityFactory.New())
+
+				require.NoError(t, err)
+				defer func() { _ = reopened.Close() }()
+				assert.Equal(t, head, reopened.Head())
+				assert.Equal(t, head1, reopened.IdentityHead(id1))
+				assert.Equal(t, status.Exists, reopened.Append(id1, m1))
+				require.Equal(t, status.Success, reopened.Append(id2, newAnnotation(id2)))
+				assert.Equal(t, head, reopened.links[len(reopened.links)-1].PreviousGlobal)
+				assert.Nil(t, reopened.Verify())
+			},
+		},
+		{
+			name: "store does not match chain",
+			test: func(t *testing.T) {
+				path, cleanup := newPath(t)
+				defer cleanup()
+				sut, err := Open(path, memory.New(), sha256.New(), identityFactory.New())
+				require.NoError(t, err)
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				require.Equal(t, status.Success, sut.Create(id, newAnnotation(id)))
+				require.NoError(t, sut.Close())
+
+				reopened, err := Open(path, memory.New(), sha256.New(), identityFactory.New())
+
+				assert.Nil(t, reopened)
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "undecodable log",
+			test: func(t *testing.T) {
+				path, cleanup := newPath(t)
+				defer cleanup()
+				require.NoError(t, ioutil.WriteFile(path, []byte("{\n"), 0600))
+
+				sut, err := Open(path, memory.New(), sha256.New(), identityFactory.New())
+
+				assert.Nil(t, sut)
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
@@ -16,6 +16,7 @@ package store
 
 import (
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
 	"github.com/project-alvarium/go-sdk/pkg/identity"
 	"github.com/project-alvarium/go-sdk/pkg/status"
 )
@@ -25,6 +26,9 @@ type Contract interface {
 	// FindByIdentity returns annotations and status corresponding to identity.
 	FindByIdentity(id identity.Contract) ([]*annotation.Instance, status.Value)
 
+	// FindDescendants returns the tree of identities derived from identity (with their annotations) and status.
+	FindDescendants(id identity.Contract) (*lineage.Node, status.Value)
+
 	// Create stores annotations corresponding to a new identity and returns status.
 	Create(id identity.Contract, m *annotation.Instance) status.Value
 
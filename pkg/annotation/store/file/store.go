@@ -0,0 +1,469 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// file implements a durable, append-only annotation store persisted to a single local file.
+package file
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"sync"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding"
+	jsonEncoding "github.com/project-alvarium/go-sdk/pkg/annotation/encoding/json"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+const (
+	createOperation = "create"
+	appendOperation = "append"
+
+	// headerSize is the size of a record's frame header (payload length followed by payload checksum).
+	headerSize = 8
+
+	// format identifies a log's header record.
+	format = "alvarium-annotation-log"
+
+	// version is the log format version recorded in the header record of new logs.
+	version = 1
+)
+
+var (
+	// crcTable is the checksum table used to detect torn or corrupted records.
+	crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+	// errIncomplete is returned for a record whose frame extends beyond the end of the log.
+	errIncomplete = errors.New("record exceeds log size")
+
+	// errChecksum is returned for a record whose payload does not match its checksum.
+	errChecksum = errors.New("record checksum mismatch")
+)
+
+// record defines the structure of a single log entry; Annotation holds the annotation in the store's encoding and
+// IdentityKind and IdentityValue hold the kind and JSON of the identity it is stored against.
+type record struct {
+	Operation     string          `json:"operation"`
+	Identity      string          `json:"identity"`
+	IdentityKind  string          `json:"identityType"`
+	IdentityValue json.RawMessage `json:"identityValue"`
+	Annotation    json.RawMessage `json:"annotation"`
+}
+
+// header defines the structure of a log's first record, which identifies the encoding of the records that follow;
+// it is always JSON-encoded so it can be read before the log's encoding is known.
+type header struct {
+	Format   string `json:"format"`
+	Version  int    `json:"version"`
+	Encoding string `json:"encoding"`
+}
+
+// index maps an identity's printable value to the file offsets of its records.
+type index map[string][]int64
+
+// derivations maps an identity's printable value to the identities derived from it.
+type derivations map[string][]identity.Contract
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	m               sync.Mutex
+	file            *os.File
+	size            int64
+	index           index
+	derivations     derivations
+	identityFactory identityFactory.Contract
+	metadataFactory metadataFactory.Contract
+	encoding        encoding.Contract
+}
+
+// New is a factory function that opens (or creates) a JSON-encoded log at path, rebuilds its index, and returns
+// instance.
+func New(
+	path string,
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract) (*instance, error) {
+
+	return NewWithEncoding(path, identityFactory, metadataFactory, jsonEncoding.New())
+}
+
+// NewWithEncoding is a factory function that opens (or creates) a log at path whose records are written with
+// encoding, rebuilds its index, and returns instance.
+//
+// A new log records encoding's kind in its header; an error is returned (and the log is left untouched) if an
+// existing log's header is missing or unreadable or records a different encoding.
+func NewWithEncoding(
+	path string,
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract,
+	encoding encoding.Contract) (*instance, error) {
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &instance{
+		m:               sync.Mutex{},
+		file:            f,
+		index:           make(index),
+		derivations:     make(derivations),
+		identityFactory: identityFactory,
+		metadataFactory: metadataFactory,
+		encoding:        encoding,
+	}
+	if err := i.load(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return i, nil
+}
+
+// Close releases the underlying file.
+func (i *instance) Close() error {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	return i.file.Close()
+}
+
+// readFrame reads the record payload at offset (bounded by limit) and returns it with the offset of the next record;
+// the next offset is also returned with errChecksum.
+func (i *instance) readFrame(offset, limit int64) ([]byte, int64, error) {
+	if offset+headerSize > limit {
+		return nil, offset, errIncomplete
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := i.file.ReadAt(header, offset); err != nil {
+		return nil, offset, err
+	}
+
+	length := int64(binary.BigEndian.Uint32(header[:4]))
+	next := offset + headerSize + length
+	if next > limit {
+		return nil, offset, errIncomplete
+	}
+
+	payload := make([]byte, length)
+	if _, err := i.file.ReadAt(payload, offset+headerSize); err != nil {
+		return nil, offset, err
+	}
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, next, errChecksum
+	}
+	return payload, next, nil
+}
+
+// torn returns whether the damaged record at offset is a torn tail left by an interrupted write: no record with a
+// non-empty payload and a valid checksum begins anywhere after it.
+func (i *instance) torn(offset, size int64) (bool, error) {
+	tail := make([]byte, size-offset)
+	if _, err := i.file.ReadAt(tail, offset); err != nil {
+		return false, err
+	}
+
+	for start := int64(1); start+headerSize < int64(len(tail)); start++ {
+		length := int64(binary.BigEndian.Uint32(tail[start : start+4]))
+		end := start + headerSize + length
+		if length == 0 || end > int64(len(tail)) {
+			continue
+		}
+		if crc32.Checksum(tail[start+headerSize:end], crcTable) == binary.BigEndian.Uint32(tail[start+4:start+headerSize]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// loadHeader reads the log's header record and returns the offset of the first annotation record; an error is
+// returned if the header is missing or unreadable, or if the log was written with a different encoding or a newer
+// format version.
+func (i *instance) loadHeader(size int64) (int64, error) {
+	payload, next, err := i.readFrame(0, size)
+	if err != nil {
+		return 0, fmt.Errorf("log header: %v", err)
+	}
+
+	var h header
+	if err := json.Unmarshal(payload, &h); err != nil {
+		return 0, fmt.Errorf("log header: %v", err)
+	}
+	if h.Format != format {
+		return 0, fmt.Errorf("log header: unrecognized format %q", h.Format)
+	}
+	if h.Version > version {
+		return 0, fmt.Errorf("unsupported log version %d (current is %d)", h.Version, version)
+	}
+	if h.Encoding != i.encoding.Kind() {
+		return 0, fmt.Errorf("log written with %s encoding cannot be opened with %s encoding", h.Encoding, i.encoding.Kind())
+	}
+	return next, nil
+}
+
+// load scans the log to rebuild the identity and derivation indexes, writing a header record to a new (or emptied)
+// log.
+//
+// A torn tail left by an interrupted write -- a final record that is incomplete or fails its checksum, with no valid
+// record after it -- is truncated.  Any other damage (a corrupt record or length followed by further records, or a
+// record whose checksum is valid but whose content cannot be decoded) is returned as an error and the log is left
+// untouched, so records are never silently discarded.
+func (i *instance) load() error {
+	info, err := i.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	offset := int64(0)
+	if info.Size() > 0 {
+		if offset, err = i.loadHeader(info.Size()); err != nil {
+			return err
+		}
+	}
+
+	for offset < info.Size() {
+		payload, next, err := i.readFrame(offset, info.Size())
+		if err == errIncomplete || err == errChecksum {
+			torn, tornErr := i.torn(offset, info.Size())
+			if tornErr != nil {
+				return tornErr
+			}
+			if torn {
+				break
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("record at offset %d: %v", offset, err)
+		}
+
+		var r record
+		if err := i.encoding.Unmarshal(payload, &r); err != nil {
+			return fmt.Errorf("record at offset %d: %v", offset, err)
+		}
+		a, err := i.decode(r.Annotation)
+		if err != nil {
+			return fmt.Errorf("record at offset %d: %v", offset, err)
+		}
+		id := i.recordIdentity(r)
+		if id == nil {
+			return fmt.Errorf("record at offset %d: missing identity", offset)
+		}
+		i.index[r.Identity] = append(i.index[r.Identity], offset)
+		i.derive(id, a)
+		offset = next
+	}
+
+	if offset < info.Size() {
+		if err := i.file.Truncate(offset); err != nil {
+			return err
+		}
+		if err := i.file.Sync(); err != nil {
+			return err
+		}
+	}
+	i.size = offset
+
+	if i.size == 0 {
+		payload, err := json.Marshal(header{Format: format, Version: version, Encoding: i.encoding.Kind()})
+		if err != nil {
+			return err
+		}
+		return i.writeFrame(payload)
+	}
+	return nil
+}
+
+// read returns the annotation stored in the record at offset.
+func (i *instance) read(offset int64) (*annotation.Instance, error) {
+	payload, _, err := i.readFrame(offset, i.size)
+	if err != nil {
+		return nil, err
+	}
+
+	var r record
+	if err := i.encoding.Unmarshal(payload, &r); err != nil {
+		return nil, err
+	}
+	return i.decode(r.Annotation)
+}
+
+// recordIdentity returns the identity record is stored against, creating it with the injected identity factory (or as
+// an opaque identity if the factory does not recognize its kind); nil is returned if the record has no identity.
+func (i *instance) recordIdentity(r record) identity.Contract {
+	if r.IdentityKind == "" {
+		return nil
+	}
+	if id := i.identityFactory.Create(r.IdentityKind, r.IdentityValue); id != nil {
+		return id
+	}
+	return opaqueIdentity.New(r.IdentityKind, r.IdentityValue)
+}
+
+// decode converts a record's encoded annotation into an annotation using the injected factories.
+func (i *instance) decode(data []byte) (*annotation.Instance, error) {
+	var a annotation.Instance
+	a.SetIdentityFactory(i.identityFactory)
+	a.SetMetadataFactory(i.metadataFactory)
+	if err := i.encoding.Unmarshal(data, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// derive records that identity was derived from each of the annotation's predecessors.
+func (i *instance) derive(id identity.Contract, m *annotation.Instance) {
+	idAsString := id.Printable()
+	predecessors := m.Predecessors()
+	for p := range predecessors {
+		previous := predecessors[p].Printable()
+		if previous == idAsString || i.derived(previous, idAsString) {
+			continue
+		}
+		i.derivations[previous] = append(i.derivations[previous], id)
+	}
+}
+
+// derived returns whether the identity with printable value id is recorded as derived from previous.
+func (i *instance) derived(previous, id string) bool {
+	for d := range i.derivations[previous] {
+		if i.derivations[previous][d].Printable() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFrame appends payload to the log as a record and flushes it to stable storage; a failed write is rolled back.
+func (i *instance) writeFrame(payload []byte) error {
+	frame := make([]byte, headerSize, headerSize+len(payload))
+	binary.BigEndian.PutUint32(frame[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:], crc32.Checksum(payload, crcTable))
+	frame = append(frame, payload...)
+
+	if _, err := i.file.WriteAt(frame, i.size); err != nil {
+		_ = i.file.Truncate(i.size)
+		return err
+	}
+	if err := i.file.Sync(); err != nil {
+		_ = i.file.Truncate(i.size)
+		return err
+	}
+	i.size += int64(len(frame))
+	return nil
+}
+
+// write appends a record to the log and flushes it to stable storage before updating the index.
+func (i *instance) write(operation string, id identity.Contract, m *annotation.Instance) status.Value {
+	marshaledAnnotation, err := i.encoding.Marshal(m)
+	if err != nil {
+		return status.Unknown
+	}
+
+	marshaledIdentity, err := json.Marshal(id)
+	if err != nil {
+		return status.Unknown
+	}
+
+	idAsString := id.Printable()
+	payload, err := i.encoding.Marshal(
+		record{
+			Operation:     operation,
+			Identity:      idAsString,
+			IdentityKind:  id.Kind(),
+			IdentityValue: marshaledIdentity,
+			Annotation:    marshaledAnnotation,
+		},
+	)
+	if err != nil {
+		return status.Unknown
+	}
+
+	offset := i.size
+	if err := i.writeFrame(payload); err != nil {
+		return status.Unknown
+	}
+
+	i.index[idAsString] = append(i.index[idAsString], offset)
+	i.derive(id, m)
+	return status.Success
+}
+
+// fetch returns the annotations stored directly against identity.
+func (i *instance) fetch(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	offsets, exists := i.index[id.Printable()]
+	if !exists {
+		return nil, status.NotFound
+	}
+
+	annotations := make([]*annotation.Instance, 0, len(offsets))
+	for o := range offsets {
+		m, err := i.read(offsets[o])
+		if err != nil {
+			return nil, status.Unknown
+		}
+		annotations = append(annotations, m)
+	}
+	return annotations, status.Success
+}
+
+// FindByIdentity returns annotations and status corresponding to identity.
+func (i *instance) FindByIdentity(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	return lineage.Find(id, i.fetch)
+}
+
+// children returns the identities derived directly from identity.
+func (i *instance) children(id identity.Contract) ([]identity.Contract, status.Value) {
+	return i.derivations[id.Printable()], status.Success
+}
+
+// FindDescendants returns the tree of identities derived from identity (with their annotations) and status.
+func (i *instance) FindDescendants(id identity.Contract) (*lineage.Node, status.Value) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	return lineage.Descendants(id, i.fetch, i.children)
+}
+
+// Create stores annotations corresponding to a new identity and returns status.
+func (i *instance) Create(id identity.Contract, m *annotation.Instance) status.Value {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if _, exists := i.index[id.Printable()]; exists {
+		return status.Exists
+	}
+	return i.write(createOperation, id, m)
+}
+
+// Append stores annotations corresponding to identity and returns status.
+func (i *instance) Append(id identity.Contract, m *annotation.Instance) status.Value {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if _, exists := i.index[id.Printable()]; !exists {
+		return status.NotFound
+	}
+	return i.write(appendOperation, id, m)
+}
@@ -0,0 +1,791 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package file
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
+	jsonEncoding "github.com/project-alvarium/go-sdk/pkg/annotation/encoding/json"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	metadataStubFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	pkiFactory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signerMetadata is the signer metadata embedded in every test annotation.
+var signerMetadata = metadataStub.NewNullObject()
+
+// newPath returns the path of a log file within a new temporary directory and a function to remove it.
+func newPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "store")
+	require.NoError(t, err)
+	return filepath.Join(dir, "annotations.log"), func() { _ = os.RemoveAll(dir) }
+}
+
+// newSUT returns a new system under test.
+func newSUT(t *testing.T, path string) *instance {
+	return newSUTWithEncoding(t, path, jsonEncoding.New())
+}
+
+// newSUTWithEncoding returns a new system under test that stores annotations with encoding.
+func newSUTWithEncoding(t *testing.T, path string, encoding encoding.Contract) *instance {
+	sut, err := NewWithEncoding(
+		path,
+		identityFactory.New(),
+		metadataFactory.New(
+			[]metadataFactory.Contract{
+				pkiFactory.New([]metadataFactory.Contract{metadataStubFactory.New(signerMetadata)}),
+			},
+		),
+		encoding,
+	)
+	require.NoError(t, err)
+	return sut
+}
+
+// newAnnotation returns a new annotation for the given identities.
+func newAnnotation(id, previous identity.Contract) *annotation.Instance {
+	return annotation.New(
+		test.FactoryRandomFixedLengthAlphanumericString(26),
+		id,
+		previous,
+		metadata.New(
+			test.FactoryRandomString(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			signerMetadata,
+		),
+	)
+}
+
+// TestStore_FindByIdentity tests store.FindByIdentity.
+func TestStore_FindByIdentity(t *testing.T) {
+	type testCase struct {
+		name                string
+		identity            identity.Contract
+		preCondition        func(t *testing.T, sut *instance)
+		expectedAnnotations []*annotation.Instance
+		expectedStatus      status.Value
+	}
+
+	cases := []testCase{
+		{
+			name:                "does not exist",
+			identity:            identityHash.New(test.FactoryRandomByteSlice()),
+			preCondition:        func(_ *testing.T, _ *instance) {},
+			expectedAnnotations: []*annotation.Instance{},
+			expectedStatus:      status.NotFound,
+		},
+		func() testCase {
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			m := newAnnotation(id, nil)
+			return testCase{
+				name:     "exists",
+				identity: id,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id, m))
+				},
+				expectedAnnotations: []*annotation.Instance{m},
+				expectedStatus:      status.Success,
+			}
+		}(),
+		func() testCase {
+			previousId := identityHash.New(test.FactoryRandomByteSlice())
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			m1 := newAnnotation(previousId, nil)
+			m2 := newAnnotation(id, previousId)
+			return testCase{
+				name:     "previous identity",
+				identity: id,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(previousId, m1))
+					assert.Equal(t, status.Success, sut.Create(id, m2))
+				},
+				expectedAnnotations: []*annotation.Instance{m2, m1},
+				expectedStatus:      status.Success,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				sut := newSUT(t, path)
+				defer sut.Close()
+				cases[i].preCondition(t, sut)
+
+				m, result := sut.FindByIdentity(cases[i].identity)
+
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expectedAnnotations), testInternal.Marshal(t, m))
+				assert.Equal(t, cases[i].expectedStatus, result)
+			},
+		)
+	}
+}
+
+// TestStore_FindDescendants tests store.FindDescendants.
+func TestStore_FindDescendants(t *testing.T) {
+	type testCase struct {
+		name   string
+		reopen bool
+	}
+
+	cases := []testCase{
+		{name: "open", reopen: false},
+		{name: "reopened", reopen: true},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id3 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m1 := newAnnotation(id1, nil)
+				m2 := newAnnotation(id2, id1)
+				m3 := newAnnotation(id3, id2)
+				m4 := newAnnotation(id1, id3)
+				sut := newSUT(t, path)
+				assert.Equal(t, status.Success, sut.Create(id1, m1))
+				assert.Equal(t, status.Success, sut.Create(id2, m2))
+				assert.Equal(t, status.Success, sut.Create(id3, m3))
+				assert.Equal(t, status.Success, sut.Append(id1, m4))
+				if cases[i].reopen {
+					assert.NoError(t, sut.Close())
+					sut = newSUT(t, path)
+				}
+				defer sut.Close()
+
+				node, result := sut.FindDescendants(id1)
+
+				assert.Equal(t, status.Success, result)
+				assert.Equal(
+					t,
+					testInternal.Marshal(
+						t,
+						&lineage.Node{
+							Identity:    id1,
+							Annotations: []*annotation.Instance{m1, m4},
+							Children: []*lineage.Node{
+								{
+									Identity:    id2,
+									Annotations: []*annotation.Instance{m2},
+									Children: []*lineage.Node{
+										{
+											Identity:    id3,
+											Annotations: []*annotation.Instance{m3},
+											Children:    []*lineage.Node{},
+										},
+									},
+								},
+							},
+						},
+					),
+					testInternal.Marshal(t, node),
+				)
+
+				_, result = sut.FindDescendants(identityHash.New(test.FactoryRandomByteSlice()))
+
+				assert.Equal(t, status.NotFound, result)
+			},
+		)
+	}
+}
+
+// TestStore_FindDescendantsStoreIdentity tests that derived identities are those annotations are stored against (not
+// the annotations' current identities) whether or not the store is reopened.
+func TestStore_FindDescendantsStoreIdentity(t *testing.T) {
+	type testCase struct {
+		name   string
+		reopen bool
+	}
+
+	cases := []testCase{
+		{name: "open", reopen: false},
+		{name: "reopened", reopen: true},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m1 := newAnnotation(id1, nil)
+				m2 := newAnnotation(identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32)), id1)
+				sut := newSUT(t, path)
+				assert.Equal(t, status.Success, sut.Create(id1, m1))
+				assert.Equal(t, status.Success, sut.Create(id2, m2))
+				if cases[i].reopen {
+					assert.NoError(t, sut.Close())
+					sut = newSUT(t, path)
+				}
+				defer sut.Close()
+
+				node, result := sut.FindDescendants(id1)
+
+				assert.Equal(t, status.Success, result)
+				assert.Equal(
+					t,
+					testInternal.Marshal(
+						t,
+						&lineage.Node{
+							Identity:    id1,
+							Annotations: []*annotation.Instance{m1},
+							Children: []*lineage.Node{
+								{Identity: id2, Annotations: []*annotation.Instance{m2}, Children: []*lineage.Node{}},
+							},
+						},
+					),
+					testInternal.Marshal(t, node),
+				)
+			},
+		)
+	}
+}
+
+// TestStore_Create tests store.Create.
+func TestStore_Create(t *testing.T) {
+	type testCase struct {
+		name                string
+		identity            identity.Contract
+		m                   *annotation.Instance
+		postCondition       func(t *testing.T, sut *instance)
+		expectedAnnotations []*annotation.Instance
+	}
+
+	cases := []testCase{
+		func() testCase {
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			m := newAnnotation(id, nil)
+			return testCase{
+				name:                "create once",
+				identity:            id,
+				m:                   m,
+				postCondition:       func(_ *testing.T, _ *instance) {},
+				expectedAnnotations: []*annotation.Instance{m},
+			}
+		}(),
+		func() testCase {
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			m1 := newAnnotation(id, nil)
+			m2 := newAnnotation(id, nil)
+			return testCase{
+				name:     "create twice",
+				identity: id,
+				m:        m1,
+				postCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Exists, sut.Create(id, m2))
+				},
+				expectedAnnotations: []*annotation.Instance{m1},
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				sut := newSUT(t, path)
+				defer sut.Close()
+
+				result := sut.Create(cases[i].identity, cases[i].m)
+
+				assert.Equal(t, status.Success, result)
+				cases[i].postCondition(t, sut)
+				savedModel, result := sut.FindByIdentity(cases[i].identity)
+				assert.Equal(t, status.Success, result)
+				assert.Equal(
+					t,
+					testInternal.Marshal(t, cases[i].expectedAnnotations),
+					testInternal.Marshal(t, savedModel),
+				)
+			},
+		)
+	}
+}
+
+// TestStore_Append tests store.Append.
+func TestStore_Append(t *testing.T) {
+	type testCase struct {
+		name                string
+		identity            identity.Contract
+		m                   *annotation.Instance
+		preCondition        func(t *testing.T, sut *instance)
+		expectedStatus      status.Value
+		expectedAnnotations []*annotation.Instance
+	}
+
+	cases := []testCase{
+		func() testCase {
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			return testCase{
+				name:                "append to missing identity",
+				identity:            id,
+				m:                   newAnnotation(id, nil),
+				preCondition:        func(_ *testing.T, _ *instance) {},
+				expectedStatus:      status.NotFound,
+				expectedAnnotations: []*annotation.Instance{},
+			}
+		}(),
+		func() testCase {
+			id := identityHash.New(test.FactoryRandomByteSlice())
+			m1 := newAnnotation(id, nil)
+			m2 := newAnnotation(id, nil)
+			return testCase{
+				name:     "append once",
+				identity: id,
+				m:        m2,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id, m1))
+				},
+				expectedStatus:      status.Success,
+				expectedAnnotations: []*annotation.Instance{m1, m2},
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				sut := newSUT(t, path)
+				defer sut.Close()
+				cases[i].preCondition(t, sut)
+
+				result := sut.Append(cases[i].identity, cases[i].m)
+
+				assert.Equal(t, cases[i].expectedStatus, result)
+				savedModel, _ := sut.FindByIdentity(cases[i].identity)
+				assert.Equal(
+					t,
+					testInternal.Marshal(t, cases[i].expectedAnnotations),
+					testInternal.Marshal(t, savedModel),
+				)
+			},
+		)
+	}
+}
+
+// TestStore_Reopen tests that annotations survive closing and reopening the store.
+func TestStore_Reopen(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "annotations and typed metadata restored",
+			test: func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				m1 := newAnnotation(id, nil)
+				m2 := newAnnotation(id, nil)
+				sut := newSUT(t, path)
+				assert.Equal(t, status.Success, sut.Create(id, m1))
+				assert.Equal(t, status.Success, sut.Append(id, m2))
+				assert.NoError(t, sut.Close())
+
+				sut = newSUT(t, path)
+				defer sut.Close()
+				result, s := sut.FindByIdentity(id)
+
+				assert.Equal(t, status.Success, s)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m1, m2}), testInternal.Marshal(t, result))
+				for i := range result {
+					assert.IsType(t, &identityHash.Identity{}, result[i].CurrentIdentity)
+					assert.IsType(t, &metadata.Instance{}, result[i].Metadata)
+				}
+			},
+		},
+		{
+			name: "index rebuilt",
+			test: func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				sut := newSUT(t, path)
+				assert.Equal(t, status.Success, sut.Create(id, newAnnotation(id, nil)))
+				assert.NoError(t, sut.Close())
+
+				sut = newSUT(t, path)
+				defer sut.Close()
+
+				assert.Equal(t, status.Exists, sut.Create(id, newAnnotation(id, nil)))
+				assert.Equal(t, status.Success, sut.Append(id, newAnnotation(id, nil)))
+			},
+		},
+		{
+			name: "cbor encoded annotations and derivations restored",
+			test: func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m1 := newAnnotation(id1, nil)
+				m2 := newAnnotation(id2, id1)
+				sut := newSUTWithEncoding(t, path, cbor.New())
+				assert.Equal(t, status.Success, sut.Create(id1, m1))
+				assert.Equal(t, status.Success, sut.Create(id2, m2))
+				assert.NoError(t, sut.Close())
+
+				sut = newSUTWithEncoding(t, path, cbor.New())
+				defer sut.Close()
+				result, s := sut.FindByIdentity(id2)
+				node, descendantsStatus := sut.FindDescendants(id1)
+
+				assert.Equal(t, status.Success, s)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m2, m1}), testInternal.Marshal(t, result))
+				for i := range result {
+					assert.IsType(t, &metadata.Instance{}, result[i].Metadata)
+				}
+				assert.Equal(t, status.Success, descendantsStatus)
+				require.Len(t, node.Children, 1)
+				assert.Equal(t, id2.Printable(), node.Children[0].Identity.Printable())
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestStore_Recovery tests that a torn trailing record is discarded when the store is reopened.
+func TestStore_Recovery(t *testing.T) {
+	type testCase struct {
+		name       string
+		damage     func(t *testing.T, path string)
+		lastIsTorn bool
+	}
+
+	cases := []testCase{
+		{
+			name: "truncated record",
+			damage: func(t *testing.T, path string) {
+				info, err := os.Stat(path)
+				require.NoError(t, err)
+				require.NoError(t, os.Truncate(path, info.Size()-1))
+			},
+			lastIsTorn: true,
+		},
+		{
+			name: "truncated header",
+			damage: func(t *testing.T, path string) {
+				f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+				require.NoError(t, err)
+				_, err = f.Write([]byte{0x00, 0x00})
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+			},
+		},
+		{
+			name: "corrupt checksum",
+			damage: func(t *testing.T, path string) {
+				f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+				require.NoError(t, err)
+				_, err = f.Write([]byte{0x00, 0x00, 0x00, 0x02, 0xde, 0xad, 0xbe, 0xef, '{', '}'})
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				m1 := newAnnotation(id, nil)
+				m2 := newAnnotation(id, nil)
+				m3 := newAnnotation(id, nil)
+				sut := newSUT(t, path)
+				assert.Equal(t, status.Success, sut.Create(id, m1))
+				assert.Equal(t, status.Success, sut.Append(id, m2))
+				assert.NoError(t, sut.Close())
+				cases[i].damage(t, path)
+
+				sut = newSUT(t, path)
+				defer sut.Close()
+				assert.Equal(t, status.Success, sut.Append(id, m3))
+				result, s := sut.FindByIdentity(id)
+
+				assert.Equal(t, status.Success, s)
+				expected := []*annotation.Instance{m1, m2, m3}
+				if cases[i].lastIsTorn {
+					expected = []*annotation.Instance{m1, m3}
+				}
+				assert.Equal(t, testInternal.Marshal(t, expected), testInternal.Marshal(t, result))
+			},
+		)
+	}
+}
+
+// firstRecord returns the offset of the first annotation record in the log at path (i.e. the size of its header
+// record).
+func firstRecord(t *testing.T, path string) int64 {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.True(t, len(data) >= headerSize)
+	return headerSize + int64(binary.BigEndian.Uint32(data[:4]))
+}
+
+// appendFrame appends a record frame with a valid checksum containing payload to the log at path.
+func appendFrame(t *testing.T, path string, payload []byte) {
+	frame := make([]byte, headerSize, headerSize+len(payload))
+	binary.BigEndian.PutUint32(frame[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:], crc32.Checksum(payload, crcTable))
+	frame = append(frame, payload...)
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.Write(frame)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+// TestStore_Corruption tests that damage other than a torn tail is reported when the store is reopened and that the
+// log is left untouched.
+func TestStore_Corruption(t *testing.T) {
+	type testCase struct {
+		name   string
+		damage func(t *testing.T, path string)
+	}
+
+	cases := []testCase{
+		{
+			name: "corrupt checksum followed by valid records",
+			damage: func(t *testing.T, path string) {
+				f, err := os.OpenFile(path, os.O_WRONLY, 0600)
+				require.NoError(t, err)
+				_, err = f.WriteAt([]byte{0xde, 0xad, 0xbe, 0xef}, firstRecord(t, path)+4)
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+			},
+		},
+		{
+			name: "corrupt length followed by valid records",
+			damage: func(t *testing.T, path string) {
+				f, err := os.OpenFile(path, os.O_WRONLY, 0600)
+				require.NoError(t, err)
+				_, err = f.WriteAt([]byte{0x7f, 0xff, 0xff, 0xff}, firstRecord(t, path))
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+			},
+		},
+		{
+			name: "valid checksum with undecodable record",
+			damage: func(t *testing.T, path string) {
+				appendFrame(t, path, []byte("not a record"))
+			},
+		},
+		{
+			name: "valid checksum with undecodable annotation",
+			damage: func(t *testing.T, path string) {
+				appendFrame(t, path, []byte(`{"operation":"append","identity":"id","annotation":"not an annotation"}`))
+			},
+		},
+		{
+			name: "valid checksum without identity",
+			damage: func(t *testing.T, path string) {
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				appendFrame(
+					t,
+					path,
+					[]byte(`{"operation":"append","identity":"`+id.Printable()+`","annotation":`+
+						testInternal.Marshal(t, newAnnotation(id, nil))+`}`),
+				)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				sut := newSUT(t, path)
+				assert.Equal(t, status.Success, sut.Create(id, newAnnotation(id, nil)))
+				assert.Equal(t, status.Success, sut.Append(id, newAnnotation(id, nil)))
+				assert.NoError(t, sut.Close())
+				cases[i].damage(t, path)
+				before, err := os.Stat(path)
+				require.NoError(t, err)
+
+				_, err = New(path, identityFactory.New(), metadataFactory.New(nil))
+
+				assert.Error(t, err)
+				after, err := os.Stat(path)
+				require.NoError(t, err)
+				assert.Equal(t, before.Size(), after.Size())
+			},
+		)
+	}
+}
+
+// TestStore_Header tests that a log records its encoding and is not opened with a different one.
+func TestStore_Header(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "encoding recorded in new log",
+			test: func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				sut := newSUTWithEncoding(t, path, cbor.New())
+				assert.NoError(t, sut.Close())
+
+				data, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				assert.JSONEq(
+					t,
+					`{"format":"alvarium-annotation-log","version":1,"encoding":"cbor"}`,
+					string(data[headerSize:firstRecord(t, path)]),
+				)
+			},
+		},
+		{
+			name: "json log not opened with cbor encoding",
+			test: func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				sut := newSUT(t, path)
+				assert.Equal(t, status.Success, sut.Create(id, newAnnotation(id, nil)))
+				assert.NoError(t, sut.Close())
+				before, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+
+				_, err = NewWithEncoding(path, identityFactory.New(), metadataFactory.New(nil), cbor.New())
+
+				assert.Error(t, err)
+				after, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t, before, after)
+			},
+		},
+		{
+			name: "cbor log not opened with json encoding",
+			test: func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				sut := newSUTWithEncoding(t, path, cbor.New())
+				assert.Equal(t, status.Success, sut.Create(id, newAnnotation(id, nil)))
+				assert.NoError(t, sut.Close())
+				before, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+
+				_, err = New(path, identityFactory.New(), metadataFactory.New(nil))
+
+				assert.Error(t, err)
+				after, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t, before, after)
+			},
+		},
+		{
+			name: "log without header not opened",
+			test: func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				id := identityHash.New(test.FactoryRandomByteSlice())
+				sut := newSUT(t, path)
+				assert.Equal(t, status.Success, sut.Create(id, newAnnotation(id, nil)))
+				assert.NoError(t, sut.Close())
+				data, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				headerless := data[firstRecord(t, path):]
+				require.NoError(t, ioutil.WriteFile(path, headerless, 0600))
+
+				_, err = New(path, identityFactory.New(), metadataFactory.New(nil))
+
+				assert.Error(t, err)
+				after, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t, headerless, after)
+			},
+		},
+		{
+			name: "corrupt header not opened",
+			test: func(t *testing.T) {
+				path, cleanUp := newPath(t)
+				defer cleanUp()
+				sut := newSUT(t, path)
+				assert.NoError(t, sut.Close())
+				f, err := os.OpenFile(path, os.O_WRONLY, 0600)
+				require.NoError(t, err)
+				_, err = f.WriteAt([]byte{'x'}, headerSize)
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+				before, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+
+				_, err = New(path, identityFactory.New(), metadataFactory.New(nil))
+
+				assert.Error(t, err)
+				after, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				assert.Equal(t, before, after)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
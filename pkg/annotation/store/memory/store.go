@@ -15,10 +15,12 @@
 package memory
 
 import (
-	"bytes"
 	"sync"
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/watch"
 	"github.com/project-alvarium/go-sdk/pkg/identity"
 	"github.com/project-alvarium/go-sdk/pkg/status"
 )
@@ -26,39 +28,110 @@ import (
 // data defines the map used to provide generic storage.
 type data map[string][]*annotation.Instance
 
+// identities defines the map used to retain the identity each annotation list is stored against.
+type identities map[string]identity.Contract
+
+// derivations defines the map used to index identities by the previous identity they were derived from.
+type derivations map[string][]identity.Contract
+
+// positions defines the map used to index the write log by annotation unique.
+type positions map[string]int
+
 // instance is a receiver that encapsulates required dependencies.
 type instance struct {
-	m    sync.Mutex
-	data data
+	m           sync.Mutex
+	data        data
+	identities  identities
+	derivations derivations
+	log         []watch.Entry
+	positions   positions
+	feed        *watch.Feed
 }
 
 // New is a factory function that returns instance.
 func New() *instance {
 	return &instance{
-		m:    sync.Mutex{},
-		data: make(data),
+		m:           sync.Mutex{},
+		data:        make(data),
+		identities:  make(identities),
+		derivations: make(derivations),
+		log:         make([]watch.Entry, 0),
+		positions:   make(positions),
+		feed:        watch.NewFeed(),
 	}
 }
 
-// recursiveFind recursively traverses a chain of custody and returns its annotations.
-func (i *instance) recursiveFind(id identity.Contract, annotations *[]*annotation.Instance) status.Value {
+// record appends an annotation stored against identity to the write log and notifies subscriptions.
+func (i *instance) record(id identity.Contract, m *annotation.Instance) {
+	i.positions[m.Unique] = len(i.log)
+	i.log = append(i.log, watch.Entry{Identity: id, Annotation: m})
+	i.feed.Publish(id, m)
+}
+
+// fetch returns the annotations stored directly against identity.
+func (i *instance) fetch(id identity.Contract) ([]*annotation.Instance, status.Value) {
 	m, exists := i.data[id.Printable()]
 	if !exists {
-		return status.NotFound
+		return nil, status.NotFound
 	}
+	return m, status.Success
+}
 
-	var p identity.Contract = nil
-	for i := range m {
-		if p == nil && m[i].PreviousIdentity != nil && !bytes.Equal(m[i].PreviousIdentity.Binary(), id.Binary()) {
-			p = m[i].PreviousIdentity
+// children returns the identities derived directly from identity.
+func (i *instance) children(id identity.Contract) ([]identity.Contract, status.Value) {
+	return i.derivations[id.Printable()], status.Success
+}
+
+// derive records that identity was derived from each of the annotation's predecessors.
+func (i *instance) derive(id identity.Contract, m *annotation.Instance) {
+	idAsString := id.Printable()
+	predecessors := m.Predecessors()
+	for p := range predecessors {
+		previous := predecessors[p].Printable()
+		if previous == idAsString || i.derived(previous, idAsString) {
+			continue
 		}
-		*annotations = append(*annotations, m[i])
+		i.derivations[previous] = append(i.derivations[previous], id)
 	}
-	if p != nil {
-		i.recursiveFind(p, annotations)
+}
+
+// derived returns whether the identity with printable value id is recorded as derived from previous.
+func (i *instance) derived(previous, id string) bool {
+	for d := range i.derivations[previous] {
+		if i.derivations[previous][d].Printable() == id {
+			return true
+		}
 	}
+	return false
+}
 
-	return status.Success
+// underive removes the derivations of the identity with printable value id that are not recorded by any of the
+// annotations stored against it.
+func (i *instance) underive(id string, m []*annotation.Instance) {
+	recorded := make(map[string]bool)
+	for a := range m {
+		predecessors := m[a].Predecessors()
+		for p := range predecessors {
+			recorded[predecessors[p].Printable()] = true
+		}
+	}
+
+	for previous, derived := range i.derivations {
+		if recorded[previous] {
+			continue
+		}
+		retained := make([]identity.Contract, 0, len(derived))
+		for d := range derived {
+			if derived[d].Printable() != id {
+				retained = append(retained, derived[d])
+			}
+		}
+		if len(retained) == 0 {
+			delete(i.derivations, previous)
+			continue
+		}
+		i.derivations[previous] = retained
+	}
 }
 
 // FindByIdentity returns annotations and status corresponding to identity.
@@ -66,9 +139,41 @@ func (i *instance) FindByIdentity(id identity.Contract) ([]*annotation.Instance,
 	i.m.Lock()
 	defer i.m.Unlock()
 
-	annotations := make([]*annotation.Instance, 0)
-	result := i.recursiveFind(id, &annotations)
-	return annotations, result
+	return lineage.Find(id, i.fetch)
+}
+
+// FindDescendants returns the tree of identities derived from identity (with their annotations) and status.
+func (i *instance) FindDescendants(id identity.Contract) (*lineage.Node, status.Value) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	return lineage.Descendants(id, i.fetch, i.children)
+}
+
+// Query returns the page of annotations satisfying criteria and status.
+func (i *instance) Query(criteria query.Criteria) (*query.Page, status.Value) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	var candidates []*annotation.Instance
+	switch {
+	case criteria.Identity == nil:
+		candidates = make([]*annotation.Instance, 0)
+		for _, m := range i.data {
+			candidates = append(candidates, m...)
+		}
+	case criteria.Lineage:
+		var result status.Value
+		if candidates, result = lineage.Find(criteria.Identity, i.fetch); result != status.Success {
+			return nil, result
+		}
+	default:
+		var result status.Value
+		if candidates, result = i.fetch(criteria.Identity); result != status.Success {
+			return nil, result
+		}
+	}
+	return query.Apply(criteria, candidates)
 }
 
 // Create stores annotations corresponding to a new identity and returns status.
@@ -82,6 +187,9 @@ func (i *instance) Create(id identity.Contract, m *annotation.Instance) status.V
 		return status.Exists
 	}
 	i.data[idAsString] = []*annotation.Instance{m}
+	i.identities[idAsString] = id
+	i.derive(id, m)
+	i.record(id, m)
 	return status.Success
 }
 
@@ -95,5 +203,101 @@ func (i *instance) Append(id identity.Contract, m *annotation.Instance) status.V
 		return status.NotFound
 	}
 	i.data[idAsString] = append(i.data[idAsString], m)
+	i.derive(id, m)
+	i.record(id, m)
+	return status.Success
+}
+
+// Identities returns the identities annotations are stored against and status.
+func (i *instance) Identities() ([]identity.Contract, status.Value) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	result := make([]identity.Contract, 0, len(i.identities))
+	for _, id := range i.identities {
+		result = append(result, id)
+	}
+	return result, status.Success
+}
+
+// Replace replaces the annotations stored directly against identity and returns status; it returns status.Conflict
+// unless the unique values of the annotations stored directly against identity are exactly expected.  Derivations
+// recorded only by the removed annotations are removed from the descendant index.
+func (i *instance) Replace(id identity.Contract, expected []string, m []*annotation.Instance) status.Value {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	idAsString := id.Printable()
+	stored, exists := i.data[idAsString]
+	if !exists {
+		return status.NotFound
+	}
+	if len(m) == 0 {
+		return status.Unknown
+	}
+	if !unchanged(stored, expected) {
+		return status.Conflict
+	}
+	i.data[idAsString] = m
+	i.underive(idAsString, m)
+	for a := range m {
+		i.derive(id, m[a])
+	}
+
+	retained := make(map[string]bool, len(m))
+	for a := range m {
+		retained[m[a].Unique] = true
+	}
+	previous := i.log
+	i.log = make([]watch.Entry, 0, len(previous))
+	i.positions = make(positions, len(previous))
+	for e := range previous {
+		if previous[e].Identity.Printable() == idAsString && !retained[previous[e].Annotation.Unique] {
+			continue
+		}
+		i.positions[previous[e].Annotation.Unique] = len(i.log)
+		i.log = append(i.log, previous[e])
+	}
+	for a := range m {
+		if _, exists := i.positions[m[a].Unique]; !exists {
+			i.record(id, m[a])
+		}
+	}
 	return status.Success
 }
+
+// unchanged returns whether the unique values of stored are exactly expected.
+func unchanged(stored []*annotation.Instance, expected []string) bool {
+	if len(stored) != len(expected) {
+		return false
+	}
+	remaining := make(map[string]bool, len(expected))
+	for e := range expected {
+		remaining[expected[e]] = true
+	}
+	for s := range stored {
+		if !remaining[stored[s].Unique] {
+			return false
+		}
+		delete(remaining, stored[s].Unique)
+	}
+	return true
+}
+
+// Watch returns a subscription delivering annotations satisfying criteria as they are stored and status.
+func (i *instance) Watch(criteria watch.Criteria) (*watch.Subscription, status.Value) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	start := len(i.log)
+	if criteria.Cursor != "" {
+		p, exists := i.positions[criteria.Cursor]
+		if !exists {
+			return nil, status.NotFound
+		}
+		start = p + 1
+	}
+	backlog := make([]watch.Entry, len(i.log)-start)
+	copy(backlog, i.log[start:])
+	return i.feed.Subscribe(criteria, backlog), status.Success
+}
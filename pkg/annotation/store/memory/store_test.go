@@ -16,10 +16,14 @@ package memory
 
 import (
 	"testing"
+	"time"
 
 	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
 	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/watch"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/identity"
 	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
@@ -27,6 +31,7 @@ import (
 	"github.com/project-alvarium/go-sdk/pkg/test"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // newSUT returns a new system under test.
@@ -76,6 +81,24 @@ func TestStore_FindByIdentity(t *testing.T) {
 				expectedStatus:      status.Success,
 			}
 		}(),
+		func() testCase {
+			id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+			id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+			m1 := annotation.New(test.FactoryRandomString(), id1, nil, metadataStub.NewNullObject())
+			m2 := annotation.New(test.FactoryRandomString(), id2, id1, metadataStub.NewNullObject())
+			m3 := annotation.New(test.FactoryRandomString(), id1, id2, metadataStub.NewNullObject())
+			return testCase{
+				name:     "reverted identity",
+				identity: id1,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id1, m1))
+					assert.Equal(t, status.Success, sut.Create(id2, m2))
+					assert.Equal(t, status.Success, sut.Append(id1, m3))
+				},
+				expectedAnnotations: []*annotation.Instance{m1, m3, m2},
+				expectedStatus:      status.Success,
+			}
+		}(),
 	}
 
 	for i := range cases {
@@ -94,6 +117,421 @@ func TestStore_FindByIdentity(t *testing.T) {
 	}
 }
 
+// TestStore_FindDescendants tests store.FindDescendants.
+func TestStore_FindDescendants(t *testing.T) {
+	type testCase struct {
+		name           string
+		identity       identity.Contract
+		preCondition   func(t *testing.T, sut *instance)
+		expectedNode   *lineage.Node
+		expectedStatus status.Value
+	}
+
+	cases := []testCase{
+		{
+			name:           "does not exist",
+			identity:       identityHash.New(test.FactoryRandomByteSlice()),
+			preCondition:   func(_ *testing.T, _ *instance) {},
+			expectedNode:   nil,
+			expectedStatus: status.NotFound,
+		},
+		func() testCase {
+			id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+			id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+			id3 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+			m1 := annotation.New(test.FactoryRandomString(), id1, nil, metadataStub.NewNullObject())
+			m2 := annotation.New(test.FactoryRandomString(), id2, id1, metadataStub.NewNullObject())
+			m3 := annotation.New(test.FactoryRandomString(), id2, id1, metadataStub.NewNullObject())
+			m4 := annotation.New(test.FactoryRandomString(), id3, id2, metadataStub.NewNullObject())
+			return testCase{
+				name:     "derived tree",
+				identity: id1,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id1, m1))
+					assert.Equal(t, status.Success, sut.Create(id2, m2))
+					assert.Equal(t, status.Success, sut.Append(id2, m3))
+					assert.Equal(t, status.Success, sut.Create(id3, m4))
+				},
+				expectedNode: &lineage.Node{
+					Identity:    id1,
+					Annotations: []*annotation.Instance{m1},
+					Children: []*lineage.Node{
+						{
+							Identity:    id2,
+							Annotations: []*annotation.Instance{m2, m3},
+							Children: []*lineage.Node{
+								{
+									Identity:    id3,
+									Annotations: []*annotation.Instance{m4},
+									Children:    []*lineage.Node{},
+								},
+							},
+						},
+					},
+				},
+				expectedStatus: status.Success,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := newSUT()
+				cases[i].preCondition(t, sut)
+
+				node, result := sut.FindDescendants(cases[i].identity)
+
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expectedNode), testInternal.Marshal(t, node))
+				assert.Equal(t, cases[i].expectedStatus, result)
+			},
+		)
+	}
+}
+
+// TestStore_Query tests store.Query.
+func TestStore_Query(t *testing.T) {
+	id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	id3 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	m1 := annotation.New(test.FactoryRandomFixedLengthAlphanumericString(26), id1, nil, metadataStub.New("a", nil))
+	m2 := annotation.New(test.FactoryRandomFixedLengthAlphanumericString(26), id2, id1, metadataStub.New("b", nil))
+	m3 := annotation.New(test.FactoryRandomFixedLengthAlphanumericString(26), id2, nil, metadataStub.New("a", nil))
+	m4 := annotation.New(test.FactoryRandomFixedLengthAlphanumericString(26), id3, nil, metadataStub.New("a", nil))
+	for n, m := range []*annotation.Instance{m1, m2, m3, m4} {
+		m.Created = time.Date(2020, 1, 1, 0, n, 0, 0, time.UTC).Format(time.RFC3339Nano)
+	}
+	sut := newSUT()
+	assert.Equal(t, status.Success, sut.Create(id1, m1))
+	assert.Equal(t, status.Success, sut.Create(id2, m2))
+	assert.Equal(t, status.Success, sut.Append(id2, m3))
+	assert.Equal(t, status.Success, sut.Create(id3, m4))
+
+	type testCase struct {
+		name                string
+		criteria            query.Criteria
+		expectedAnnotations []*annotation.Instance
+		expectedStatus      status.Value
+	}
+
+	cases := []testCase{
+		{
+			name:                "all identities",
+			criteria:            query.Criteria{},
+			expectedAnnotations: []*annotation.Instance{m1, m2, m3, m4},
+			expectedStatus:      status.Success,
+		},
+		{
+			name:                "identity",
+			criteria:            query.Criteria{Identity: id2, Order: query.Descending},
+			expectedAnnotations: []*annotation.Instance{m3, m2},
+			expectedStatus:      status.Success,
+		},
+		{
+			name:                "identity lineage",
+			criteria:            query.Criteria{Identity: id2, Lineage: true},
+			expectedAnnotations: []*annotation.Instance{m1, m2, m3},
+			expectedStatus:      status.Success,
+		},
+		{
+			name:                "kind",
+			criteria:            query.Criteria{Identity: id2, Lineage: true, Kinds: []string{"a"}},
+			expectedAnnotations: []*annotation.Instance{m1, m3},
+			expectedStatus:      status.Success,
+		},
+		{
+			name:                "identity does not exist",
+			criteria:            query.Criteria{Identity: identityHash.New(test.FactoryRandomByteSlice())},
+			expectedAnnotations: nil,
+			expectedStatus:      status.NotFound,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				page, result := sut.Query(cases[i].criteria)
+
+				assert.Equal(t, cases[i].expectedStatus, result)
+				if cases[i].expectedAnnotations == nil {
+					assert.Nil(t, page)
+					return
+				}
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expectedAnnotations), testInternal.Marshal(t, page.Annotations))
+			},
+		)
+	}
+}
+
+// TestStore_Identities tests store.Identities.
+func TestStore_Identities(t *testing.T) {
+	id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+	m1 := annotation.New(test.FactoryRandomString(), id1, nil, metadataStub.NewNullObject())
+	m2 := annotation.New(test.FactoryRandomString(), id1, id1, metadataStub.NewNullObject())
+	m3 := annotation.New(test.FactoryRandomString(), id2, id1, metadataStub.NewNullObject())
+	sut := newSUT()
+	assert.Equal(t, status.Success, sut.Create(id1, m1))
+	assert.Equal(t, status.Success, sut.Append(id1, m2))
+	assert.Equal(t, status.Success, sut.Create(id2, m3))
+
+	identities, result := sut.Identities()
+
+	assert.Equal(t, status.Success, result)
+	printable := make([]string, len(identities))
+	for i := range identities {
+		printable[i] = identities[i].Printable()
+	}
+	assert.ElementsMatch(t, []string{id1.Printable(), id2.Printable()}, printable)
+}
+
+// TestStore_Replace tests store.Replace.
+func TestStore_Replace(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "identity exists",
+			test: func(t *testing.T) {
+				id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m1 := annotation.New(test.FactoryRandomString(), id1, nil, metadataStub.NewNullObject())
+				m2 := annotation.New(test.FactoryRandomString(), id2, nil, metadataStub.NewNullObject())
+				m3 := annotation.New(test.FactoryRandomString(), id2, id2, metadataStub.NewNullObject())
+				replacement := annotation.New(test.FactoryRandomString(), id2, id1, metadataStub.NewNullObject())
+				sut := newSUT()
+				assert.Equal(t, status.Success, sut.Create(id1, m1))
+				assert.Equal(t, status.Success, sut.Create(id2, m2))
+				assert.Equal(t, status.Success, sut.Append(id2, m3))
+
+				result := sut.Replace(id2, []string{m3.Unique, m2.Unique}, []*annotation.Instance{replacement, m3})
+
+				assert.Equal(t, status.Success, result)
+				m, result := sut.FindByIdentity(id2)
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{replacement, m3, m1}), testInternal.Marshal(t, m))
+				node, _ := sut.FindDescendants(id1)
+				assert.Equal(t, 1, len(node.Children))
+			},
+		},
+		{
+			name: "derivations of removed annotations pruned",
+			test: func(t *testing.T) {
+				id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id3 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m1 := annotation.New(test.FactoryRandomString(), id1, nil, metadataStub.NewNullObject())
+				m2 := annotation.New(test.FactoryRandomString(), id2, nil, metadataStub.NewNullObject())
+				m3 := annotation.New(test.FactoryRandomString(), id3, id1, metadataStub.NewNullObject())
+				m4 := annotation.New(test.FactoryRandomString(), id3, id2, metadataStub.NewNullObject())
+				sut := newSUT()
+				require.Equal(t, status.Success, sut.Create(id1, m1))
+				require.Equal(t, status.Success, sut.Create(id2, m2))
+				require.Equal(t, status.Success, sut.Create(id3, m3))
+				require.Equal(t, status.Success, sut.Append(id3, m4))
+
+				result := sut.Replace(id3, []string{m3.Unique, m4.Unique}, []*annotation.Instance{m4})
+
+				assert.Equal(t, status.Success, result)
+				node, _ := sut.FindDescendants(id1)
+				assert.Empty(t, node.Children)
+				node, _ = sut.FindDescendants(id2)
+				require.Equal(t, 1, len(node.Children))
+				assert.Equal(t, id3.Printable(), node.Children[0].Identity.Printable())
+			},
+		},
+		{
+			name: "identity does not exist",
+			test: func(t *testing.T) {
+				id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				sut := newSUT()
+
+				result := sut.Replace(id, nil, []*annotation.Instance{m})
+
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+		{
+			name: "no annotations",
+			test: func(t *testing.T) {
+				id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				sut := newSUT()
+				assert.Equal(t, status.Success, sut.Create(id, m))
+
+				result := sut.Replace(id, []string{m.Unique}, nil)
+
+				assert.Equal(t, status.Unknown, result)
+				stored, _ := sut.FindByIdentity(id)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m}), testInternal.Marshal(t, stored))
+			},
+		},
+		{
+			name: "annotations changed",
+			test: func(t *testing.T) {
+				id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m1 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				m2 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				summary := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				sut := newSUT()
+				assert.Equal(t, status.Success, sut.Create(id, m1))
+				assert.Equal(t, status.Success, sut.Append(id, m2))
+
+				result := sut.Replace(id, []string{m1.Unique}, []*annotation.Instance{summary})
+
+				assert.Equal(t, status.Conflict, result)
+				stored, _ := sut.FindByIdentity(id)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m1, m2}), testInternal.Marshal(t, stored))
+				subscription, result := sut.Watch(watch.Criteria{Cursor: m1.Unique})
+				require.Equal(t, status.Success, result)
+				defer subscription.Close()
+				assert.Equal(t, []string{m2.Unique}, receive(t, subscription, 1))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// receive returns the unique values of the next count annotations delivered to subscription.
+func receive(t *testing.T, subscription *watch.Subscription, count int) []string {
+	result := make([]string, 0, count)
+	for len(result) < count {
+		select {
+		case m, ok := <-subscription.Annotations():
+			require.True(t, ok)
+			result = append(result, m.Unique)
+		case <-time.After(time.Second):
+			require.FailNow(t, "timed out waiting for annotation")
+		}
+	}
+	return result
+}
+
+// TestStore_Watch tests store.Watch.
+func TestStore_Watch(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "created and appended annotations",
+			test: func(t *testing.T) {
+				id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m1 := annotation.New(test.FactoryRandomString(), id1, nil, metadataStub.NewNullObject())
+				m2 := annotation.New(test.FactoryRandomString(), id1, nil, metadataStub.NewNullObject())
+				m3 := annotation.New(test.FactoryRandomString(), id2, id1, metadataStub.NewNullObject())
+				sut := newSUT()
+				assert.Equal(t, status.Success, sut.Create(id1, m1))
+				subscription, result := sut.Watch(watch.Criteria{})
+				require.Equal(t, status.Success, result)
+				defer subscription.Close()
+
+				assert.Equal(t, status.Success, sut.Append(id1, m2))
+				assert.Equal(t, status.Exists, sut.Create(id1, m1))
+				assert.Equal(t, status.Success, sut.Create(id2, m3))
+
+				assert.Equal(t, []string{m2.Unique, m3.Unique}, receive(t, subscription, 2))
+			},
+		},
+		{
+			name: "filtered by identity and kind",
+			test: func(t *testing.T) {
+				id1 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				id2 := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				kind := test.FactoryRandomString()
+				m1 := annotation.New(test.FactoryRandomString(), id1, nil, metadataStub.NewNullObject())
+				m2 := annotation.New(test.FactoryRandomString(), id2, nil, metadataStub.New(kind, nil))
+				m3 := annotation.New(test.FactoryRandomString(), id1, nil, metadataStub.New(kind, nil))
+				sut := newSUT()
+				subscription, result := sut.Watch(watch.Criteria{Identity: id1, Kinds: []string{kind}})
+				require.Equal(t, status.Success, result)
+				defer subscription.Close()
+
+				assert.Equal(t, status.Success, sut.Create(id1, m1))
+				assert.Equal(t, status.Success, sut.Create(id2, m2))
+				assert.Equal(t, status.Success, sut.Append(id1, m3))
+
+				assert.Equal(t, []string{m3.Unique}, receive(t, subscription, 1))
+			},
+		},
+		{
+			name: "resumed from cursor",
+			test: func(t *testing.T) {
+				id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m1 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				m2 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				m3 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				m4 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				sut := newSUT()
+				assert.Equal(t, status.Success, sut.Create(id, m1))
+				assert.Equal(t, status.Success, sut.Append(id, m2))
+				assert.Equal(t, status.Success, sut.Append(id, m3))
+
+				subscription, result := sut.Watch(watch.Criteria{Cursor: m1.Unique})
+				require.Equal(t, status.Success, result)
+				defer subscription.Close()
+				assert.Equal(t, status.Success, sut.Append(id, m4))
+
+				assert.Equal(t, []string{m2.Unique, m3.Unique, m4.Unique}, receive(t, subscription, 3))
+			},
+		},
+		{
+			name: "unknown cursor",
+			test: func(t *testing.T) {
+				sut := newSUT()
+
+				subscription, result := sut.Watch(watch.Criteria{Cursor: test.FactoryRandomString()})
+
+				assert.Nil(t, subscription)
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+		{
+			name: "replaced annotations",
+			test: func(t *testing.T) {
+				id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m1 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				m2 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				summary := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				sut := newSUT()
+				assert.Equal(t, status.Success, sut.Create(id, m1))
+				assert.Equal(t, status.Success, sut.Append(id, m2))
+				subscription, result := sut.Watch(watch.Criteria{})
+				require.Equal(t, status.Success, result)
+				defer subscription.Close()
+
+				result = sut.Replace(id, []string{m1.Unique, m2.Unique}, []*annotation.Instance{summary, m2})
+
+				assert.Equal(t, status.Success, result)
+
+				assert.Equal(t, []string{summary.Unique}, receive(t, subscription, 1))
+				_, result = sut.Watch(watch.Criteria{Cursor: m1.Unique})
+				assert.Equal(t, status.NotFound, result)
+				resumed, result := sut.Watch(watch.Criteria{Cursor: m2.Unique})
+				require.Equal(t, status.Success, result)
+				defer resumed.Close()
+				assert.Equal(t, []string{summary.Unique}, receive(t, resumed, 1))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
 // TestStore_Create tests store.Create.
 func TestStore_Create(t *testing.T) {
 	type testCase struct {
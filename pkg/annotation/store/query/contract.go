@@ -0,0 +1,81 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package query
+
+import (
+	"time"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// Order defines the order in which query results are returned.
+type Order int
+
+const (
+	// Ascending returns annotations oldest (by created value) first.
+	Ascending Order = iota
+
+	// Descending returns annotations newest (by created value) first.
+	Descending
+)
+
+// Criteria defines the conditions an annotation must satisfy to be included in a query result.
+type Criteria struct {
+	// Identity restricts results to annotations stored against identity (nil matches all identities).
+	Identity identity.Contract
+
+	// Lineage includes annotations of Identity's predecessors (as FindByIdentity does); ignored if Identity is nil.
+	Lineage bool
+
+	// Kinds restricts results to annotations with one of the given metadata kinds (empty matches all kinds).
+	Kinds []string
+
+	// From restricts results to annotations created at or after From (nil is unbounded).
+	From *time.Time
+
+	// To restricts results to annotations created before To (nil is unbounded).
+	To *time.Time
+
+	// Provenance restricts results to annotations whose metadata records one of the given provenance values
+	// (empty matches all annotations); see provenance.Source.
+	Provenance []provenance.Contract
+
+	// Order defines the order of results.
+	Order Order
+
+	// Limit is the maximum number of annotations returned in a page (zero is unlimited).
+	Limit int
+
+	// Cursor resumes a query after the last annotation of a previous page (empty starts from the beginning).
+	Cursor string
+}
+
+// Page is a single page of query results.
+type Page struct {
+	// Annotations are the annotations in the page.
+	Annotations []*annotation.Instance
+
+	// Next is the cursor for the next page (empty if this is the last page).
+	Next string
+}
+
+// Contract defines the optional store query abstraction.
+type Contract interface {
+	// Query returns the page of annotations satisfying criteria and status.
+	Query(criteria Criteria) (*Page, status.Value)
+}
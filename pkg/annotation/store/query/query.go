@@ -0,0 +1,230 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// query implements an optional store query capability and helpers shared by its implementations.
+package query
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/datetime"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
+	"github.com/project-alvarium/go-sdk/pkg/canonical"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// created returns the annotation's created value (or the zero time if it cannot be parsed).
+func created(m *annotation.Instance) time.Time {
+	if t := datetime.TimeFromCreated(m.Created); t != nil {
+		return *t
+	}
+	return time.Time{}
+}
+
+// keySize is the size of a sort key's created prefix (offset seconds followed by nanoseconds).
+const keySize = 12
+
+// key returns the sort key of an annotation: its created value followed by its unique value.
+func key(m *annotation.Instance) []byte {
+	t := created(m)
+	b := make([]byte, keySize, keySize+len(m.Unique))
+	binary.BigEndian.PutUint64(b[:8], uint64(t.Unix())^(1<<63))
+	binary.BigEndian.PutUint32(b[8:], uint32(t.Nanosecond()))
+	return append(b, m.Unique...)
+}
+
+// encodeCursor returns the opaque cursor for the given sort key.
+func encodeCursor(k []byte) string {
+	return base64.RawURLEncoding.EncodeToString(k)
+}
+
+// decodeCursor returns the sort key for the given opaque cursor.
+func decodeCursor(cursor string) ([]byte, bool) {
+	k, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(k) < keySize {
+		return nil, false
+	}
+	return k, true
+}
+
+// Match returns whether an annotation satisfies criteria's kind, created window, and provenance conditions.
+//
+// Provenance values are compared by their canonical JSON encodings (see canonical.Marshal), so a value matches the
+// same value decoded from JSON (e.g. a struct matches the map a store decodes it as).
+func Match(criteria Criteria, m *annotation.Instance) bool {
+	if len(criteria.Kinds) > 0 {
+		found := false
+		for i := range criteria.Kinds {
+			if criteria.Kinds[i] == m.MetadataKind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if criteria.From != nil || criteria.To != nil {
+		t := created(m)
+		if criteria.From != nil && t.Before(*criteria.From) {
+			return false
+		}
+		if criteria.To != nil && !t.Before(*criteria.To) {
+			return false
+		}
+	}
+
+	if len(criteria.Provenance) > 0 {
+		source, ok := m.Metadata.(provenance.Source)
+		if !ok {
+			return false
+		}
+		p, err := canonical.Marshal(source.GetProvenance())
+		if err != nil {
+			return false
+		}
+		for i := range criteria.Provenance {
+			if c, err := canonical.Marshal(criteria.Provenance[i]); err == nil && bytes.Equal(c, p) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Apply filters, orders, and paginates candidate annotations according to criteria and returns a page and status.
+func Apply(criteria Criteria, candidates []*annotation.Instance) (*Page, status.Value) {
+	var after []byte
+	if criteria.Cursor != "" {
+		var ok bool
+		if after, ok = decodeCursor(criteria.Cursor); !ok {
+			return nil, status.Unknown
+		}
+	}
+
+	type entry struct {
+		key []byte
+		m   *annotation.Instance
+	}
+
+	entries := make([]entry, 0, len(candidates))
+	for i := range candidates {
+		if Match(criteria, candidates[i]) {
+			entries = append(entries, entry{key: key(candidates[i]), m: candidates[i]})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if criteria.Order == Descending {
+			return bytes.Compare(entries[i].key, entries[j].key) > 0
+		}
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	page := &Page{Annotations: make([]*annotation.Instance, 0)}
+	for i := range entries {
+		if after != nil {
+			c := bytes.Compare(entries[i].key, after)
+			if (criteria.Order == Descending && c >= 0) || (criteria.Order != Descending && c <= 0) {
+				continue
+			}
+		}
+		if criteria.Limit > 0 && len(page.Annotations) == criteria.Limit {
+			page.Next = encodeCursor(entries[i-1].key)
+			break
+		}
+		page.Annotations = append(page.Annotations, entries[i].m)
+	}
+	return page, status.Success
+}
+
+// All follows a query's cursors and returns every annotation satisfying criteria and status.
+func All(q Contract, criteria Criteria) ([]*annotation.Instance, status.Value) {
+	annotations := make([]*annotation.Instance, 0)
+	for {
+		page, result := q.Query(criteria)
+		if result != status.Success {
+			return annotations, result
+		}
+		annotations = append(annotations, page.Annotations...)
+		if page.Next == "" {
+			return annotations, status.Success
+		}
+		criteria.Cursor = page.Next
+	}
+}
+
+// Locate returns the identities the given annotations are stored against (keyed by unique value) and status.
+//
+// The identities each annotation names are queried for the annotations stored directly against them, current
+// identities first and then predecessors (see annotation.Instance.Predecessors); status.NotFound is returned if an
+// annotation is not stored against any identity it names.
+func Locate(q Contract, annotations []*annotation.Instance) (map[string]identity.Contract, status.Value) {
+	located := make(map[string]identity.Contract, len(annotations))
+	wanted := make(map[string]bool, len(annotations))
+	for a := range annotations {
+		wanted[annotations[a].Unique] = true
+	}
+
+	queried := make(map[string]bool)
+	find := func(id identity.Contract) status.Value {
+		if id == nil || queried[id.Printable()] {
+			return status.Success
+		}
+		queried[id.Printable()] = true
+
+		found, result := All(q, Criteria{Identity: id})
+		switch result {
+		case status.Success:
+		case status.NotFound:
+			return status.Success
+		default:
+			return result
+		}
+		for f := range found {
+			if wanted[found[f].Unique] && located[found[f].Unique] == nil {
+				located[found[f].Unique] = id
+			}
+		}
+		return status.Success
+	}
+
+	for a := range annotations {
+		if located[annotations[a].Unique] != nil {
+			continue
+		}
+		if result := find(annotations[a].CurrentIdentity); result != status.Success {
+			return nil, result
+		}
+	}
+	for a := range annotations {
+		predecessors := annotations[a].Predecessors()
+		for p := 0; p < len(predecessors) && located[annotations[a].Unique] == nil; p++ {
+			if result := find(predecessors[p]); result != status.Success {
+				return nil, result
+			}
+		}
+		if located[annotations[a].Unique] == nil {
+			return nil, status.NotFound
+		}
+	}
+	return located, status.Success
+}
@@ -0,0 +1,317 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// epoch is the created value of the first test annotation.
+var epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newAnnotation returns a new annotation created offset after epoch with the given provenance.
+func newAnnotation(offset time.Duration, p provenance.Contract) *annotation.Instance {
+	m := annotation.New(
+		test.FactoryRandomFixedLengthAlphanumericString(26),
+		identityHash.New(test.FactoryRandomByteSlice()),
+		nil,
+		metadata.New(
+			p,
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			metadataStub.NewNullObject(),
+		),
+	)
+	m.Created = epoch.Add(offset).Format(time.RFC3339Nano)
+	return m
+}
+
+// timeAt returns a pointer to the time offset after epoch.
+func timeAt(offset time.Duration) *time.Time {
+	t := epoch.Add(offset)
+	return &t
+}
+
+// uniques returns the unique values of the given annotations.
+func uniques(annotations []*annotation.Instance) []string {
+	result := make([]string, len(annotations))
+	for i := range annotations {
+		result[i] = annotations[i].Unique
+	}
+	return result
+}
+
+// TestMatch tests Match.
+func TestMatch(t *testing.T) {
+	p := test.FactoryRandomString()
+	m := newAnnotation(time.Minute, p)
+
+	type testCase struct {
+		name     string
+		criteria Criteria
+		m        *annotation.Instance
+		expected bool
+	}
+
+	cases := []testCase{
+		{name: "no conditions", criteria: Criteria{}, m: m, expected: true},
+		{name: "kind matches", criteria: Criteria{Kinds: []string{"other", metadata.Kind}}, m: m, expected: true},
+		{name: "kind does not match", criteria: Criteria{Kinds: []string{"other"}}, m: m, expected: false},
+		{name: "within window", criteria: Criteria{From: timeAt(time.Minute), To: timeAt(2 * time.Minute)}, m: m, expected: true},
+		{name: "before window", criteria: Criteria{From: timeAt(2 * time.Minute)}, m: m, expected: false},
+		{name: "window end is exclusive", criteria: Criteria{To: timeAt(time.Minute)}, m: m, expected: false},
+		{name: "provenance matches", criteria: Criteria{Provenance: []provenance.Contract{p}}, m: m, expected: true},
+		{
+			name:     "provenance does not match",
+			criteria: Criteria{Provenance: []provenance.Contract{test.FactoryRandomString()}},
+			m:        m,
+			expected: false,
+		},
+		{
+			name:     "struct provenance matches decoded provenance",
+			criteria: Criteria{Provenance: []provenance.Contract{struct{ Node string }{Node: "origin"}}},
+			m:        newAnnotation(time.Minute, map[string]interface{}{"Node": "origin"}),
+			expected: true,
+		},
+		{
+			name:     "struct provenance does not match other decoded provenance",
+			criteria: Criteria{Provenance: []provenance.Contract{struct{ Node string }{Node: "origin"}}},
+			m:        newAnnotation(time.Minute, map[string]interface{}{"Node": "evaluation"}),
+			expected: false,
+		},
+		{
+			name:     "provenance not recorded",
+			criteria: Criteria{Provenance: []provenance.Contract{p}},
+			m:        annotation.New(test.FactoryRandomString(), m.CurrentIdentity, nil, metadataStub.NewNullObject()),
+			expected: false,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				assert.Equal(t, cases[i].expected, Match(cases[i].criteria, cases[i].m))
+			},
+		)
+	}
+}
+
+// TestApply tests Apply.
+func TestApply(t *testing.T) {
+	p := test.FactoryRandomString()
+	a0 := newAnnotation(0, p)
+	a1 := newAnnotation(time.Minute, test.FactoryRandomString())
+	a2 := newAnnotation(2*time.Minute, p)
+	a3 := newAnnotation(3*time.Minute, p)
+	candidates := []*annotation.Instance{a2, a0, a3, a1}
+
+	type testCase struct {
+		name     string
+		criteria Criteria
+		expected []string
+	}
+
+	cases := []testCase{
+		{name: "ascending", criteria: Criteria{}, expected: uniques([]*annotation.Instance{a0, a1, a2, a3})},
+		{
+			name:     "descending",
+			criteria: Criteria{Order: Descending},
+			expected: uniques([]*annotation.Instance{a3, a2, a1, a0}),
+		},
+		{
+			name:     "filtered",
+			criteria: Criteria{Provenance: []provenance.Contract{p}, From: timeAt(time.Second)},
+			expected: uniques([]*annotation.Instance{a2, a3}),
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				page, result := Apply(cases[i].criteria, candidates)
+
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, cases[i].expected, uniques(page.Annotations))
+				assert.Equal(t, "", page.Next)
+			},
+		)
+	}
+}
+
+// TestApply_Pagination tests Apply's cursor-based pagination.
+func TestApply_Pagination(t *testing.T) {
+	a0 := newAnnotation(0, nil)
+	a1 := newAnnotation(time.Minute, nil)
+	a2 := newAnnotation(time.Minute, nil)
+	a3 := newAnnotation(2*time.Minute, nil)
+	// a1 and a2 share a created value and are ordered by unique.
+	a1.Unique, a2.Unique = "A"+a1.Unique, "B"+a2.Unique
+	a4 := newAnnotation(3*time.Minute, nil)
+	candidates := []*annotation.Instance{a4, a3, a2, a1, a0}
+
+	type testCase struct {
+		name     string
+		order    Order
+		expected []*annotation.Instance
+	}
+
+	cases := []testCase{
+		{name: "ascending", order: Ascending, expected: []*annotation.Instance{a0, a1, a2, a3, a4}},
+		{name: "descending", order: Descending, expected: []*annotation.Instance{a4, a3, a2, a1, a0}},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				pages := make([][]string, 0)
+				criteria := Criteria{Order: cases[i].order, Limit: 2}
+				for {
+					page, result := Apply(criteria, candidates)
+					assert.Equal(t, status.Success, result)
+					pages = append(pages, uniques(page.Annotations))
+					if page.Next == "" {
+						break
+					}
+					criteria.Cursor = page.Next
+				}
+
+				expected := uniques(cases[i].expected)
+				assert.Equal(t, [][]string{expected[0:2], expected[2:4], expected[4:]}, pages)
+			},
+		)
+	}
+}
+
+// TestApply_InvalidCursor tests Apply with a malformed cursor.
+func TestApply_InvalidCursor(t *testing.T) {
+	page, result := Apply(Criteria{Cursor: "!"}, []*annotation.Instance{newAnnotation(0, nil)})
+
+	assert.Nil(t, page)
+	assert.Equal(t, status.Unknown, result)
+}
+
+// candidates is a query.Contract implementation over a fixed set of annotations.
+type candidates []*annotation.Instance
+
+// Query implements Contract.
+func (c candidates) Query(criteria Criteria) (*Page, status.Value) {
+	return Apply(criteria, c)
+}
+
+// TestAll tests All.
+func TestAll(t *testing.T) {
+	annotations := []*annotation.Instance{
+		newAnnotation(0, nil),
+		newAnnotation(time.Minute, nil),
+		newAnnotation(2*time.Minute, nil),
+	}
+
+	result, s := All(candidates(annotations), Criteria{Limit: 1})
+
+	assert.Equal(t, status.Success, s)
+	assert.Equal(t, uniques(annotations), uniques(result))
+
+	_, s = All(candidates(annotations), Criteria{Cursor: "!"})
+
+	assert.Equal(t, status.Unknown, s)
+}
+
+// stored holds annotations keyed by the printable value of the identity they are stored against.
+type stored map[string][]*annotation.Instance
+
+// Query implements Contract for criteria that restrict results to a single identity (without lineage).
+func (s stored) Query(criteria Criteria) (*Page, status.Value) {
+	m, exists := s[criteria.Identity.Printable()]
+	if !exists {
+		return nil, status.NotFound
+	}
+	return Apply(criteria, m)
+}
+
+// TestLocate tests Locate.
+func TestLocate(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	newIdentity := func() identity.Contract { return identityHash.New(test.FactoryRandomByteSlice()) }
+	newDerived := func(id identity.Contract, predecessors ...identity.Contract) *annotation.Instance {
+		return annotation.NewDerived(
+			test.FactoryRandomFixedLengthAlphanumericString(26),
+			id,
+			predecessors,
+			metadataStub.NewNullObject(),
+		)
+	}
+
+	cases := []testCase{
+		{
+			name: "current identity",
+			test: func(t *testing.T) {
+				id := newIdentity()
+				m := newDerived(id)
+
+				located, result := Locate(stored{id.Printable(): {m}}, []*annotation.Instance{m})
+
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, map[string]identity.Contract{m.Unique: id}, located)
+			},
+		},
+		{
+			name: "predecessor",
+			test: func(t *testing.T) {
+				previous1, previous2, current := newIdentity(), newIdentity(), newIdentity()
+				m := newDerived(current, previous1, previous2)
+
+				located, result := Locate(stored{previous2.Printable(): {m}}, []*annotation.Instance{m})
+
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, map[string]identity.Contract{m.Unique: previous2}, located)
+			},
+		},
+		{
+			name: "not stored against a named identity",
+			test: func(t *testing.T) {
+				m := newDerived(newIdentity())
+
+				located, result := Locate(stored{newIdentity().Printable(): {m}}, []*annotation.Instance{m})
+
+				assert.Equal(t, status.NotFound, result)
+				assert.Nil(t, located)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
@@ -0,0 +1,104 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package relational
+
+import (
+	"database/sql"
+)
+
+// migration is a numbered set of schema statements applied together.
+type migration struct {
+	version    int
+	statements []string
+}
+
+// migrations are the schema migrations in the order they are applied; released migrations must never change.
+var migrations = []migration{
+	{
+		version: 1,
+		statements: []string{
+			`CREATE TABLE identities (
+				identity VARCHAR(512) NOT NULL PRIMARY KEY,
+				kind VARCHAR(64) NOT NULL,
+				data TEXT NOT NULL
+			)`,
+			`CREATE TABLE annotations (
+				sequence BIGINT NOT NULL PRIMARY KEY,
+				unique_value VARCHAR(255) NOT NULL UNIQUE,
+				identity VARCHAR(512) NOT NULL REFERENCES identities (identity),
+				kind VARCHAR(64) NOT NULL,
+				created VARCHAR(64) NOT NULL,
+				created_utc VARCHAR(30) NOT NULL,
+				data TEXT NOT NULL
+			)`,
+			`CREATE INDEX annotations_identity ON annotations (identity, sequence)`,
+			`CREATE INDEX annotations_kind ON annotations (kind, sequence)`,
+			`CREATE INDEX annotations_created_utc ON annotations (created_utc)`,
+			`CREATE TABLE lineage (
+				previous VARCHAR(512) NOT NULL,
+				derived VARCHAR(512) NOT NULL REFERENCES identities (identity),
+				sequence BIGINT NOT NULL,
+				PRIMARY KEY (previous, derived)
+			)`,
+		},
+	},
+}
+
+// version returns the most recently applied migration version (zero if none have been applied).
+func version(db *sql.DB) (int, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL PRIMARY KEY)`)
+	if err != nil {
+		return 0, err
+	}
+
+	var current sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&current); err != nil {
+		return 0, err
+	}
+	return int(current.Int64), nil
+}
+
+// migrate applies each migration newer than the database's schema version, each within its own transaction.
+func migrate(db *sql.DB) error {
+	current, err := version(db)
+	if err != nil {
+		return err
+	}
+
+	for m := range migrations {
+		if migrations[m].version <= current {
+			continue
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		for s := range migrations[m].statements {
+			if _, err := tx.Exec(migrations[m].statements[s]); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, migrations[m].version); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
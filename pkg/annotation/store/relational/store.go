@@ -0,0 +1,424 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// relational implements an annotation store on top of a database/sql database.
+//
+// Statements use "?" placeholders (as SQLite and MySQL drivers do).  The schema is created and upgraded by New; it
+// records annotations (as JSON, alongside their unique value, metadata kind, and created timestamp for ad hoc
+// queries), the identities they are stored against, and the lineage edges from each previous identity to the
+// identities derived from it.  The created_utc column holds each created timestamp converted to fixed-width UTC text
+// so that it sorts chronologically; the created column retains the annotation's original value.
+//
+// Each annotation is numbered one past the highest stored sequence; the sequence primary key rejects a number taken by
+// a concurrent writer, in which case the write is retried.
+package relational
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/datetime"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// attempts is the number of times a write is tried when it fails (such as when a concurrent writer takes its sequence).
+const attempts = 5
+
+// backoff bounds the randomized delay before a write's second attempt; the bound doubles with each further attempt.
+const backoff = 10 * time.Millisecond
+
+// createdFormat is the fixed-width UTC layout of the created_utc column.
+const createdFormat = "2006-01-02T15:04:05.000000000Z"
+
+// createdUTC returns the created_utc value of an annotation's created value; values that cannot be parsed are stored
+// as the zero time (as query.Apply treats them).
+func createdUTC(created string) string {
+	t := time.Time{}
+	if parsed := datetime.TimeFromCreated(created); parsed != nil {
+		t = *parsed
+	}
+	return t.UTC().Format(createdFormat)
+}
+
+// querier is the subset of *sql.DB and *sql.Tx used to read the store.
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	db              *sql.DB
+	identityFactory identityFactory.Contract
+	metadataFactory metadataFactory.Contract
+}
+
+// New is a factory function that migrates db's schema to the current version and returns instance; the caller
+// retains ownership of db.
+func New(
+	db *sql.DB,
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract) (*instance, error) {
+
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+
+	return &instance{
+		db:              db,
+		identityFactory: identityFactory,
+		metadataFactory: metadataFactory,
+	}, nil
+}
+
+// decode converts stored JSON into an annotation using the injected factories.
+func (i *instance) decode(data string) (*annotation.Instance, error) {
+	var a annotation.Instance
+	a.SetIdentityFactory(i.identityFactory)
+	a.SetMetadataFactory(i.metadataFactory)
+	if err := json.Unmarshal([]byte(data), &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// annotations returns the annotations selected by a statement whose only column is the annotation's JSON.
+func (i *instance) annotations(q querier, statement string, args ...interface{}) ([]*annotation.Instance, error) {
+	rows, err := q.Query(statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*annotation.Instance, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		a, err := i.decode(data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
+
+// count returns the single integer selected by a statement.
+func count(q querier, statement string, args ...interface{}) (int, error) {
+	var n int
+	err := q.QueryRow(statement, args...).Scan(&n)
+	return n, err
+}
+
+// exists returns whether identity has been stored.
+func exists(q querier, id identity.Contract) (bool, error) {
+	n, err := count(q, `SELECT COUNT(*) FROM identities WHERE identity = ?`, id.Printable())
+	return n > 0, err
+}
+
+// fetch returns the annotations stored directly against identity.
+func (i *instance) fetch(q querier, id identity.Contract) ([]*annotation.Instance, status.Value) {
+	found, err := exists(q, id)
+	switch {
+	case err != nil:
+		return nil, status.Unknown
+	case !found:
+		return nil, status.NotFound
+	}
+
+	m, err := i.annotations(q, `SELECT data FROM annotations WHERE identity = ? ORDER BY sequence`, id.Printable())
+	if err != nil {
+		return nil, status.Unknown
+	}
+	return m, status.Success
+}
+
+// children returns the identities derived directly from identity; identities of a kind the injected identity factory
+// does not recognize are returned as opaque identities.
+func (i *instance) children(q querier, id identity.Contract) ([]identity.Contract, status.Value) {
+	rows, err := q.Query(
+		`SELECT identities.kind, identities.data FROM lineage
+			JOIN identities ON identities.identity = lineage.derived
+			WHERE lineage.previous = ? ORDER BY lineage.sequence`,
+		id.Printable(),
+	)
+	if err != nil {
+		return nil, status.Unknown
+	}
+	defer rows.Close()
+
+	identities := make([]identity.Contract, 0)
+	for rows.Next() {
+		var kind, data string
+		if err := rows.Scan(&kind, &data); err != nil {
+			return nil, status.Unknown
+		}
+		derived := i.identityFactory.Create(kind, json.RawMessage(data))
+		if derived == nil {
+			derived = opaqueIdentity.New(kind, json.RawMessage(data))
+		}
+		identities = append(identities, derived)
+	}
+	if rows.Err() != nil {
+		return nil, status.Unknown
+	}
+	return identities, status.Success
+}
+
+// view calls read within a transaction so that a traversal sees a consistent snapshot of the store.
+func (i *instance) view(read func(tx *sql.Tx) status.Value) status.Value {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return status.Unknown
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	return read(tx)
+}
+
+// FindByIdentity returns annotations and status corresponding to identity.
+func (i *instance) FindByIdentity(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	annotations := make([]*annotation.Instance, 0)
+	result := i.view(func(tx *sql.Tx) status.Value {
+		var result status.Value
+		annotations, result = lineage.Find(id, func(id identity.Contract) ([]*annotation.Instance, status.Value) {
+			return i.fetch(tx, id)
+		})
+		return result
+	})
+	return annotations, result
+}
+
+// FindDescendants returns the tree of identities derived from identity (with their annotations) and status.
+func (i *instance) FindDescendants(id identity.Contract) (*lineage.Node, status.Value) {
+	var node *lineage.Node
+	result := i.view(func(tx *sql.Tx) status.Value {
+		var result status.Value
+		node, result = lineage.Descendants(
+			id,
+			func(id identity.Contract) ([]*annotation.Instance, status.Value) {
+				return i.fetch(tx, id)
+			},
+			func(id identity.Contract) ([]identity.Contract, status.Value) {
+				return i.children(tx, id)
+			},
+		)
+		return result
+	})
+	if result != status.Success {
+		return nil, result
+	}
+	return node, result
+}
+
+// Query returns the page of annotations satisfying criteria and status.
+//
+// Kind and created window conditions are evaluated by the database when no identity is given; all conditions are
+// (re)applied by query.Apply.
+func (i *instance) Query(criteria query.Criteria) (*query.Page, status.Value) {
+	var candidates []*annotation.Instance
+	result := i.view(func(tx *sql.Tx) status.Value {
+		switch {
+		case criteria.Identity == nil:
+			conditions := make([]string, 0)
+			args := make([]interface{}, 0)
+			if len(criteria.Kinds) > 0 {
+				conditions = append(conditions, `kind IN (?`+strings.Repeat(`, ?`, len(criteria.Kinds)-1)+`)`)
+				for k := range criteria.Kinds {
+					args = append(args, criteria.Kinds[k])
+				}
+			}
+			if criteria.From != nil {
+				conditions = append(conditions, `created_utc >= ?`)
+				args = append(args, criteria.From.UTC().Format(createdFormat))
+			}
+			if criteria.To != nil {
+				conditions = append(conditions, `created_utc < ?`)
+				args = append(args, criteria.To.UTC().Format(createdFormat))
+			}
+			statement := `SELECT data FROM annotations`
+			if len(conditions) > 0 {
+				statement += ` WHERE ` + strings.Join(conditions, ` AND `)
+			}
+			var err error
+			if candidates, err = i.annotations(tx, statement, args...); err != nil {
+				return status.Unknown
+			}
+			return status.Success
+		case criteria.Lineage:
+			var result status.Value
+			candidates, result = lineage.Find(
+				criteria.Identity,
+				func(id identity.Contract) ([]*annotation.Instance, status.Value) {
+					return i.fetch(tx, id)
+				},
+			)
+			return result
+		default:
+			var result status.Value
+			candidates, result = i.fetch(tx, criteria.Identity)
+			return result
+		}
+	})
+	if result != status.Success {
+		return nil, result
+	}
+	return query.Apply(criteria, candidates)
+}
+
+// put stores an annotation, its identity (if new), and its lineage edges within a write transaction.
+func put(tx *sql.Tx, id identity.Contract, m *annotation.Instance, found bool) (status.Value, error) {
+	n, err := count(tx, `SELECT COUNT(*) FROM annotations WHERE unique_value = ?`, m.Unique)
+	if err != nil {
+		return status.Unknown, err
+	}
+	if n > 0 {
+		return status.Exists, nil
+	}
+
+	marshaledAnnotation, err := json.Marshal(m)
+	if err != nil {
+		return status.Unknown, err
+	}
+
+	idAsString := id.Printable()
+	if !found {
+		marshaledIdentity, err := json.Marshal(id)
+		if err != nil {
+			return status.Unknown, err
+		}
+		_, err = tx.Exec(
+			`INSERT INTO identities (identity, kind, data) VALUES (?, ?, ?)`,
+			idAsString,
+			id.Kind(),
+			string(marshaledIdentity),
+		)
+		if err != nil {
+			return status.Unknown, err
+		}
+	}
+
+	sequence, err := count(tx, `SELECT COALESCE(MAX(sequence), 0) + 1 FROM annotations`)
+	if err != nil {
+		return status.Unknown, err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO annotations (sequence, unique_value, identity, kind, created, created_utc, data)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		sequence,
+		m.Unique,
+		idAsString,
+		m.MetadataKind,
+		m.Created,
+		createdUTC(m.Created),
+		string(marshaledAnnotation),
+	)
+	if err != nil {
+		return status.Unknown, err
+	}
+
+	predecessors := m.Predecessors()
+	for p := range predecessors {
+		previous := predecessors[p].Printable()
+		if previous == idAsString {
+			continue
+		}
+		n, err := count(tx, `SELECT COUNT(*) FROM lineage WHERE previous = ? AND derived = ?`, previous, idAsString)
+		if err != nil {
+			return status.Unknown, err
+		}
+		if n > 0 {
+			continue
+		}
+		_, err = tx.Exec(
+			`INSERT INTO lineage (previous, derived, sequence) VALUES (?, ?, ?)`,
+			previous,
+			idAsString,
+			sequence,
+		)
+		if err != nil {
+			return status.Unknown, err
+		}
+	}
+	return status.Success, nil
+}
+
+// attempt stores an annotation within a single transaction if identity's existence matches mustExist; a non-nil error
+// means the transaction failed and may be retried.
+func (i *instance) attempt(id identity.Contract, m *annotation.Instance, mustExist bool) (status.Value, error) {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return status.Unknown, err
+	}
+
+	result := status.Unknown
+	found, err := exists(tx, id)
+	switch {
+	case err != nil:
+	case found && !mustExist:
+		result = status.Exists
+	case !found && mustExist:
+		result = status.NotFound
+	default:
+		result, err = put(tx, id, m, found)
+	}
+
+	if err != nil || result != status.Success {
+		_ = tx.Rollback()
+		return result, err
+	}
+	if err := tx.Commit(); err != nil {
+		return status.Unknown, err
+	}
+	return status.Success, nil
+}
+
+// write stores an annotation if identity's existence matches mustExist and returns status; failed transactions are
+// retried so that a writer whose sequence (or new identity) was taken concurrently re-reads the store and either
+// succeeds with the next sequence or reports the conflicting state.
+func (i *instance) write(id identity.Contract, m *annotation.Instance, mustExist bool) status.Value {
+	result := status.Unknown
+	for n := 0; n < attempts; n++ {
+		if n > 0 {
+			time.Sleep(time.Duration(rand.Int63n(int64(backoff) << uint(n-1))))
+		}
+		var err error
+		if result, err = i.attempt(id, m, mustExist); err == nil {
+			break
+		}
+	}
+	return result
+}
+
+// Create stores annotations corresponding to a new identity and returns status.
+func (i *instance) Create(id identity.Contract, m *annotation.Instance) status.Value {
+	return i.write(id, m, false)
+}
+
+// Append stores annotations corresponding to identity and returns status.
+func (i *instance) Append(id identity.Contract, m *annotation.Instance) status.Value {
+	return i.write(id, m, true)
+}
@@ -0,0 +1,463 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package relational
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	metadataStubFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	pkiFactory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// signerMetadata is the signer metadata embedded in every test annotation.
+var signerMetadata = metadataStub.NewNullObject()
+
+// newPath returns the path of a database file within a new temporary directory and a function to remove it.
+func newPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "store")
+	require.NoError(t, err)
+	return filepath.Join(dir, "annotations.db"), func() { _ = os.RemoveAll(dir) }
+}
+
+// open returns the SQLite database at path.
+func open(t *testing.T, path string) *sql.DB {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	return db
+}
+
+// newSUT returns a new system under test backed by db.
+func newSUT(t *testing.T, db *sql.DB) *instance {
+	sut, err := New(
+		db,
+		identityFactory.New(),
+		metadataFactory.New(
+			[]metadataFactory.Contract{
+				pkiFactory.New([]metadataFactory.Contract{metadataStubFactory.New(signerMetadata)}),
+				metadataStubFactory.New(signerMetadata),
+			},
+		),
+	)
+	require.NoError(t, err)
+	return sut
+}
+
+// withSUT calls f with a new system under test backed by a new database.
+func withSUT(t *testing.T, f func(t *testing.T, sut *instance)) {
+	path, cleanup := newPath(t)
+	defer cleanup()
+	db := open(t, path)
+	defer db.Close()
+
+	f(t, newSUT(t, db))
+}
+
+// newIdentity returns a new random identity.
+func newIdentity() identity.Contract {
+	return identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+}
+
+// newAnnotation returns a new annotation for the given identities.
+func newAnnotation(id, previous identity.Contract) *annotation.Instance {
+	return annotation.New(
+		test.FactoryRandomFixedLengthAlphanumericString(26),
+		id,
+		previous,
+		metadata.New(
+			test.FactoryRandomString(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			signerMetadata,
+		),
+	)
+}
+
+// TestStore_FindByIdentity tests store.FindByIdentity.
+func TestStore_FindByIdentity(t *testing.T) {
+	type testCase struct {
+		name                string
+		identity            identity.Contract
+		preCondition        func(t *testing.T, sut *instance)
+		expectedAnnotations []*annotation.Instance
+		expectedStatus      status.Value
+	}
+
+	cases := []testCase{
+		{
+			name:                "does not exist",
+			identity:            newIdentity(),
+			preCondition:        func(_ *testing.T, _ *instance) {},
+			expectedAnnotations: []*annotation.Instance{},
+			expectedStatus:      status.NotFound,
+		},
+		func() testCase {
+			id := newIdentity()
+			m := newAnnotation(id, nil)
+			return testCase{
+				name:     "exists",
+				identity: id,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id, m))
+				},
+				expectedAnnotations: []*annotation.Instance{m},
+				expectedStatus:      status.Success,
+			}
+		}(),
+		func() testCase {
+			id1, id2 := newIdentity(), newIdentity()
+			m1 := newAnnotation(id1, nil)
+			m2 := newAnnotation(id2, id1)
+			m3 := newAnnotation(id1, id2)
+			return testCase{
+				name:     "reverted identity",
+				identity: id1,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id1, m1))
+					assert.Equal(t, status.Success, sut.Create(id2, m2))
+					assert.Equal(t, status.Success, sut.Append(id1, m3))
+				},
+				expectedAnnotations: []*annotation.Instance{m1, m3, m2},
+				expectedStatus:      status.Success,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				withSUT(t, func(t *testing.T, sut *instance) {
+					cases[i].preCondition(t, sut)
+
+					m, result := sut.FindByIdentity(cases[i].identity)
+
+					assert.Equal(t, cases[i].expectedStatus, result)
+					assert.Equal(t, testInternal.Marshal(t, cases[i].expectedAnnotations), testInternal.Marshal(t, m))
+				})
+			},
+		)
+	}
+}
+
+// TestStore_FindDescendants tests store.FindDescendants.
+func TestStore_FindDescendants(t *testing.T) {
+	type testCase struct {
+		name           string
+		identity       identity.Contract
+		preCondition   func(t *testing.T, sut *instance)
+		expectedNode   *lineage.Node
+		expectedStatus status.Value
+	}
+
+	cases := []testCase{
+		{
+			name:           "does not exist",
+			identity:       newIdentity(),
+			preCondition:   func(_ *testing.T, _ *instance) {},
+			expectedNode:   nil,
+			expectedStatus: status.NotFound,
+		},
+		func() testCase {
+			id1, id2, id3 := newIdentity(), newIdentity(), newIdentity()
+			m1 := newAnnotation(id1, nil)
+			m2 := newAnnotation(id2, id1)
+			m3 := newAnnotation(id2, id1)
+			m4 := newAnnotation(id3, id2)
+			return testCase{
+				name:     "derived tree",
+				identity: id1,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id1, m1))
+					assert.Equal(t, status.Success, sut.Create(id2, m2))
+					assert.Equal(t, status.Success, sut.Append(id2, m3))
+					assert.Equal(t, status.Success, sut.Create(id3, m4))
+				},
+				expectedNode: &lineage.Node{
+					Identity:    id1,
+					Annotations: []*annotation.Instance{m1},
+					Children: []*lineage.Node{
+						{
+							Identity:    id2,
+							Annotations: []*annotation.Instance{m2, m3},
+							Children: []*lineage.Node{
+								{
+									Identity:    id3,
+									Annotations: []*annotation.Instance{m4},
+									Children:    []*lineage.Node{},
+								},
+							},
+						},
+					},
+				},
+				expectedStatus: status.Success,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				withSUT(t, func(t *testing.T, sut *instance) {
+					cases[i].preCondition(t, sut)
+
+					node, result := sut.FindDescendants(cases[i].identity)
+
+					assert.Equal(t, testInternal.Marshal(t, cases[i].expectedNode), testInternal.Marshal(t, node))
+					assert.Equal(t, cases[i].expectedStatus, result)
+				})
+			},
+		)
+	}
+}
+
+// TestStore_Write tests store.Create and store.Append statuses.
+func TestStore_Write(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T, sut *instance)
+	}
+
+	cases := []testCase{
+		{
+			name: "create twice",
+			test: func(t *testing.T, sut *instance) {
+				id := newIdentity()
+				m := newAnnotation(id, nil)
+
+				assert.Equal(t, status.Success, sut.Create(id, m))
+				assert.Equal(t, status.Exists, sut.Create(id, newAnnotation(id, nil)))
+
+				stored, _ := sut.FindByIdentity(id)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m}), testInternal.Marshal(t, stored))
+			},
+		},
+		{
+			name: "append to missing identity",
+			test: func(t *testing.T, sut *instance) {
+				id := newIdentity()
+
+				assert.Equal(t, status.NotFound, sut.Append(id, newAnnotation(id, nil)))
+
+				_, result := sut.FindByIdentity(id)
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+		{
+			name: "duplicate unique",
+			test: func(t *testing.T, sut *instance) {
+				id1, id2 := newIdentity(), newIdentity()
+				m := newAnnotation(id1, nil)
+				require.Equal(t, status.Success, sut.Create(id1, m))
+
+				assert.Equal(t, status.Exists, sut.Append(id1, m))
+				assert.Equal(t, status.Exists, sut.Create(id2, m))
+
+				_, result := sut.FindByIdentity(id2)
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+		{
+			name: "ad hoc sql",
+			test: func(t *testing.T, sut *instance) {
+				id1, id2 := newIdentity(), newIdentity()
+				m := newAnnotation(id2, id1)
+				require.Equal(t, status.Success, sut.Create(id1, newAnnotation(id1, nil)))
+				require.Equal(t, status.Success, sut.Create(id2, m))
+
+				var unique string
+				err := sut.db.QueryRow(
+					`SELECT annotations.unique_value FROM lineage
+						JOIN annotations ON annotations.identity = lineage.derived
+						WHERE lineage.previous = ? AND annotations.kind = ?`,
+					id1.Printable(),
+					metadata.Kind,
+				).Scan(&unique)
+
+				require.NoError(t, err)
+				assert.Equal(t, m.Unique, unique)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				withSUT(t, cases[i].test)
+			},
+		)
+	}
+}
+
+// TestStore_Query tests store.Query.
+func TestStore_Query(t *testing.T) {
+	withSUT(t, func(t *testing.T, sut *instance) {
+		id1, id2, id3 := newIdentity(), newIdentity(), newIdentity()
+		m1 := newAnnotation(id1, nil)
+		m2 := newAnnotation(id2, id1)
+		m3 := annotation.New(test.FactoryRandomFixedLengthAlphanumericString(26), id3, nil, signerMetadata)
+		reference := memory.New()
+		for _, s := range []store.Contract{sut, reference} {
+			require.Equal(t, status.Success, s.Create(id1, m1))
+			require.Equal(t, status.Success, s.Create(id2, m2))
+			require.Equal(t, status.Success, s.Create(id3, m3))
+		}
+
+		type testCase struct {
+			name     string
+			criteria query.Criteria
+		}
+
+		cases := []testCase{
+			{name: "all identities", criteria: query.Criteria{}},
+			{name: "kinds", criteria: query.Criteria{Kinds: []string{metadata.Kind, test.FactoryRandomString()}}},
+			{name: "identity", criteria: query.Criteria{Identity: id2}},
+			{name: "lineage", criteria: query.Criteria{Identity: id2, Lineage: true, Order: query.Descending}},
+			{name: "paginated", criteria: query.Criteria{Limit: 1}},
+		}
+
+		for i := range cases {
+			t.Run(
+				cases[i].name,
+				func(t *testing.T) {
+					page, result := sut.Query(cases[i].criteria)
+
+					assert.Equal(t, status.Success, result)
+					expected, _ := reference.Query(cases[i].criteria)
+					assert.Equal(t, testInternal.Marshal(t, expected), testInternal.Marshal(t, page))
+				},
+			)
+		}
+	})
+}
+
+// TestStore_WriteConcurrent tests that writers sharing a database file are each assigned a distinct sequence.
+func TestStore_WriteConcurrent(t *testing.T) {
+	const writers, writes = 4, 5
+
+	path, cleanup := newPath(t)
+	defer cleanup()
+	db := open(t, path)
+	defer db.Close()
+	_ = newSUT(t, db)
+
+	var wg sync.WaitGroup
+	results := make(chan status.Value, writers*writes)
+	for w := 0; w < writers; w++ {
+		db := open(t, path)
+		defer db.Close()
+		sut := newSUT(t, db)
+		ids := make([]identity.Contract, writes)
+		for n := range ids {
+			ids[n] = newIdentity()
+		}
+		annotations := make([]*annotation.Instance, writes)
+		for n := range annotations {
+			annotations[n] = newAnnotation(ids[n], nil)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range ids {
+				results <- sut.Create(ids[n], annotations[n])
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	for result := range results {
+		assert.Equal(t, status.Success, result)
+	}
+	n, err := count(db, `SELECT COUNT(DISTINCT sequence) FROM annotations`)
+	require.NoError(t, err)
+	assert.Equal(t, writers*writes, n)
+}
+
+// TestStore_QueryCreatedWindow tests that created windows are evaluated chronologically regardless of offset.
+func TestStore_QueryCreatedWindow(t *testing.T) {
+	withSUT(t, func(t *testing.T, sut *instance) {
+		id1, id2 := newIdentity(), newIdentity()
+		m1 := newAnnotation(id1, nil)
+		m1.Created = "2020-01-01T01:00:00+01:00"
+		m2 := newAnnotation(id2, nil)
+		m2.Created = "2020-01-01T00:30:00.5Z"
+		require.Equal(t, status.Success, sut.Create(id1, m1))
+		require.Equal(t, status.Success, sut.Create(id2, m2))
+
+		from := time.Date(2020, 1, 1, 0, 15, 0, 0, time.UTC)
+		page, result := sut.Query(query.Criteria{From: &from})
+
+		assert.Equal(t, status.Success, result)
+		assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m2}), testInternal.Marshal(t, page.Annotations))
+
+		var earliest string
+		require.NoError(t, sut.db.QueryRow(`SELECT unique_value FROM annotations ORDER BY created_utc`).Scan(&earliest))
+		assert.Equal(t, m1.Unique, earliest)
+	})
+}
+
+// TestNew tests New's schema migration.
+func TestNew(t *testing.T) {
+	path, cleanup := newPath(t)
+	defer cleanup()
+	id := newIdentity()
+	m := newAnnotation(id, nil)
+
+	db := open(t, path)
+	require.Equal(t, status.Success, newSUT(t, db).Create(id, m))
+	require.NoError(t, db.Close())
+
+	db = open(t, path)
+	defer db.Close()
+	sut := newSUT(t, db)
+
+	current, err := version(db)
+	require.NoError(t, err)
+	assert.Equal(t, migrations[len(migrations)-1].version, current)
+	n, err := count(db, `SELECT COUNT(*) FROM schema_migrations`)
+	require.NoError(t, err)
+	assert.Equal(t, len(migrations), n)
+	stored, result := sut.FindByIdentity(id)
+	assert.Equal(t, status.Success, result)
+	assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m}), testInternal.Marshal(t, stored))
+}
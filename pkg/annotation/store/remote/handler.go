@@ -0,0 +1,220 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/archive"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// MaxRequestSize is the maximum size in bytes of a request body accepted by the handler returned by NewHandler.
+const MaxRequestSize = 16 << 20
+
+// handler is a receiver that encapsulates required dependencies.
+type handler struct {
+	store           store.Contract
+	identityFactory identityFactory.Contract
+	metadataFactory metadataFactory.Contract
+	hashProvider    hashprovider.Contract
+	limit           int64
+}
+
+// NewHandler is a factory function that returns an http.Handler exposing store; query and export are available if
+// store implements query.Contract, and exported archives are digested with hashProvider.  Request bodies larger than
+// MaxRequestSize are rejected.
+func NewHandler(
+	store store.Contract,
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract,
+	hashProvider hashprovider.Contract) http.Handler {
+
+	return newHandler(store, identityFactory, metadataFactory, hashProvider, MaxRequestSize)
+}
+
+// newHandler returns an http.Handler exposing store that rejects request bodies larger than limit bytes.
+func newHandler(
+	store store.Contract,
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract,
+	hashProvider hashprovider.Contract,
+	limit int64) http.Handler {
+
+	h := &handler{
+		store:           store,
+		identityFactory: identityFactory,
+		metadataFactory: metadataFactory,
+		hashProvider:    hashProvider,
+		limit:           limit,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(CreatePath, h.post(h.create))
+	mux.HandleFunc(AppendPath, h.post(h.append))
+	mux.HandleFunc(FindPath, h.post(h.find))
+	mux.HandleFunc(DescendantsPath, h.post(h.descendants))
+	mux.HandleFunc(QueryPath, h.query)
+	mux.HandleFunc(ExportPath, h.export)
+	return mux
+}
+
+// respond writes value as a JSON response body with the HTTP status code corresponding to result.
+func respond(w http.ResponseWriter, result status.Value, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(result))
+	if value != nil {
+		_ = json.NewEncoder(w).Encode(value)
+	}
+}
+
+// decode decodes the JSON request body (of at most h.limit bytes) into v; if it cannot, it writes an error response
+// and returns false.
+func (h *handler) decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.ContentLength > h.limit {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return false
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.limit)).Decode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// post returns a handler that decodes an identity-based request and passes it to serve.
+func (h *handler) post(serve func(w http.ResponseWriter, id identity.Contract, r *request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		var body request
+		if !h.decode(w, r, &body) {
+			return
+		}
+		id, err := decodeIdentity(h.identityFactory, body.IdentityKind, body.Identity)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		serve(w, id, &body)
+	}
+}
+
+// write decodes the request's annotation and stores it with write.
+func (h *handler) write(
+	w http.ResponseWriter,
+	id identity.Contract,
+	r *request,
+	write func(id identity.Contract, m *annotation.Instance) status.Value) {
+
+	m, err := decodeAnnotation(h.identityFactory, h.metadataFactory, r.Annotation)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	respond(w, write(id, m), nil)
+}
+
+// create serves CreatePath.
+func (h *handler) create(w http.ResponseWriter, id identity.Contract, r *request) {
+	h.write(w, id, r, h.store.Create)
+}
+
+// append serves AppendPath.
+func (h *handler) append(w http.ResponseWriter, id identity.Contract, r *request) {
+	h.write(w, id, r, h.store.Append)
+}
+
+// find serves FindPath.
+func (h *handler) find(w http.ResponseWriter, id identity.Contract, _ *request) {
+	annotations, result := h.store.FindByIdentity(id)
+	respond(w, result, annotations)
+}
+
+// descendants serves DescendantsPath.
+func (h *handler) descendants(w http.ResponseWriter, id identity.Contract, _ *request) {
+	n, result := h.store.FindDescendants(id)
+	respond(w, result, newNode(n))
+}
+
+// query serves QueryPath.
+func (h *handler) query(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	q, ok := h.store.(query.Contract)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotImplemented), http.StatusNotImplemented)
+		return
+	}
+
+	var body criteria
+	if !h.decode(w, r, &body) {
+		return
+	}
+	c, err := decodeCriteria(h.identityFactory, &body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, value := q.Query(c)
+	if value != status.Success {
+		respond(w, value, nil)
+		return
+	}
+
+	response := page{Annotations: make([]json.RawMessage, len(result.Annotations)), Next: result.Next}
+	for a := range result.Annotations {
+		if response.Annotations[a], err = json.Marshal(result.Annotations[a]); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	respond(w, status.Success, response)
+}
+
+// export serves ExportPath.
+func (h *handler) export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if _, ok := h.store.(query.Contract); !ok {
+		http.Error(w, http.StatusText(http.StatusNotImplemented), http.StatusNotImplemented)
+		return
+	}
+
+	var b bytes.Buffer
+	if _, err := archive.Export(&b, h.store, nil, h.hashProvider); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	_, _ = b.WriteTo(w)
+}
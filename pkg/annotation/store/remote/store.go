@@ -0,0 +1,195 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// remote implements an annotation store backed by a store served over HTTP, and the handler that serves a store.
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	url             string
+	client          *http.Client
+	identityFactory identityFactory.Contract
+	metadataFactory metadataFactory.Contract
+}
+
+// New is a factory function that returns an initialized instance for the store served at url; a nil client uses
+// http.DefaultClient.
+func New(
+	url string,
+	client *http.Client,
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract) *instance {
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &instance{
+		url:             strings.TrimSuffix(url, "/"),
+		client:          client,
+		identityFactory: identityFactory,
+		metadataFactory: metadataFactory,
+	}
+}
+
+// do sends a request to the server and returns the response (which the caller must close) and status; the response
+// is nil unless status is Success.
+func (i *instance) do(method, path string, body interface{}) (*http.Response, status.Value) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, status.Unknown
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	r, err := http.NewRequest(method, i.url+path, reader)
+	if err != nil {
+		return nil, status.Unknown
+	}
+	if body != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := i.client.Do(r)
+	if err != nil {
+		return nil, status.Unknown
+	}
+	if result := storeStatus(response.StatusCode); result != status.Success {
+		_, _ = io.Copy(ioutil.Discard, response.Body)
+		_ = response.Body.Close()
+		return nil, result
+	}
+	return response, status.Success
+}
+
+// call sends an identity-based request to the server and decodes the JSON response body into result (if not nil).
+func (i *instance) call(path string, id identity.Contract, m *annotation.Instance, result interface{}) status.Value {
+	marshaledIdentity, err := json.Marshal(id)
+	if err != nil {
+		return status.Unknown
+	}
+	body := request{IdentityKind: id.Kind(), Identity: marshaledIdentity}
+	if m != nil {
+		if body.Annotation, err = json.Marshal(m); err != nil {
+			return status.Unknown
+		}
+	}
+
+	response, value := i.do(http.MethodPost, path, body)
+	if value != status.Success {
+		return value
+	}
+	defer response.Body.Close()
+
+	if result != nil {
+		if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+			return status.Unknown
+		}
+	}
+	return status.Success
+}
+
+// FindByIdentity returns annotations and status corresponding to identity.
+func (i *instance) FindByIdentity(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	var data []json.RawMessage
+	if result := i.call(FindPath, id, nil, &data); result != status.Success {
+		return nil, result
+	}
+
+	annotations, err := decodeAnnotations(i.identityFactory, i.metadataFactory, data)
+	if err != nil {
+		return nil, status.Unknown
+	}
+	return annotations, status.Success
+}
+
+// FindDescendants returns the tree of identities derived from identity (with their annotations) and status.
+func (i *instance) FindDescendants(id identity.Contract) (*lineage.Node, status.Value) {
+	var data decodedNode
+	if result := i.call(DescendantsPath, id, nil, &data); result != status.Success {
+		return nil, result
+	}
+
+	n, err := decodeNode(i.identityFactory, i.metadataFactory, &data)
+	if err != nil {
+		return nil, status.Unknown
+	}
+	return n, status.Success
+}
+
+// Create stores annotations corresponding to a new identity and returns status.
+func (i *instance) Create(id identity.Contract, m *annotation.Instance) status.Value {
+	return i.call(CreatePath, id, m, nil)
+}
+
+// Append stores annotations corresponding to identity and returns status.
+func (i *instance) Append(id identity.Contract, m *annotation.Instance) status.Value {
+	return i.call(AppendPath, id, m, nil)
+}
+
+// Query returns the page of annotations satisfying criteria and status.
+func (i *instance) Query(c query.Criteria) (*query.Page, status.Value) {
+	body, err := newCriteria(c)
+	if err != nil {
+		return nil, status.Unknown
+	}
+
+	response, result := i.do(http.MethodPost, QueryPath, body)
+	if result != status.Success {
+		return nil, result
+	}
+	defer response.Body.Close()
+
+	var data page
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, status.Unknown
+	}
+	annotations, err := decodeAnnotations(i.identityFactory, i.metadataFactory, data.Annotations)
+	if err != nil {
+		return nil, status.Unknown
+	}
+	return &query.Page{Annotations: annotations, Next: data.Next}, status.Success
+}
+
+// Export writes an archive (see the archive package) of every annotation in the store to w.
+func (i *instance) Export(w io.Writer) error {
+	response, result := i.do(http.MethodGet, ExportPath, nil)
+	if result != status.Success {
+		return fmt.Errorf("export failed with status %d", result)
+	}
+	defer response.Body.Close()
+
+	_, err := io.Copy(w, response.Body)
+	return err
+}
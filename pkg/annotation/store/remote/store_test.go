@@ -0,0 +1,523 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/archive"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	metadataStubFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/chain"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	pkiFactory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signerMetadata is the signer metadata embedded in every test annotation.
+var signerMetadata = metadataStub.NewNullObject()
+
+// newMetadataFactory returns a metadata factory able to decode test annotations.
+func newMetadataFactory() metadataFactory.Contract {
+	return metadataFactory.New(
+		[]metadataFactory.Contract{
+			pkiFactory.New([]metadataFactory.Contract{metadataStubFactory.New(signerMetadata)}),
+		},
+	)
+}
+
+// newServer returns a test server exposing backing.
+func newServer(backing store.Contract) *httptest.Server {
+	return httptest.NewServer(NewHandler(backing, identityFactory.New(), newMetadataFactory(), sha256.New()))
+}
+
+// newSUT returns a new system under test backed by a server exposing backing, and a function to stop the server.
+func newSUT(backing store.Contract) (*instance, func()) {
+	server := newServer(backing)
+	return New(server.URL, server.Client(), identityFactory.New(), newMetadataFactory()), server.Close
+}
+
+// newIdentity returns a new random identity.
+func newIdentity() identity.Contract {
+	return identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+}
+
+// newAnnotation returns a new annotation for the given identities.
+func newAnnotation(id, previous identity.Contract) *annotation.Instance {
+	return annotation.New(
+		test.FactoryRandomFixedLengthAlphanumericString(26),
+		id,
+		previous,
+		metadata.New(
+			test.FactoryRandomString(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			signerMetadata,
+		),
+	)
+}
+
+// TestStore_FindByIdentity tests store.FindByIdentity.
+func TestStore_FindByIdentity(t *testing.T) {
+	type testCase struct {
+		name                string
+		identity            identity.Contract
+		preCondition        func(t *testing.T, sut *instance)
+		expectedAnnotations []*annotation.Instance
+		expectedStatus      status.Value
+	}
+
+	cases := []testCase{
+		{
+			name:                "does not exist",
+			identity:            newIdentity(),
+			preCondition:        func(_ *testing.T, _ *instance) {},
+			expectedAnnotations: nil,
+			expectedStatus:      status.NotFound,
+		},
+		func() testCase {
+			id := newIdentity()
+			m := newAnnotation(id, nil)
+			return testCase{
+				name:     "exists",
+				identity: id,
+				preCondition: func(t *testing.T, sut *instance) {
+					require.Equal(t, status.Success, sut.Create(id, m))
+				},
+				expectedAnnotations: []*annotation.Instance{m},
+				expectedStatus:      status.Success,
+			}
+		}(),
+		func() testCase {
+			id1 := newIdentity()
+			id2 := newIdentity()
+			m1 := newAnnotation(id1, nil)
+			m2 := newAnnotation(id1, nil)
+			m3 := newAnnotation(id2, id1)
+			return testCase{
+				name:     "appended with lineage",
+				identity: id2,
+				preCondition: func(t *testing.T, sut *instance) {
+					require.Equal(t, status.Success, sut.Create(id1, m1))
+					require.Equal(t, status.Success, sut.Append(id1, m2))
+					require.Equal(t, status.Success, sut.Create(id2, m3))
+				},
+				expectedAnnotations: []*annotation.Instance{m3, m1, m2},
+				expectedStatus:      status.Success,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut, closer := newSUT(memory.New())
+				defer closer()
+				cases[i].preCondition(t, sut)
+
+				m, result := sut.FindByIdentity(cases[i].identity)
+
+				assert.Equal(t, cases[i].expectedStatus, result)
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expectedAnnotations), testInternal.Marshal(t, m))
+			},
+		)
+	}
+}
+
+// TestStore_Write tests store.Create and store.Append statuses.
+func TestStore_Write(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "create existing",
+			test: func(t *testing.T) {
+				sut, closer := newSUT(memory.New())
+				defer closer()
+				id := newIdentity()
+				require.Equal(t, status.Success, sut.Create(id, newAnnotation(id, nil)))
+
+				assert.Equal(t, status.Exists, sut.Create(id, newAnnotation(id, nil)))
+			},
+		},
+		{
+			name: "append missing",
+			test: func(t *testing.T) {
+				sut, closer := newSUT(memory.New())
+				defer closer()
+				id := newIdentity()
+
+				assert.Equal(t, status.NotFound, sut.Append(id, newAnnotation(id, nil)))
+			},
+		},
+		{
+			name: "server unavailable",
+			test: func(t *testing.T) {
+				sut, closer := newSUT(memory.New())
+				closer()
+				id := newIdentity()
+
+				assert.Equal(t, status.Unknown, sut.Create(id, newAnnotation(id, nil)))
+				m, result := sut.FindByIdentity(id)
+				assert.Nil(t, m)
+				assert.Equal(t, status.Unknown, result)
+			},
+		},
+		{
+			name: "stored in backing store",
+			test: func(t *testing.T) {
+				backing := memory.New()
+				sut, closer := newSUT(backing)
+				defer closer()
+				id := newIdentity()
+				m := newAnnotation(id, nil)
+
+				require.Equal(t, status.Success, sut.Create(id, m))
+
+				annotations, result := backing.FindByIdentity(id)
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m}), testInternal.Marshal(t, annotations))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestStore_FindDescendants tests store.FindDescendants.
+func TestStore_FindDescendants(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "does not exist",
+			test: func(t *testing.T) {
+				sut, closer := newSUT(memory.New())
+				defer closer()
+
+				n, result := sut.FindDescendants(newIdentity())
+
+				assert.Nil(t, n)
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+		{
+			name: "same tree as backing store",
+			test: func(t *testing.T) {
+				backing := memory.New()
+				sut, closer := newSUT(backing)
+				defer closer()
+				id1, id2, id3 := newIdentity(), newIdentity(), newIdentity()
+				require.Equal(t, status.Success, sut.Create(id1, newAnnotation(id1, nil)))
+				require.Equal(t, status.Success, sut.Create(id2, newAnnotation(id2, id1)))
+				require.Equal(t, status.Success, sut.Create(id3, newAnnotation(id3, id2)))
+
+				n, result := sut.FindDescendants(id1)
+
+				require.Equal(t, status.Success, result)
+				expected, _ := backing.FindDescendants(id1)
+				assert.Equal(t, testInternal.Marshal(t, expected), testInternal.Marshal(t, n))
+				assert.Equal(t, id3.Printable(), n.Children[0].Children[0].Identity.Printable())
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestStore_UnrecognizedIdentity tests that identities of a kind neither factory recognizes pass through the store
+// and handler unchanged.
+func TestStore_UnrecognizedIdentity(t *testing.T) {
+	backing := memory.New()
+	sut, closer := newSUT(backing)
+	defer closer()
+	id1 := opaqueIdentity.New(test.FactoryRandomString(), json.RawMessage(`{"value":"`+test.FactoryRandomString()+`"}`))
+	id2 := newIdentity()
+	m1, m2 := newAnnotation(id1, nil), newAnnotation(id2, id1)
+
+	require.Equal(t, status.Success, sut.Create(id1, m1))
+	require.Equal(t, status.Success, sut.Create(id2, m2))
+
+	stored, result := sut.FindByIdentity(id1)
+	assert.Equal(t, status.Success, result)
+	assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m1}), testInternal.Marshal(t, stored))
+	n, result := sut.FindDescendants(id1)
+	require.Equal(t, status.Success, result)
+	expected, _ := backing.FindDescendants(id1)
+	assert.Equal(t, testInternal.Marshal(t, expected), testInternal.Marshal(t, n))
+	assert.Equal(t, id1.Kind(), n.Identity.Kind())
+}
+
+// TestStore_Query tests store.Query.
+func TestStore_Query(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "paginated results match backing store",
+			test: func(t *testing.T) {
+				backing := memory.New()
+				sut, closer := newSUT(backing)
+				defer closer()
+				id1, id2 := newIdentity(), newIdentity()
+				require.Equal(t, status.Success, sut.Create(id1, newAnnotation(id1, nil)))
+				require.Equal(t, status.Success, sut.Append(id1, newAnnotation(id1, nil)))
+				require.Equal(t, status.Success, sut.Create(id2, newAnnotation(id2, id1)))
+				criteria := query.Criteria{Identity: id2, Lineage: true, Order: query.Descending, Limit: 1}
+
+				annotations, result := query.All(sut, criteria)
+
+				require.Equal(t, status.Success, result)
+				expected, _ := query.All(backing, query.Criteria{Identity: id2, Lineage: true, Order: query.Descending})
+				assert.Len(t, annotations, 3)
+				assert.Equal(t, testInternal.Marshal(t, expected), testInternal.Marshal(t, annotations))
+			},
+		},
+		{
+			name: "provenance matches whether stored directly or through the client",
+			test: func(t *testing.T) {
+				backing := memory.New()
+				sut, closer := newSUT(backing)
+				defer closer()
+				p := struct{ Node string }{Node: test.FactoryRandomString()}
+				id1, id2 := newIdentity(), newIdentity()
+				m1 := annotation.New(test.FactoryRandomString(), id1, nil, metadata.New(p, nil, nil, nil, signerMetadata))
+				m2 := annotation.New(test.FactoryRandomString(), id2, nil, metadata.New(p, nil, nil, nil, signerMetadata))
+				require.Equal(t, status.Success, backing.Create(id1, m1))
+				require.Equal(t, status.Success, sut.Create(id2, m2))
+				id3 := newIdentity()
+				require.Equal(t, status.Success, sut.Create(id3, newAnnotation(id3, nil)))
+
+				annotations, result := query.All(sut, query.Criteria{Provenance: []provenance.Contract{p}})
+
+				require.Equal(t, status.Success, result)
+				require.Len(t, annotations, 2)
+				assert.ElementsMatch(t, []string{m1.Unique, m2.Unique}, []string{annotations[0].Unique, annotations[1].Unique})
+			},
+		},
+		{
+			name: "invalid cursor",
+			test: func(t *testing.T) {
+				sut, closer := newSUT(memory.New())
+				defer closer()
+
+				page, result := sut.Query(query.Criteria{Cursor: "!"})
+
+				assert.Nil(t, page)
+				assert.Equal(t, status.Unknown, result)
+			},
+		},
+		{
+			name: "query not supported by backing store",
+			test: func(t *testing.T) {
+				sut, closer := newSUT(chain.New(memory.New(), sha256.New()))
+				defer closer()
+
+				page, result := sut.Query(query.Criteria{})
+
+				assert.Nil(t, page)
+				assert.Equal(t, status.Unknown, result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestStore_Export tests store.Export.
+func TestStore_Export(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "imports into another store",
+			test: func(t *testing.T) {
+				sut, closer := newSUT(memory.New())
+				defer closer()
+				id1, id2 := newIdentity(), newIdentity()
+				require.Equal(t, status.Success, sut.Create(id1, newAnnotation(id1, nil)))
+				require.Equal(t, status.Success, sut.Create(id2, newAnnotation(id2, id1)))
+				var b bytes.Buffer
+
+				require.NoError(t, sut.Export(&b))
+
+				target := memory.New()
+				manifest, err := archive.Import(&b, target, sha256.New(), identityFactory.New(), newMetadataFactory())
+				require.NoError(t, err)
+				assert.Equal(t, 2, manifest.Count)
+				expected, _ := sut.FindByIdentity(id2)
+				actual, result := target.FindByIdentity(id2)
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, testInternal.Marshal(t, expected), testInternal.Marshal(t, actual))
+			},
+		},
+		{
+			name: "export not supported by backing store",
+			test: func(t *testing.T) {
+				sut, closer := newSUT(chain.New(memory.New(), sha256.New()))
+				defer closer()
+
+				assert.Error(t, sut.Export(&bytes.Buffer{}))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestHandler tests NewHandler's responses to invalid requests.
+func TestHandler(t *testing.T) {
+	type testCase struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+	}
+
+	cases := []testCase{
+		{name: "create wrong method", method: http.MethodGet, path: CreatePath, expectedCode: http.StatusMethodNotAllowed},
+		{name: "query wrong method", method: http.MethodGet, path: QueryPath, expectedCode: http.StatusMethodNotAllowed},
+		{name: "export wrong method", method: http.MethodPost, path: ExportPath, expectedCode: http.StatusMethodNotAllowed},
+		{name: "malformed body", method: http.MethodPost, path: FindPath, body: "{", expectedCode: http.StatusBadRequest},
+		{
+			name:         "unknown identity kind",
+			method:       http.MethodPost,
+			path:         FindPath,
+			body:         `{"identityType":"unknown","identity":"{}"}`,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "missing identity",
+			method:       http.MethodPost,
+			path:         FindPath,
+			body:         `{}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "missing annotation",
+			method:       http.MethodPost,
+			path:         CreatePath,
+			body:         `{"identityType":"hash","identity":{"hash":"AA=="}}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "malformed criteria",
+			method:       http.MethodPost,
+			path:         QueryPath,
+			body:         "[",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				server := newServer(memory.New())
+				defer server.Close()
+				r, err := http.NewRequest(cases[i].method, server.URL+cases[i].path, strings.NewReader(cases[i].body))
+				require.NoError(t, err)
+
+				response, err := server.Client().Do(r)
+
+				require.NoError(t, err)
+				_ = response.Body.Close()
+				assert.Equal(t, cases[i].expectedCode, response.StatusCode)
+			},
+		)
+	}
+}
+
+// TestHandler_RequestSize tests that the handler rejects request bodies larger than its limit.
+func TestHandler_RequestSize(t *testing.T) {
+	type testCase struct {
+		name          string
+		contentLength bool
+		expectedCode  int
+	}
+
+	cases := []testCase{
+		{name: "declared length", contentLength: true, expectedCode: http.StatusRequestEntityTooLarge},
+		{name: "undeclared length", contentLength: false, expectedCode: http.StatusBadRequest},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				server := httptest.NewServer(
+					newHandler(memory.New(), identityFactory.New(), newMetadataFactory(), sha256.New(), 16),
+				)
+				defer server.Close()
+				body := `{"identityType":"hash","identity":{"hash":"` + strings.Repeat("A", 32) + `"}}`
+				r, err := http.NewRequest(http.MethodPost, server.URL+FindPath, strings.NewReader(body))
+				require.NoError(t, err)
+				if !cases[i].contentLength {
+					r.ContentLength = -1
+				}
+
+				response, err := server.Client().Do(r)
+
+				require.NoError(t, err)
+				_ = response.Body.Close()
+				assert.Equal(t, cases[i].expectedCode, response.StatusCode)
+			},
+		)
+	}
+}
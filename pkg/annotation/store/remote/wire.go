@@ -0,0 +1,274 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+const (
+	// CreatePath stores an annotation against a new identity (POST).
+	CreatePath = "/annotations/create"
+
+	// AppendPath stores an annotation against an existing identity (POST).
+	AppendPath = "/annotations/append"
+
+	// FindPath returns the annotations for an identity and its previous identities (POST).
+	FindPath = "/annotations/find"
+
+	// DescendantsPath returns the tree of identities derived from an identity (POST).
+	DescendantsPath = "/annotations/descendants"
+
+	// QueryPath returns a page of annotations satisfying query criteria (POST).
+	QueryPath = "/annotations/query"
+
+	// ExportPath returns an archive of every annotation in the store (GET).
+	ExportPath = "/annotations/export"
+)
+
+// request is the request body of the identity-based endpoints.
+type request struct {
+	IdentityKind string          `json:"identityType"`
+	Identity     json.RawMessage `json:"identity"`
+	Annotation   json.RawMessage `json:"annotation,omitempty"`
+}
+
+// node is the wire representation of lineage.Node, which carries its identity's kind.
+type node struct {
+	IdentityKind string                 `json:"identityType"`
+	Identity     identity.Contract      `json:"identity"`
+	Annotations  []*annotation.Instance `json:"annotations"`
+	Children     []*node                `json:"children"`
+}
+
+// decodedNode is the structure node is decoded into before its contents are converted with factories.
+type decodedNode struct {
+	IdentityKind string            `json:"identityType"`
+	Identity     json.RawMessage   `json:"identity"`
+	Annotations  []json.RawMessage `json:"annotations"`
+	Children     []*decodedNode    `json:"children"`
+}
+
+// criteria is the wire representation of query.Criteria.
+type criteria struct {
+	IdentityKind string            `json:"identityType,omitempty"`
+	Identity     json.RawMessage   `json:"identity,omitempty"`
+	Lineage      bool              `json:"lineage,omitempty"`
+	Kinds        []string          `json:"kinds,omitempty"`
+	From         *time.Time        `json:"from,omitempty"`
+	To           *time.Time        `json:"to,omitempty"`
+	Provenance   []json.RawMessage `json:"provenance,omitempty"`
+	Order        query.Order       `json:"order,omitempty"`
+	Limit        int               `json:"limit,omitempty"`
+	Cursor       string            `json:"cursor,omitempty"`
+}
+
+// page is the wire representation of query.Page.
+type page struct {
+	Annotations []json.RawMessage `json:"annotations"`
+	Next        string            `json:"next,omitempty"`
+}
+
+// httpStatus returns the HTTP status code corresponding to a store status.
+func httpStatus(result status.Value) int {
+	switch result {
+	case status.Success:
+		return http.StatusOK
+	case status.NotFound:
+		return http.StatusNotFound
+	case status.Exists:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// storeStatus returns the store status corresponding to an HTTP status code.
+func storeStatus(code int) status.Value {
+	switch code {
+	case http.StatusOK:
+		return status.Success
+	case http.StatusNotFound:
+		return status.NotFound
+	case http.StatusConflict:
+		return status.Exists
+	default:
+		return status.Unknown
+	}
+}
+
+// newNode converts a lineage.Node to its wire representation.
+func newNode(n *lineage.Node) *node {
+	if n == nil {
+		return nil
+	}
+
+	result := &node{
+		Identity:    n.Identity,
+		Annotations: n.Annotations,
+		Children:    make([]*node, len(n.Children)),
+	}
+	if n.Identity != nil {
+		result.IdentityKind = n.Identity.Kind()
+	}
+	for c := range n.Children {
+		result.Children[c] = newNode(n.Children[c])
+	}
+	return result
+}
+
+// decodeIdentity converts JSON into an identity using the injected factory; identities of a kind the factory does not
+// recognize are returned as opaque identities.
+func decodeIdentity(factory identityFactory.Contract, kind string, data json.RawMessage) (identity.Contract, error) {
+	if kind == "" || len(data) == 0 {
+		return nil, errors.New("missing identity")
+	}
+	if id := factory.Create(kind, data); id != nil {
+		return id, nil
+	}
+	return opaqueIdentity.New(kind, data), nil
+}
+
+// decodeAnnotation converts JSON into an annotation using the injected factories.
+func decodeAnnotation(
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract,
+	data json.RawMessage) (*annotation.Instance, error) {
+
+	var m annotation.Instance
+	m.SetIdentityFactory(identityFactory)
+	m.SetMetadataFactory(metadataFactory)
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if m.CurrentIdentity == nil || m.Metadata == nil {
+		return nil, errors.New("incomplete annotation")
+	}
+	return &m, nil
+}
+
+// decodeAnnotations converts a list of JSON values into annotations using the injected factories.
+func decodeAnnotations(
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract,
+	data []json.RawMessage) ([]*annotation.Instance, error) {
+
+	annotations := make([]*annotation.Instance, len(data))
+	for a := range data {
+		m, err := decodeAnnotation(identityFactory, metadataFactory, data[a])
+		if err != nil {
+			return nil, err
+		}
+		annotations[a] = m
+	}
+	return annotations, nil
+}
+
+// decodeNode converts a decoded wire node into a lineage.Node using the injected factories.
+func decodeNode(
+	identityFactory identityFactory.Contract,
+	metadataFactory metadataFactory.Contract,
+	n *decodedNode) (*lineage.Node, error) {
+
+	id, err := decodeIdentity(identityFactory, n.IdentityKind, n.Identity)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := decodeAnnotations(identityFactory, metadataFactory, n.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &lineage.Node{Identity: id, Annotations: annotations, Children: make([]*lineage.Node, len(n.Children))}
+	for c := range n.Children {
+		if result.Children[c], err = decodeNode(identityFactory, metadataFactory, n.Children[c]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// newCriteria converts query.Criteria to its wire representation.
+func newCriteria(c query.Criteria) (*criteria, error) {
+	result := &criteria{
+		Lineage: c.Lineage,
+		Kinds:   c.Kinds,
+		From:    c.From,
+		To:      c.To,
+		Order:   c.Order,
+		Limit:   c.Limit,
+		Cursor:  c.Cursor,
+	}
+
+	if c.Identity != nil {
+		data, err := json.Marshal(c.Identity)
+		if err != nil {
+			return nil, err
+		}
+		result.IdentityKind = c.Identity.Kind()
+		result.Identity = data
+	}
+
+	for p := range c.Provenance {
+		data, err := json.Marshal(c.Provenance[p])
+		if err != nil {
+			return nil, err
+		}
+		result.Provenance = append(result.Provenance, data)
+	}
+	return result, nil
+}
+
+// decodeCriteria converts the wire representation of query criteria into query.Criteria; provenance values are
+// passed on as their JSON, which query.Match compares by canonical encoding with the provenance a store holds.
+func decodeCriteria(factory identityFactory.Contract, c *criteria) (query.Criteria, error) {
+	result := query.Criteria{
+		Lineage: c.Lineage,
+		Kinds:   c.Kinds,
+		From:    c.From,
+		To:      c.To,
+		Order:   c.Order,
+		Limit:   c.Limit,
+		Cursor:  c.Cursor,
+	}
+
+	if c.IdentityKind != "" {
+		id, err := decodeIdentity(factory, c.IdentityKind, c.Identity)
+		if err != nil {
+			return result, err
+		}
+		result.Identity = id
+	}
+
+	for p := range c.Provenance {
+		if !json.Valid(c.Provenance[p]) {
+			return result, errors.New("invalid provenance")
+		}
+		result.Provenance = append(result.Provenance, c.Provenance[p])
+	}
+	return result, nil
+}
@@ -0,0 +1,196 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// sharded implements a lock-striped in-memory annotation store for concurrent, high-throughput use.
+package sharded
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// DefaultShards is the number of shards used when New is given a non-positive count.
+const DefaultShards = 64
+
+// shard is a partition of the store guarded by its own lock.
+type shard struct {
+	m           sync.RWMutex
+	data        map[string][]*annotation.Instance
+	derivations map[string][]identity.Contract
+}
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	shards []*shard
+}
+
+// New is a factory function that returns an instance partitioned into count shards.
+func New(count int) *instance {
+	if count <= 0 {
+		count = DefaultShards
+	}
+
+	shards := make([]*shard, count)
+	for s := range shards {
+		shards[s] = &shard{
+			m:           sync.RWMutex{},
+			data:        make(map[string][]*annotation.Instance),
+			derivations: make(map[string][]identity.Contract),
+		}
+	}
+	return &instance{
+		shards: shards,
+	}
+}
+
+// shard returns the shard owning the identity with printable value id.
+func (i *instance) shard(id string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return i.shards[h.Sum32()%uint32(len(i.shards))]
+}
+
+// fetch returns the annotations stored directly against identity.
+//
+// The returned slice's capacity is limited to its length so a later append to the identity cannot alias it.
+func (i *instance) fetch(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	idAsString := id.Printable()
+	s := i.shard(idAsString)
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	m, exists := s.data[idAsString]
+	if !exists {
+		return nil, status.NotFound
+	}
+	return m[:len(m):len(m)], status.Success
+}
+
+// children returns the identities derived directly from identity.
+func (i *instance) children(id identity.Contract) ([]identity.Contract, status.Value) {
+	idAsString := id.Printable()
+	s := i.shard(idAsString)
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	c := s.derivations[idAsString]
+	return c[:len(c):len(c)], status.Success
+}
+
+// derive records that identity was derived from each of the annotation's predecessors.
+//
+// Derivations are held by the predecessor's shard, so each is recorded under that shard's lock alone; no two shard
+// locks are ever held at once.
+func (i *instance) derive(id identity.Contract, m *annotation.Instance) {
+	idAsString := id.Printable()
+	predecessors := m.Predecessors()
+	for p := range predecessors {
+		previous := predecessors[p].Printable()
+		if previous == idAsString {
+			continue
+		}
+
+		s := i.shard(previous)
+		s.m.Lock()
+		derived := false
+		for d := range s.derivations[previous] {
+			if s.derivations[previous][d].Printable() == idAsString {
+				derived = true
+				break
+			}
+		}
+		if !derived {
+			s.derivations[previous] = append(s.derivations[previous], id)
+		}
+		s.m.Unlock()
+	}
+}
+
+// FindByIdentity returns annotations and status corresponding to identity.
+//
+// Each identity in the lineage is read under its own shard's read lock, so the traversal never blocks writes to
+// unrelated identities; annotations stored concurrently with the traversal may or may not be included.
+func (i *instance) FindByIdentity(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	return lineage.Find(id, i.fetch)
+}
+
+// FindDescendants returns the tree of identities derived from identity (with their annotations) and status.
+func (i *instance) FindDescendants(id identity.Contract) (*lineage.Node, status.Value) {
+	return lineage.Descendants(id, i.fetch, i.children)
+}
+
+// Query returns the page of annotations satisfying criteria and status.
+func (i *instance) Query(criteria query.Criteria) (*query.Page, status.Value) {
+	var candidates []*annotation.Instance
+	switch {
+	case criteria.Identity == nil:
+		candidates = make([]*annotation.Instance, 0)
+		for s := range i.shards {
+			i.shards[s].m.RLock()
+			for _, m := range i.shards[s].data {
+				candidates = append(candidates, m...)
+			}
+			i.shards[s].m.RUnlock()
+		}
+	case criteria.Lineage:
+		var result status.Value
+		if candidates, result = lineage.Find(criteria.Identity, i.fetch); result != status.Success {
+			return nil, result
+		}
+	default:
+		var result status.Value
+		if candidates, result = i.fetch(criteria.Identity); result != status.Success {
+			return nil, result
+		}
+	}
+	return query.Apply(criteria, candidates)
+}
+
+// Create stores annotations corresponding to a new identity and returns status.
+func (i *instance) Create(id identity.Contract, m *annotation.Instance) status.Value {
+	idAsString := id.Printable()
+	s := i.shard(idAsString)
+	s.m.Lock()
+	if _, exists := s.data[idAsString]; exists {
+		s.m.Unlock()
+		return status.Exists
+	}
+	s.data[idAsString] = []*annotation.Instance{m}
+	s.m.Unlock()
+
+	i.derive(id, m)
+	return status.Success
+}
+
+// Append stores annotations corresponding to identity and returns status.
+func (i *instance) Append(id identity.Contract, m *annotation.Instance) status.Value {
+	idAsString := id.Printable()
+	s := i.shard(idAsString)
+	s.m.Lock()
+	if _, exists := s.data[idAsString]; !exists {
+		s.m.Unlock()
+		return status.NotFound
+	}
+	s.data[idAsString] = append(s.data[idAsString], m)
+	s.m.Unlock()
+
+	i.derive(id, m)
+	return status.Success
+}
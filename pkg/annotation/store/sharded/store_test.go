@@ -0,0 +1,424 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package sharded
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSUT returns a new system under test.
+func newSUT() *instance {
+	return New(4)
+}
+
+// newIdentity returns a new random identity.
+func newIdentity() identity.Contract {
+	return identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+}
+
+// newAnnotation returns a new annotation for the given identities.
+func newAnnotation(id, previous identity.Contract) *annotation.Instance {
+	return annotation.New(test.FactoryRandomFixedLengthAlphanumericString(26), id, previous, metadataStub.NewNullObject())
+}
+
+// TestNew tests New.
+func TestNew(t *testing.T) {
+	assert.Len(t, New(0).shards, DefaultShards)
+	assert.Len(t, New(-1).shards, DefaultShards)
+	assert.Len(t, New(3).shards, 3)
+}
+
+// TestStore_FindByIdentity tests store.FindByIdentity.
+func TestStore_FindByIdentity(t *testing.T) {
+	type testCase struct {
+		name                string
+		identity            identity.Contract
+		preCondition        func(t *testing.T, sut *instance)
+		expectedAnnotations []*annotation.Instance
+		expectedStatus      status.Value
+	}
+
+	cases := []testCase{
+		{
+			name:                "does not exist",
+			identity:            newIdentity(),
+			preCondition:        func(_ *testing.T, _ *instance) {},
+			expectedAnnotations: []*annotation.Instance{},
+			expectedStatus:      status.NotFound,
+		},
+		func() testCase {
+			id := newIdentity()
+			m := newAnnotation(id, nil)
+			return testCase{
+				name:     "exists",
+				identity: id,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id, m))
+				},
+				expectedAnnotations: []*annotation.Instance{m},
+				expectedStatus:      status.Success,
+			}
+		}(),
+		func() testCase {
+			id1, id2 := newIdentity(), newIdentity()
+			m1 := newAnnotation(id1, nil)
+			m2 := newAnnotation(id2, id1)
+			m3 := newAnnotation(id1, id2)
+			return testCase{
+				name:     "reverted identity",
+				identity: id1,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id1, m1))
+					assert.Equal(t, status.Success, sut.Create(id2, m2))
+					assert.Equal(t, status.Success, sut.Append(id1, m3))
+				},
+				expectedAnnotations: []*annotation.Instance{m1, m3, m2},
+				expectedStatus:      status.Success,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := newSUT()
+				cases[i].preCondition(t, sut)
+
+				m, result := sut.FindByIdentity(cases[i].identity)
+
+				assert.Equal(t, cases[i].expectedStatus, result)
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expectedAnnotations), testInternal.Marshal(t, m))
+			},
+		)
+	}
+}
+
+// TestStore_FindDescendants tests store.FindDescendants.
+func TestStore_FindDescendants(t *testing.T) {
+	type testCase struct {
+		name           string
+		identity       identity.Contract
+		preCondition   func(t *testing.T, sut *instance)
+		expectedNode   *lineage.Node
+		expectedStatus status.Value
+	}
+
+	cases := []testCase{
+		{
+			name:           "does not exist",
+			identity:       newIdentity(),
+			preCondition:   func(_ *testing.T, _ *instance) {},
+			expectedNode:   nil,
+			expectedStatus: status.NotFound,
+		},
+		func() testCase {
+			id1, id2, id3 := newIdentity(), newIdentity(), newIdentity()
+			m1 := newAnnotation(id1, nil)
+			m2 := newAnnotation(id2, id1)
+			m3 := newAnnotation(id2, id1)
+			m4 := newAnnotation(id3, id2)
+			return testCase{
+				name:     "derived tree",
+				identity: id1,
+				preCondition: func(t *testing.T, sut *instance) {
+					assert.Equal(t, status.Success, sut.Create(id1, m1))
+					assert.Equal(t, status.Success, sut.Create(id2, m2))
+					assert.Equal(t, status.Success, sut.Append(id2, m3))
+					assert.Equal(t, status.Success, sut.Create(id3, m4))
+				},
+				expectedNode: &lineage.Node{
+					Identity:    id1,
+					Annotations: []*annotation.Instance{m1},
+					Children: []*lineage.Node{
+						{
+							Identity:    id2,
+							Annotations: []*annotation.Instance{m2, m3},
+							Children: []*lineage.Node{
+								{
+									Identity:    id3,
+									Annotations: []*annotation.Instance{m4},
+									Children:    []*lineage.Node{},
+								},
+							},
+						},
+					},
+				},
+				expectedStatus: status.Success,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := newSUT()
+				cases[i].preCondition(t, sut)
+
+				node, result := sut.FindDescendants(cases[i].identity)
+
+				assert.Equal(t, testInternal.Marshal(t, cases[i].expectedNode), testInternal.Marshal(t, node))
+				assert.Equal(t, cases[i].expectedStatus, result)
+			},
+		)
+	}
+}
+
+// TestStore_Query tests store.Query.
+func TestStore_Query(t *testing.T) {
+	id1, id2, id3 := newIdentity(), newIdentity(), newIdentity()
+	m1 := newAnnotation(id1, nil)
+	m2 := newAnnotation(id2, id1)
+	m3 := newAnnotation(id3, nil)
+	sut := newSUT()
+	reference := memory.New()
+	for _, s := range []store.Contract{sut, reference} {
+		require.Equal(t, status.Success, s.Create(id1, m1))
+		require.Equal(t, status.Success, s.Create(id2, m2))
+		require.Equal(t, status.Success, s.Create(id3, m3))
+	}
+
+	type testCase struct {
+		name     string
+		criteria query.Criteria
+	}
+
+	cases := []testCase{
+		{name: "all identities", criteria: query.Criteria{}},
+		{name: "identity", criteria: query.Criteria{Identity: id2}},
+		{name: "lineage", criteria: query.Criteria{Identity: id2, Lineage: true, Order: query.Descending}},
+		{name: "paginated", criteria: query.Criteria{Limit: 1}},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				page, result := sut.Query(cases[i].criteria)
+
+				assert.Equal(t, status.Success, result)
+				expected, _ := reference.Query(cases[i].criteria)
+				assert.Equal(t, testInternal.Marshal(t, expected), testInternal.Marshal(t, page))
+			},
+		)
+	}
+
+	t.Run(
+		"identity does not exist",
+		func(t *testing.T) {
+			page, result := sut.Query(query.Criteria{Identity: newIdentity()})
+
+			assert.Nil(t, page)
+			assert.Equal(t, status.NotFound, result)
+		},
+	)
+}
+
+// TestStore_Write tests store.Create and store.Append statuses.
+func TestStore_Write(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "create twice",
+			test: func(t *testing.T) {
+				id := newIdentity()
+				m1, m2 := newAnnotation(id, nil), newAnnotation(id, nil)
+				sut := newSUT()
+
+				assert.Equal(t, status.Success, sut.Create(id, m1))
+				assert.Equal(t, status.Exists, sut.Create(id, m2))
+
+				m, _ := sut.FindByIdentity(id)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m1}), testInternal.Marshal(t, m))
+			},
+		},
+		{
+			name: "append to missing identity",
+			test: func(t *testing.T) {
+				id := newIdentity()
+				sut := newSUT()
+
+				assert.Equal(t, status.NotFound, sut.Append(id, newAnnotation(id, nil)))
+			},
+		},
+		{
+			name: "found annotations are not aliased by later appends",
+			test: func(t *testing.T) {
+				id := newIdentity()
+				m1, m2, m3 := newAnnotation(id, nil), newAnnotation(id, nil), newAnnotation(id, nil)
+				sut := newSUT()
+				require.Equal(t, status.Success, sut.Create(id, m1))
+				require.Equal(t, status.Success, sut.Append(id, m2))
+				node, _ := sut.FindDescendants(id)
+
+				node.Annotations = append(node.Annotations, newAnnotation(id, nil))
+				require.Equal(t, status.Success, sut.Append(id, m3))
+
+				m, _ := sut.FindByIdentity(id)
+				assert.Equal(t, testInternal.Marshal(t, []*annotation.Instance{m1, m2, m3}), testInternal.Marshal(t, m))
+			},
+		},
+		{
+			name: "concurrent writes and reads",
+			test: func(t *testing.T) {
+				const writers, appends = 8, 50
+				sut := newSUT()
+				root := newIdentity()
+				require.Equal(t, status.Success, sut.Create(root, newAnnotation(root, nil)))
+				ids := make([]identity.Contract, writers)
+				annotations := make([][]*annotation.Instance, writers)
+				for w := range ids {
+					ids[w] = newIdentity()
+					annotations[w] = []*annotation.Instance{newAnnotation(ids[w], root)}
+					for a := 0; a < appends; a++ {
+						annotations[w] = append(annotations[w], newAnnotation(ids[w], nil))
+					}
+				}
+
+				var wg sync.WaitGroup
+				for w := range ids {
+					wg.Add(1)
+					go func(id identity.Contract, m []*annotation.Instance) {
+						defer wg.Done()
+						assert.Equal(t, status.Success, sut.Create(id, m[0]))
+						for a := 1; a < len(m); a++ {
+							assert.Equal(t, status.Success, sut.Append(id, m[a]))
+							_, result := sut.FindByIdentity(id)
+							assert.Equal(t, status.Success, result)
+						}
+					}(ids[w], annotations[w])
+				}
+				wg.Wait()
+
+				node, result := sut.FindDescendants(root)
+				assert.Equal(t, status.Success, result)
+				assert.Len(t, node.Children, writers)
+				for c := range node.Children {
+					assert.Len(t, node.Children[c].Annotations, appends+1)
+				}
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// benchmarkMetadata is the metadata of every benchmark annotation.
+var benchmarkMetadata = metadataStub.New("benchmark", nil)
+
+// newBenchmarkAnnotation returns a new annotation for the given identities without using shared random sources.
+func newBenchmarkAnnotation(sequence int64, id, previous identity.Contract) *annotation.Instance {
+	return annotation.New(fmt.Sprintf("%026d", sequence), id, previous, benchmarkMetadata)
+}
+
+// benchmarkStores returns the store implementations compared by benchmarks.
+func benchmarkStores() map[string]func() store.Contract {
+	return map[string]func() store.Contract{
+		"memory":  func() store.Contract { return memory.New() },
+		"sharded": func() store.Contract { return New(DefaultShards) },
+	}
+}
+
+// populate stores count identities, each derived from the previous one, and returns them.
+func populate(b *testing.B, s store.Contract, count int) []identity.Contract {
+	ids := make([]identity.Contract, count)
+	for i := range ids {
+		ids[i] = identityHash.New([]byte(fmt.Sprintf("%032d", i)))
+		var previous identity.Contract
+		if i%10 != 0 {
+			previous = ids[i-1]
+		}
+		if s.Create(ids[i], newBenchmarkAnnotation(int64(i), ids[i], previous)) != status.Success {
+			b.Fatal("unable to populate store")
+		}
+	}
+	return ids
+}
+
+// BenchmarkStore_Create compares concurrent creation of new identities.
+func BenchmarkStore_Create(b *testing.B) {
+	for name, factory := range benchmarkStores() {
+		b.Run(
+			name,
+			func(b *testing.B) {
+				s := factory()
+				var sequence int64
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						n := atomic.AddInt64(&sequence, 1)
+						id := identityHash.New([]byte(fmt.Sprintf("%032d", n)))
+						if s.Create(id, newBenchmarkAnnotation(n, id, nil)) != status.Success {
+							b.Error("unable to create")
+						}
+					}
+				})
+			},
+		)
+	}
+}
+
+// BenchmarkStore_AppendAndFind compares concurrent appends interleaved with lineage reads of unrelated identities.
+func BenchmarkStore_AppendAndFind(b *testing.B) {
+	for name, factory := range benchmarkStores() {
+		b.Run(
+			name,
+			func(b *testing.B) {
+				s := factory()
+				ids := populate(b, s, 1000)
+				sequence := int64(len(ids))
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					r := rand.New(rand.NewSource(atomic.AddInt64(&sequence, 1)))
+					for pb.Next() {
+						id := ids[r.Intn(len(ids))]
+						if s.Append(id, newBenchmarkAnnotation(atomic.AddInt64(&sequence, 1), id, nil)) != status.Success {
+							b.Error("unable to append")
+						}
+						if _, result := s.FindByIdentity(ids[r.Intn(len(ids))]); result != status.Success {
+							b.Error("unable to find")
+						}
+					}
+				})
+			},
+		)
+	}
+}
@@ -0,0 +1,40 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package watch
+
+import (
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// Criteria defines the conditions an annotation must satisfy to be delivered to a subscription.
+type Criteria struct {
+	// Identity restricts delivery to annotations stored against identity (nil matches all identities).
+	Identity identity.Contract
+
+	// Kinds restricts delivery to annotations with one of the given metadata kinds (empty matches all kinds).
+	Kinds []string
+
+	// Cursor is the unique value of the last annotation a previous subscription delivered; annotations stored after
+	// it are delivered before newly stored annotations (empty delivers only newly stored annotations).
+	Cursor string
+}
+
+// Contract defines the optional store change notification abstraction.
+type Contract interface {
+	// Watch returns a subscription delivering annotations satisfying criteria as they are stored and status;
+	// status is NotFound if criteria's cursor is not known to the store.
+	Watch(criteria Criteria) (*Subscription, status.Value)
+}
@@ -0,0 +1,177 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// watch implements an optional store change notification capability and helpers shared by its implementations.
+package watch
+
+import (
+	"sync"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+)
+
+// Entry is an annotation and the identity it was stored against.
+type Entry struct {
+	Identity   identity.Contract
+	Annotation *annotation.Instance
+}
+
+// Match returns whether an annotation stored against identity satisfies criteria's identity and kind conditions.
+func Match(criteria Criteria, id identity.Contract, m *annotation.Instance) bool {
+	if criteria.Identity != nil && criteria.Identity.Printable() != id.Printable() {
+		return false
+	}
+	if len(criteria.Kinds) == 0 {
+		return true
+	}
+	for i := range criteria.Kinds {
+		if criteria.Kinds[i] == m.MetadataKind {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription delivers annotations in the order they were stored.
+//
+// Delivery never blocks the store: annotations are queued until the subscriber receives them.
+type Subscription struct {
+	m        sync.Mutex
+	criteria Criteria
+	pending  []*annotation.Instance
+	signal   chan struct{}
+	done     chan struct{}
+	out      chan *annotation.Instance
+	once     sync.Once
+	feed     *Feed
+}
+
+// newSubscription returns a started subscription that first delivers backlog.
+func newSubscription(feed *Feed, criteria Criteria, backlog []*annotation.Instance) *Subscription {
+	s := &Subscription{
+		criteria: criteria,
+		pending:  backlog,
+		signal:   make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		out:      make(chan *annotation.Instance),
+		feed:     feed,
+	}
+	go s.run()
+	return s
+}
+
+// run delivers queued annotations until the subscription is closed.
+func (s *Subscription) run() {
+	defer close(s.out)
+	for {
+		s.m.Lock()
+		if len(s.pending) == 0 {
+			s.m.Unlock()
+			select {
+			case <-s.signal:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		next := s.pending[0]
+		s.pending[0] = nil
+		s.pending = s.pending[1:]
+		s.m.Unlock()
+
+		select {
+		case s.out <- next:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// push queues an annotation for delivery.
+func (s *Subscription) push(m *annotation.Instance) {
+	s.m.Lock()
+	s.pending = append(s.pending, m)
+	s.m.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Annotations returns the channel annotations are delivered on; it is closed when the subscription is closed.
+func (s *Subscription) Annotations() <-chan *annotation.Instance {
+	return s.out
+}
+
+// Close stops delivery and releases the subscription; undelivered annotations are discarded.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.feed.remove(s)
+		close(s.done)
+	})
+}
+
+// Feed tracks a store's subscriptions and delivers stored annotations to them.
+type Feed struct {
+	m             sync.Mutex
+	subscriptions map[*Subscription]struct{}
+}
+
+// NewFeed is a factory function that returns an initialized Feed.
+func NewFeed() *Feed {
+	return &Feed{
+		m:             sync.Mutex{},
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe returns a new subscription for criteria that first delivers the backlog entries satisfying it.
+//
+// Callers must serialize Subscribe with Publish (typically by holding the store's lock) so that an annotation is
+// neither missed nor delivered twice between the backlog and subsequent publications.
+func (f *Feed) Subscribe(criteria Criteria, backlog []Entry) *Subscription {
+	pending := make([]*annotation.Instance, 0)
+	for i := range backlog {
+		if Match(criteria, backlog[i].Identity, backlog[i].Annotation) {
+			pending = append(pending, backlog[i].Annotation)
+		}
+	}
+
+	s := newSubscription(f, criteria, pending)
+	f.m.Lock()
+	f.subscriptions[s] = struct{}{}
+	f.m.Unlock()
+	return s
+}
+
+// Publish delivers an annotation stored against identity to each subscription whose criteria it satisfies.
+func (f *Feed) Publish(id identity.Contract, m *annotation.Instance) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	for s := range f.subscriptions {
+		if Match(s.criteria, id, m) {
+			s.push(m)
+		}
+	}
+}
+
+// remove stops delivering annotations to a subscription.
+func (f *Feed) remove(s *Subscription) {
+	f.m.Lock()
+	delete(f.subscriptions, s)
+	f.m.Unlock()
+}
@@ -0,0 +1,186 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package watch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timeout is the longest a test waits for a delivery.
+const timeout = time.Second
+
+// newIdentity returns a new random identity.
+func newIdentity() identity.Contract {
+	return identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+}
+
+// newAnnotation returns a new pki annotation for identity.
+func newAnnotation(id identity.Contract) *annotation.Instance {
+	return annotation.New(
+		test.FactoryRandomFixedLengthAlphanumericString(26),
+		id,
+		nil,
+		metadata.New(
+			test.FactoryRandomString(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			test.FactoryRandomByteSlice(),
+			metadataStub.NewNullObject(),
+		),
+	)
+}
+
+// receive returns the unique values of the next count annotations delivered to s.
+func receive(t *testing.T, s *Subscription, count int) []string {
+	result := make([]string, 0, count)
+	for len(result) < count {
+		select {
+		case m, ok := <-s.Annotations():
+			require.True(t, ok)
+			result = append(result, m.Unique)
+		case <-time.After(timeout):
+			require.FailNow(t, "timed out waiting for annotation")
+		}
+	}
+	return result
+}
+
+// TestMatch tests Match.
+func TestMatch(t *testing.T) {
+	id := newIdentity()
+	m := newAnnotation(id)
+
+	type testCase struct {
+		name     string
+		criteria Criteria
+		expected bool
+	}
+
+	cases := []testCase{
+		{name: "empty criteria", criteria: Criteria{}, expected: true},
+		{name: "same identity", criteria: Criteria{Identity: identityHash.New(id.Binary())}, expected: true},
+		{name: "different identity", criteria: Criteria{Identity: newIdentity()}, expected: false},
+		{name: "matching kind", criteria: Criteria{Kinds: []string{"other", metadata.Kind}}, expected: true},
+		{name: "different kind", criteria: Criteria{Kinds: []string{"other"}}, expected: false},
+		{
+			name:     "same identity different kind",
+			criteria: Criteria{Identity: id, Kinds: []string{"other"}},
+			expected: false,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				assert.Equal(t, cases[i].expected, Match(cases[i].criteria, id, m))
+			},
+		)
+	}
+}
+
+// TestFeed tests Feed.
+func TestFeed(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "backlog delivered before publications",
+			test: func(t *testing.T) {
+				sut := NewFeed()
+				id := newIdentity()
+				m1, m2, m3 := newAnnotation(id), newAnnotation(id), newAnnotation(id)
+				s := sut.Subscribe(Criteria{}, []Entry{{Identity: id, Annotation: m1}, {Identity: id, Annotation: m2}})
+				defer s.Close()
+
+				sut.Publish(id, m3)
+
+				assert.Equal(t, []string{m1.Unique, m2.Unique, m3.Unique}, receive(t, s, 3))
+			},
+		},
+		{
+			name: "filtered by criteria",
+			test: func(t *testing.T) {
+				sut := NewFeed()
+				id1, id2 := newIdentity(), newIdentity()
+				m1, m2, m3 := newAnnotation(id1), newAnnotation(id2), newAnnotation(id1)
+				s := sut.Subscribe(Criteria{Identity: id1}, []Entry{{Identity: id2, Annotation: m2}})
+				defer s.Close()
+
+				sut.Publish(id1, m1)
+				sut.Publish(id2, m2)
+				sut.Publish(id1, m3)
+
+				assert.Equal(t, []string{m1.Unique, m3.Unique}, receive(t, s, 2))
+			},
+		},
+		{
+			name: "publish does not block on slow subscriber",
+			test: func(t *testing.T) {
+				sut := NewFeed()
+				id := newIdentity()
+				s := sut.Subscribe(Criteria{}, nil)
+				defer s.Close()
+				expected := make([]string, 100)
+
+				for i := range expected {
+					m := newAnnotation(id)
+					expected[i] = m.Unique
+					sut.Publish(id, m)
+				}
+
+				assert.Equal(t, expected, receive(t, s, len(expected)))
+			},
+		},
+		{
+			name: "closed subscription",
+			test: func(t *testing.T) {
+				sut := NewFeed()
+				id := newIdentity()
+				s := sut.Subscribe(Criteria{}, nil)
+
+				s.Close()
+				s.Close()
+				sut.Publish(id, newAnnotation(id))
+
+				select {
+				case _, ok := <-s.Annotations():
+					assert.False(t, ok)
+				case <-time.After(timeout):
+					assert.Fail(t, "timed out waiting for close")
+				}
+				assert.Len(t, sut.subscriptions, 0)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
@@ -0,0 +1,56 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package transparency
+
+import (
+	"encoding/binary"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/verifier"
+)
+
+const (
+	// version is the RFC 6962 signature structure version (v1).
+	version = 0
+
+	// treeHashSignatureType is the RFC 6962 signature type for tree heads.
+	treeHashSignatureType = 1
+)
+
+// TreeHead is a signed commitment to the state of the log at a given size.
+type TreeHead struct {
+	TreeSize       uint64            `json:"treeSize"`
+	Timestamp      uint64            `json:"timestamp"`
+	RootHash       []byte            `json:"rootHash"`
+	Signature      []byte            `json:"signature"`
+	PublicKey      []byte            `json:"publicKey"`
+	SignerKind     string            `json:"signerType"`
+	SignerMetadata metadata.Contract `json:"signerMetadata"`
+}
+
+// signedData returns the RFC 6962 TreeHeadSignature structure covered by the tree head's signature.
+func (h *TreeHead) signedData() []byte {
+	b := make([]byte, 18, 18+len(h.RootHash))
+	b[0] = version
+	b[1] = treeHashSignatureType
+	binary.BigEndian.PutUint64(b[2:10], h.Timestamp)
+	binary.BigEndian.PutUint64(b[10:18], h.TreeSize)
+	return append(b, h.RootHash...)
+}
+
+// Verify returns whether the tree head's signature is valid.
+func (h *TreeHead) Verify(verifier verifier.Contract) bool {
+	return verifier != nil && verifier.VerifyData(h.signedData(), h.Signature, h.PublicKey)
+}
@@ -0,0 +1,312 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// transparency implements an RFC 6962-style Merkle tree log over every annotation written through a store.
+package transparency
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/jsonlog"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/lineage"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer"
+	"github.com/project-alvarium/go-sdk/pkg/canonical"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	opaqueIdentity "github.com/project-alvarium/go-sdk/pkg/identity/opaque"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// InclusionProof proves that an annotation is included in the tree of a given size.
+type InclusionProof struct {
+	LeafIndex uint64   `json:"leafIndex"`
+	TreeSize  uint64   `json:"treeSize"`
+	AuditPath [][]byte `json:"auditPath"`
+}
+
+// Verify returns whether the proof shows leafHash is included in the tree with the given root.
+func (p *InclusionProof) Verify(hashProvider hashprovider.Contract, leafHash, rootHash []byte) bool {
+	return VerifyInclusion(hashProvider, p.LeafIndex, p.TreeSize, leafHash, p.AuditPath, rootHash)
+}
+
+// record is the persisted form of a leaf.
+type record struct {
+	Unique       string          `json:"unique"`
+	IdentityKind string          `json:"identityType"`
+	Identity     json.RawMessage `json:"identity"`
+	Leaf         []byte          `json:"leaf"`
+}
+
+// instance is a receiver that encapsulates required dependencies.
+type instance struct {
+	m            sync.Mutex
+	store        store.Contract
+	hashProvider hashprovider.Contract
+	signer       signer.Contract
+	file         *jsonlog.Log
+	leaves       [][]byte
+	indexes      map[string]uint64
+	subtrees     subtrees
+}
+
+// New is a factory function that returns an initialized instance decorating store; the log is held in memory only
+// (see Open).
+func New(store store.Contract, hashProvider hashprovider.Contract, signer signer.Contract) *instance {
+	return &instance{
+		m:            sync.Mutex{},
+		store:        store,
+		hashProvider: hashProvider,
+		signer:       signer,
+		leaves:       make([][]byte, 0),
+		indexes:      make(map[string]uint64),
+		subtrees:     make(subtrees),
+	}
+}
+
+// Open is a factory function that returns an initialized instance decorating store whose leaves are persisted to the
+// file at path (which is created if necessary).
+//
+// The leaves are reloaded and each is verified against the annotation store holds for it; an error is returned if
+// the file cannot be read or a leaf does not match.  An annotation stored without its leaf being persisted (for
+// example, by a crash between the two writes) is not part of the log.
+func Open(
+	path string,
+	store store.Contract,
+	hashProvider hashprovider.Contract,
+	signer signer.Contract,
+	identityFactory identityFactory.Contract) (*instance, error) {
+
+	i := New(store, hashProvider, signer)
+	stored := make(map[string]map[string]*annotation.Instance)
+	file, err := jsonlog.Open(path, func(data []byte) error {
+		var r record
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		id := identityFactory.Create(r.IdentityKind, r.Identity)
+		if id == nil {
+			id = opaqueIdentity.New(r.IdentityKind, r.Identity)
+		}
+		if leaf, err := i.find(id, r.Unique, stored); err != nil || !bytes.Equal(leaf, r.Leaf) {
+			return fmt.Errorf("leaf %d does not match annotation %s", len(i.leaves), r.Unique)
+		}
+		i.add(r.Unique, r.Leaf)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	i.file = file
+	return i, nil
+}
+
+// find returns the leaf hash of the annotation with unique value stored against identity; each identity's
+// annotations are read from the store once and cached in stored (keyed by identity, then unique value).
+func (i *instance) find(
+	id identity.Contract,
+	unique string,
+	stored map[string]map[string]*annotation.Instance) ([]byte, error) {
+
+	idAsString := id.Printable()
+	byUnique, exists := stored[idAsString]
+	if !exists {
+		byUnique = make(map[string]*annotation.Instance)
+		if annotations, result := i.store.FindByIdentity(id); result == status.Success {
+			for a := range annotations {
+				byUnique[annotations[a].Unique] = annotations[a]
+			}
+		}
+		stored[idAsString] = byUnique
+	}
+
+	m, exists := byUnique[unique]
+	if !exists {
+		return nil, fmt.Errorf("annotation %s not found", unique)
+	}
+	return leaf(i.hashProvider, m)
+}
+
+// Close closes the persisted log (if any).
+func (i *instance) Close() error {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if i.file == nil {
+		return nil
+	}
+	return i.file.Close()
+}
+
+// add appends a leaf to the tree.
+func (i *instance) add(unique string, leaf []byte) {
+	i.indexes[unique] = uint64(len(i.leaves))
+	i.leaves = append(i.leaves, leaf)
+}
+
+// persist writes a leaf to the persisted log (if any).
+func (i *instance) persist(id identity.Contract, unique string, leaf []byte) error {
+	if i.file == nil {
+		return nil
+	}
+	data, err := json.Marshal(id)
+	if err != nil {
+		return err
+	}
+	return i.file.Append(record{Unique: unique, IdentityKind: id.Kind(), Identity: data, Leaf: leaf})
+}
+
+// leaf returns the leaf hash (of its canonical JSON encoding) under which an annotation is logged.
+func leaf(hashProvider hashprovider.Contract, m *annotation.Instance) ([]byte, error) {
+	data, err := canonical.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return LeafHash(hashProvider, data), nil
+}
+
+// AnnotationLeafHash returns the leaf hash (of its canonical JSON encoding) under which an annotation is logged (or
+// nil if it cannot be encoded).
+func AnnotationLeafHash(hashProvider hashprovider.Contract, m *annotation.Instance) []byte {
+	h, _ := leaf(hashProvider, m)
+	return h
+}
+
+// log delegates to write and, if successful, adds the annotation to the tree; status.Unknown is returned without
+// writing if the annotation cannot be encoded, and if its leaf cannot be persisted.
+//
+// The annotation is hashed after write returns so that the leaf covers any fields set by the stores beneath the log
+// (for example, the signature set by a signature store).
+func (i *instance) log(
+	id identity.Contract,
+	m *annotation.Instance,
+	write func(id identity.Contract, m *annotation.Instance) status.Value) status.Value {
+
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if _, exists := i.indexes[m.Unique]; exists {
+		return status.Exists
+	}
+
+	if _, err := canonical.Marshal(m); err != nil {
+		return status.Unknown
+	}
+	result := write(id, m)
+	if result != status.Success {
+		return result
+	}
+	h, err := leaf(i.hashProvider, m)
+	if err != nil {
+		return status.Unknown
+	}
+	if err := i.persist(id, m.Unique, h); err != nil {
+		return status.Unknown
+	}
+	i.add(m.Unique, h)
+	return result
+}
+
+// FindByIdentity returns annotations and status corresponding to identity.
+func (i *instance) FindByIdentity(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	return i.store.FindByIdentity(id)
+}
+
+// FindDescendants returns the tree of identities derived from identity (with their annotations) and status.
+func (i *instance) FindDescendants(id identity.Contract) (*lineage.Node, status.Value) {
+	return i.store.FindDescendants(id)
+}
+
+// Create stores annotations corresponding to a new identity and returns status.
+func (i *instance) Create(id identity.Contract, m *annotation.Instance) status.Value {
+	return i.log(id, m, i.store.Create)
+}
+
+// Append stores annotations corresponding to identity and returns status.
+func (i *instance) Append(id identity.Contract, m *annotation.Instance) status.Value {
+	return i.log(id, m, i.store.Append)
+}
+
+// TreeHead returns a signed tree head for the current state of the log and status; status.Unknown is returned if the
+// signer fails to sign it.
+//
+// The lock is held while signing so that the signer metadata recorded is the one describing this signature.
+func (i *instance) TreeHead() (*TreeHead, status.Value) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	h := &TreeHead{
+		TreeSize:  uint64(len(i.leaves)),
+		Timestamp: uint64(time.Now().UnixNano() / int64(time.Millisecond)),
+		RootHash:  i.subtrees.root(i.hashProvider, 0, i.leaves),
+	}
+	signature, err := signer.SignData(i.signer, h.signedData())
+	if err != nil {
+		return nil, status.Unknown
+	}
+	h.Signature = signature
+	h.SignerMetadata = i.signer.Metadata()
+	h.PublicKey = i.signer.PublicKey()
+	if h.SignerMetadata != nil {
+		h.SignerKind = h.SignerMetadata.Kind()
+	}
+	return h, status.Success
+}
+
+// RootHash returns the Merkle tree hash of the first treeSize annotations.
+func (i *instance) RootHash(treeSize uint64) ([]byte, status.Value) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if treeSize > uint64(len(i.leaves)) {
+		return nil, status.NotFound
+	}
+	return i.subtrees.root(i.hashProvider, 0, i.leaves[:treeSize]), status.Success
+}
+
+// InclusionProof returns the proof that the annotation identified by unique is included in the tree of treeSize.
+func (i *instance) InclusionProof(unique string, treeSize uint64) (*InclusionProof, status.Value) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	index, exists := i.indexes[unique]
+	if !exists || index >= treeSize || treeSize > uint64(len(i.leaves)) {
+		return nil, status.NotFound
+	}
+	return &InclusionProof{
+		LeafIndex: index,
+		TreeSize:  treeSize,
+		AuditPath: i.subtrees.path(i.hashProvider, index, 0, i.leaves[:treeSize]),
+	}, status.Success
+}
+
+// ConsistencyProof returns the proof that the tree of size second extends the tree of size first.
+func (i *instance) ConsistencyProof(first, second uint64) ([][]byte, status.Value) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	if first > second || second > uint64(len(i.leaves)) {
+		return nil, status.NotFound
+	}
+	if first == 0 {
+		return [][]byte{}, status.Success
+	}
+	return i.subtrees.subproof(i.hashProvider, first, 0, i.leaves[:second], true), status.Success
+}
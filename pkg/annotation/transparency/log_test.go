@@ -0,0 +1,443 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package transparency
+
+import (
+	"crypto"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	metadataStubFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/signature"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/factory/verifier"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/fail"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	identityFactory "github.com/project-alvarium/go-sdk/pkg/identity/factory"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metadataValue is the metadata embedded in every test annotation.
+var metadataValue = metadataStub.NewNullObject()
+
+// newSUT returns a new system under test.
+func newSUT() *instance {
+	h := sha256.New()
+	return New(
+		memory.New(),
+		h,
+		signpkcs1v15.New(crypto.SHA256, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, h),
+	)
+}
+
+// populate stores count annotations (each for a new identity) and returns them.
+func populate(t *testing.T, sut *instance, count int) []*annotation.Instance {
+	annotations := make([]*annotation.Instance, count)
+	for i := range annotations {
+		id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+		annotations[i] = annotation.New(test.FactoryRandomFixedLengthAlphanumericString(26), id, nil, metadataValue)
+		require.Equal(t, status.Success, sut.Create(id, annotations[i]))
+	}
+	return annotations
+}
+
+// treeHead returns the log's signed tree head.
+func treeHead(t *testing.T, sut *instance) *TreeHead {
+	h, result := sut.TreeHead()
+	require.Equal(t, status.Success, result)
+	return h
+}
+
+// newPath returns the path of a persisted log in a new temporary directory and a function that removes it.
+func newPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "transparency")
+	require.NoError(t, err)
+	return filepath.Join(dir, "leaves.log"), func() { _ = os.RemoveAll(dir) }
+}
+
+// TestInstance_Create tests instance.Create.
+func TestInstance_Create(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "logged",
+			test: func(t *testing.T) {
+				sut := newSUT()
+
+				annotations := populate(t, sut, 3)
+
+				assert.Equal(t, uint64(3), treeHead(t, sut).TreeSize)
+				found, result := sut.FindByIdentity(annotations[1].CurrentIdentity)
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, testInternal.Marshal(t, annotations[1:2]), testInternal.Marshal(t, found))
+			},
+		},
+		{
+			name: "underlying failure not logged",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				annotations := populate(t, sut, 1)
+				id := annotations[0].CurrentIdentity
+
+				result := sut.Create(id, annotation.New(test.FactoryRandomFixedLengthAlphanumericString(26), id, nil, metadataValue))
+
+				assert.Equal(t, status.Exists, result)
+				assert.Equal(t, uint64(1), treeHead(t, sut).TreeSize)
+			},
+		},
+		{
+			name: "duplicate unique rejected",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				annotations := populate(t, sut, 1)
+
+				result := sut.Append(annotations[0].CurrentIdentity, annotations[0])
+
+				assert.Equal(t, status.Exists, result)
+				assert.Equal(t, uint64(1), treeHead(t, sut).TreeSize)
+			},
+		},
+		{
+			name: "unhashable annotation not written",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				id := identityHash.New(test.FactoryRandomFixedLengthAlphanumericByteSlice(32))
+				m := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.New("kind", make(chan int)))
+
+				result := sut.Create(id, m)
+
+				assert.Equal(t, status.Unknown, result)
+				assert.Equal(t, uint64(0), treeHead(t, sut).TreeSize)
+				_, result = sut.FindByIdentity(id)
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_TreeHead tests instance.TreeHead.
+func TestInstance_TreeHead(t *testing.T) {
+	type testCase struct {
+		name     string
+		tamper   func(h *TreeHead)
+		expected bool
+	}
+
+	cases := []testCase{
+		{
+			name:     "valid signature",
+			tamper:   func(_ *TreeHead) {},
+			expected: true,
+		},
+		{
+			name:     "altered root",
+			tamper:   func(h *TreeHead) { h.RootHash[0] ^= 0xff },
+			expected: false,
+		},
+		{
+			name:     "altered size",
+			tamper:   func(h *TreeHead) { h.TreeSize++ },
+			expected: false,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := newSUT()
+				populate(t, sut, 5)
+				h := treeHead(t, sut)
+				cases[i].tamper(h)
+
+				result := h.Verify(verifier.New().Create(h.SignerMetadata))
+
+				assert.Equal(t, cases[i].expected, result)
+			},
+		)
+	}
+}
+
+// TestInstance_TreeHeadSignerFailure tests that instance.TreeHead reports a signer's failure.
+func TestInstance_TreeHeadSignerFailure(t *testing.T) {
+	sut := New(memory.New(), sha256.New(), fail.New())
+	populate(t, sut, 2)
+
+	h, result := sut.TreeHead()
+
+	assert.Nil(t, h)
+	assert.Equal(t, status.Unknown, result)
+}
+
+// TestInstance_TreeHeadConcurrent tests that concurrent calls to instance.TreeHead each return a tree head signed
+// with the metadata describing its signature.
+func TestInstance_TreeHeadConcurrent(t *testing.T) {
+	const callers = 8
+
+	sut := newSUT()
+	populate(t, sut, 2)
+
+	var wg sync.WaitGroup
+	heads := make(chan *TreeHead, callers)
+	for c := 0; c < callers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h, _ := sut.TreeHead()
+			heads <- h
+		}()
+	}
+	wg.Wait()
+	close(heads)
+
+	for h := range heads {
+		require.NotNil(t, h)
+		assert.True(t, h.Verify(verifier.New().Create(h.SignerMetadata)))
+	}
+}
+
+// TestInstance_Signed tests a log decorating a signature store, which signs annotations as they are written.
+func TestInstance_Signed(t *testing.T) {
+	h := sha256.New()
+	s := signpkcs1v15.New(crypto.SHA256, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, h)
+	sut := New(signature.New(memory.New(), s), h, s)
+	annotations := populate(t, sut, 3)
+	head := treeHead(t, sut)
+
+	for n := range annotations {
+		require.NotNil(t, annotations[n].Signature)
+		proof, result := sut.InclusionProof(annotations[n].Unique, head.TreeSize)
+		require.Equal(t, status.Success, result)
+		assert.True(t, proof.Verify(h, AnnotationLeafHash(h, annotations[n]), head.RootHash))
+	}
+}
+
+// TestOpen tests Open.
+func TestOpen(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	h := sha256.New()
+	s := signpkcs1v15.New(crypto.SHA256, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, h)
+	cases := []testCase{
+		{
+			name: "leaves reloaded",
+			test: func(t *testing.T) {
+				path, cleanup := newPath(t)
+				defer cleanup()
+				underlying := memory.New()
+				sut, err := Open(path, underlying, h, s, identityFactory.New())
+				require.NoError(t, err)
+				annotations := populate(t, sut, 5)
+				first := treeHead(t, sut)
+				require.NoError(t, sut.Close())
+
+				reopened, err := Open(path, underlying, h, s, identityFactory.New())
+
+				require.NoError(t, err)
+				defer func() { _ = reopened.Close() }()
+				rootHash, result := reopened.RootHash(first.TreeSize)
+				assert.Equal(t, status.Success, result)
+				assert.Equal(t, first.RootHash, rootHash)
+				assert.Equal(t, status.Exists, reopened.Append(annotations[0].CurrentIdentity, annotations[0]))
+				populate(t, reopened, 2)
+				second := treeHead(t, reopened)
+				proof, result := reopened.ConsistencyProof(first.TreeSize, second.TreeSize)
+				assert.Equal(t, status.Success, result)
+				assert.True(t, VerifyConsistency(h, first.TreeSize, second.TreeSize, first.RootHash, second.RootHash, proof))
+			},
+		},
+		{
+			name: "store does not match leaves",
+			test: func(t *testing.T) {
+				path, cleanup := newPath(t)
+				defer cleanup()
+				sut, err := Open(path, memory.New(), h, s, identityFactory.New())
+				require.NoError(t, err)
+				populate(t, sut, 2)
+				require.NoError(t, sut.Close())
+
+				reopened, err := Open(path, memory.New(), h, s, identityFactory.New())
+
+				assert.Nil(t, reopened)
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_InclusionProof tests instance.InclusionProof.
+func TestInstance_InclusionProof(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "published bundle verifies against tree head",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				annotations := populate(t, sut, 7)
+				h := treeHead(t, sut)
+				bundle, err := json.Marshal(annotations[2:4])
+				require.NoError(t, err)
+
+				var raw []json.RawMessage
+				require.NoError(t, json.Unmarshal(bundle, &raw))
+				for r := range raw {
+					var a annotation.Instance
+					a.SetIdentityFactory(identityFactory.New())
+					a.SetMetadataFactory(
+						metadataFactory.New([]metadataFactory.Contract{metadataStubFactory.New(metadataValue)}),
+					)
+					require.NoError(t, json.Unmarshal(raw[r], &a))
+
+					proof, result := sut.InclusionProof(a.Unique, h.TreeSize)
+
+					assert.Equal(t, status.Success, result)
+					assert.True(t, proof.Verify(sha256.New(), AnnotationLeafHash(sha256.New(), &a), h.RootHash))
+				}
+			},
+		},
+		{
+			name: "earlier tree size",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				annotations := populate(t, sut, 7)
+				rootHash, result := sut.RootHash(4)
+				assert.Equal(t, status.Success, result)
+
+				proof, result := sut.InclusionProof(annotations[3].Unique, 4)
+
+				assert.Equal(t, status.Success, result)
+				assert.True(t, proof.Verify(sha256.New(), AnnotationLeafHash(sha256.New(), annotations[3]), rootHash))
+			},
+		},
+		{
+			name: "altered annotation fails",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				annotations := populate(t, sut, 3)
+				h := treeHead(t, sut)
+				proof, _ := sut.InclusionProof(annotations[1].Unique, h.TreeSize)
+				annotations[1].Created = test.FactoryRandomString()
+
+				result := proof.Verify(sha256.New(), AnnotationLeafHash(sha256.New(), annotations[1]), h.RootHash)
+
+				assert.False(t, result)
+			},
+		},
+		{
+			name: "not included in tree size",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				annotations := populate(t, sut, 3)
+
+				proof, result := sut.InclusionProof(annotations[2].Unique, 2)
+
+				assert.Nil(t, proof)
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+		{
+			name: "unknown unique",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				populate(t, sut, 3)
+
+				_, result := sut.InclusionProof(test.FactoryRandomFixedLengthAlphanumericString(26), 3)
+
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_ConsistencyProof tests instance.ConsistencyProof.
+func TestInstance_ConsistencyProof(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "log grows consistently",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				populate(t, sut, 3)
+				first := treeHead(t, sut)
+				populate(t, sut, 6)
+				second := treeHead(t, sut)
+
+				proof, result := sut.ConsistencyProof(first.TreeSize, second.TreeSize)
+
+				assert.Equal(t, status.Success, result)
+				assert.True(
+					t,
+					VerifyConsistency(sha256.New(), first.TreeSize, second.TreeSize, first.RootHash, second.RootHash, proof),
+				)
+			},
+		},
+		{
+			name: "beyond tree size",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				populate(t, sut, 3)
+
+				_, result := sut.ConsistencyProof(1, 4)
+
+				assert.Equal(t, status.NotFound, result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
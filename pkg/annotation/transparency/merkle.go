@@ -0,0 +1,210 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package transparency
+
+import (
+	"bytes"
+
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider"
+)
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// LeafHash returns the RFC 6962 hash of a leaf's data.
+func LeafHash(hashProvider hashprovider.Contract, data []byte) []byte {
+	return hashProvider.Derive(append([]byte{leafPrefix}, data...))
+}
+
+// nodeHash returns the RFC 6962 hash of an interior node.
+func nodeHash(hashProvider hashprovider.Contract, left, right []byte) []byte {
+	b := make([]byte, 0, 1+len(left)+len(right))
+	b = append(b, nodePrefix)
+	b = append(b, left...)
+	return hashProvider.Derive(append(b, right...))
+}
+
+// split returns the largest power of two smaller than n.
+func split(n uint64) uint64 {
+	k := uint64(1)
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// subtrees caches the hashes of complete subtrees (those whose size is a power of two), keyed by their first leaf
+// index and size; a complete subtree never changes as leaves are appended, so its hash is computed once.  A nil
+// cache computes every hash.
+type subtrees map[[2]uint64][]byte
+
+// root returns the Merkle tree hash of the given leaf hashes (the first at offset).
+func (c subtrees) root(hashProvider hashprovider.Contract, offset uint64, leaves [][]byte) []byte {
+	n := uint64(len(leaves))
+	switch n {
+	case 0:
+		return hashProvider.Derive(nil)
+	case 1:
+		return leaves[0]
+	}
+
+	key := [2]uint64{offset, n}
+	complete := c != nil && n&(n-1) == 0
+	if complete {
+		if h, exists := c[key]; exists {
+			return h
+		}
+	}
+	k := split(n)
+	h := nodeHash(hashProvider, c.root(hashProvider, offset, leaves[:k]), c.root(hashProvider, offset+k, leaves[k:]))
+	if complete {
+		c[key] = h
+	}
+	return h
+}
+
+// path returns the audit path for leaf m within the given leaf hashes (the first at offset).
+func (c subtrees) path(hashProvider hashprovider.Contract, m, offset uint64, leaves [][]byte) [][]byte {
+	n := uint64(len(leaves))
+	if n <= 1 {
+		return [][]byte{}
+	}
+
+	k := split(n)
+	if m < k {
+		return append(c.path(hashProvider, m, offset, leaves[:k]), c.root(hashProvider, offset+k, leaves[k:]))
+	}
+	return append(c.path(hashProvider, m-k, offset+k, leaves[k:]), c.root(hashProvider, offset, leaves[:k]))
+}
+
+// subproof returns the consistency proof between the first m leaves and the given leaf hashes (the first at offset).
+func (c subtrees) subproof(
+	hashProvider hashprovider.Contract,
+	m, offset uint64,
+	leaves [][]byte,
+	complete bool) [][]byte {
+
+	n := uint64(len(leaves))
+	if m == n {
+		if complete {
+			return [][]byte{}
+		}
+		return [][]byte{c.root(hashProvider, offset, leaves)}
+	}
+
+	k := split(n)
+	if m <= k {
+		return append(c.subproof(hashProvider, m, offset, leaves[:k], complete), c.root(hashProvider, offset+k, leaves[k:]))
+	}
+	return append(c.subproof(hashProvider, m-k, offset+k, leaves[k:], false), c.root(hashProvider, offset, leaves[:k]))
+}
+
+// root returns the Merkle tree hash of the given leaf hashes.
+func root(hashProvider hashprovider.Contract, leaves [][]byte) []byte {
+	return subtrees(nil).root(hashProvider, 0, leaves)
+}
+
+// path returns the audit path for leaf m within the given leaf hashes.
+func path(hashProvider hashprovider.Contract, m uint64, leaves [][]byte) [][]byte {
+	return subtrees(nil).path(hashProvider, m, 0, leaves)
+}
+
+// subproof returns the consistency proof between the first m leaves and the given leaf hashes.
+func subproof(hashProvider hashprovider.Contract, m uint64, leaves [][]byte, complete bool) [][]byte {
+	return subtrees(nil).subproof(hashProvider, m, 0, leaves, complete)
+}
+
+// VerifyInclusion returns whether leafHash is included at index in the tree of treeSize with the given root.
+func VerifyInclusion(
+	hashProvider hashprovider.Contract,
+	index, treeSize uint64,
+	leafHash []byte,
+	auditPath [][]byte,
+	rootHash []byte) bool {
+
+	if index >= treeSize {
+		return false
+	}
+
+	fn, sn, r := index, treeSize-1, leafHash
+	for _, p := range auditPath {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(hashProvider, p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(hashProvider, r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(r, rootHash)
+}
+
+// VerifyConsistency returns whether the tree of size second (with secondRoot) extends the tree of size first.
+func VerifyConsistency(
+	hashProvider hashprovider.Contract,
+	first, second uint64,
+	firstRoot, secondRoot []byte,
+	proof [][]byte) bool {
+
+	switch {
+	case first > second:
+		return false
+	case first == second:
+		return len(proof) == 0 && bytes.Equal(firstRoot, secondRoot)
+	case first == 0:
+		return len(proof) == 0
+	case len(proof) == 0:
+		return false
+	}
+
+	if first&(first-1) == 0 {
+		proof = append([][]byte{firstRoot}, proof...)
+	}
+
+	fn, sn := first-1, second-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = nodeHash(hashProvider, c, fr)
+			sr = nodeHash(hashProvider, c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = nodeHash(hashProvider, sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(fr, firstRoot) && bytes.Equal(sr, secondRoot)
+}
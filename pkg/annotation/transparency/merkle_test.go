@@ -0,0 +1,224 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package transparency
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// vectorLeaves are the leaf inputs used by the RFC 6962 reference test vectors.
+var vectorLeaves = []string{
+	"",
+	"00",
+	"10",
+	"2021",
+	"3031",
+	"40414243",
+	"5051525354555657",
+	"606162636465666768696a6b6c6d6e6f",
+}
+
+// vectorRoots are the expected tree hashes for the first n+1 vector leaves.
+var vectorRoots = []string{
+	"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+	"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+	"aeb6bcfe274b70a14fb067a5e5578264db0fa9b51af5e0ba159158f329e06e77",
+	"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+	"4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+	"76e67dadbcdf1e10e1b74ddc608abd2f98dfb16fbce75277b5232a127f2087ef",
+	"ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c",
+	"5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+}
+
+// decode converts a hex test vector into bytes.
+func decode(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		assert.FailNow(t, "invalid test vector", s)
+	}
+	return b
+}
+
+// vectorLeafHashes returns the leaf hashes of the vector leaves.
+func vectorLeafHashes(t *testing.T) [][]byte {
+	leaves := make([][]byte, len(vectorLeaves))
+	for i := range vectorLeaves {
+		leaves[i] = LeafHash(sha256.New(), decode(t, vectorLeaves[i]))
+	}
+	return leaves
+}
+
+// TestRoot tests root.
+func TestRoot(t *testing.T) {
+	leaves := vectorLeafHashes(t)
+
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", hex.EncodeToString(root(sha256.New(), nil)))
+	for i := range vectorRoots {
+		assert.Equal(t, vectorRoots[i], hex.EncodeToString(root(sha256.New(), leaves[:i+1])))
+	}
+}
+
+// TestSubtrees tests that cached subtree hashes give the same results as computing every hash.
+func TestSubtrees(t *testing.T) {
+	leaves := vectorLeafHashes(t)
+	h := sha256.New()
+	sut := make(subtrees)
+
+	for n := uint64(1); n <= uint64(len(leaves)); n++ {
+		assert.Equal(t, vectorRoots[n-1], hex.EncodeToString(sut.root(h, 0, leaves[:n])))
+		for m := uint64(0); m < n; m++ {
+			assert.Equal(t, path(h, m, leaves[:n]), sut.path(h, m, 0, leaves[:n]))
+			assert.Equal(t, subproof(h, m+1, leaves[:n], true), sut.subproof(h, m+1, 0, leaves[:n], true))
+		}
+	}
+	assert.NotEmpty(t, sut)
+}
+
+// TestVerifyInclusion tests VerifyInclusion.
+func TestVerifyInclusion(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	leaves := vectorLeafHashes(t)
+	h := sha256.New()
+	cases := []testCase{
+		{
+			name: "every leaf of every tree size",
+			test: func(t *testing.T) {
+				for n := uint64(1); n <= uint64(len(leaves)); n++ {
+					rootHash := root(h, leaves[:n])
+					for m := uint64(0); m < n; m++ {
+						assert.True(t, VerifyInclusion(h, m, n, leaves[m], path(h, m, leaves[:n]), rootHash))
+					}
+				}
+			},
+		},
+		{
+			name: "wrong leaf",
+			test: func(t *testing.T) {
+				assert.False(t, VerifyInclusion(h, 2, 8, leaves[3], path(h, 2, leaves), root(h, leaves)))
+			},
+		},
+		{
+			name: "wrong index",
+			test: func(t *testing.T) {
+				assert.False(t, VerifyInclusion(h, 3, 8, leaves[2], path(h, 2, leaves), root(h, leaves)))
+			},
+		},
+		{
+			name: "index beyond tree size",
+			test: func(t *testing.T) {
+				assert.False(t, VerifyInclusion(h, 8, 8, leaves[2], path(h, 2, leaves), root(h, leaves)))
+			},
+		},
+		{
+			name: "truncated path",
+			test: func(t *testing.T) {
+				p := path(h, 5, leaves)
+				assert.False(t, VerifyInclusion(h, 5, 8, leaves[5], p[:len(p)-1], root(h, leaves)))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestVerifyConsistency tests VerifyConsistency.
+func TestVerifyConsistency(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	leaves := vectorLeafHashes(t)
+	h := sha256.New()
+	cases := []testCase{
+		{
+			name: "reference vector (6, 8)",
+			test: func(t *testing.T) {
+				expected := []string{
+					"0ebc5d3437fbe2db158b9f126a1d118e308181031d0a949f8dededebc558ef6a",
+					"ca854ea128ed050b41b35ffc1b87b8eb2bde461e9e3b5596ece6b9d5975a0ae0",
+					"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+				}
+
+				proof := subproof(h, 6, leaves, true)
+
+				if assert.Equal(t, len(expected), len(proof)) {
+					for i := range expected {
+						assert.Equal(t, expected[i], hex.EncodeToString(proof[i]))
+					}
+				}
+			},
+		},
+		{
+			name: "every pair of tree sizes",
+			test: func(t *testing.T) {
+				for second := uint64(1); second <= uint64(len(leaves)); second++ {
+					for first := uint64(1); first <= second; first++ {
+						assert.True(
+							t,
+							VerifyConsistency(
+								h,
+								first,
+								second,
+								root(h, leaves[:first]),
+								root(h, leaves[:second]),
+								subproof(h, first, leaves[:second], true),
+							),
+						)
+					}
+				}
+			},
+		},
+		{
+			name: "wrong first root",
+			test: func(t *testing.T) {
+				assert.False(t, VerifyConsistency(h, 3, 8, root(h, leaves[:4]), root(h, leaves), subproof(h, 3, leaves, true)))
+			},
+		},
+		{
+			name: "wrong second root",
+			test: func(t *testing.T) {
+				assert.False(t, VerifyConsistency(h, 3, 8, root(h, leaves[:3]), root(h, leaves[:7]), subproof(h, 3, leaves, true)))
+			},
+		},
+		{
+			name: "first larger than second",
+			test: func(t *testing.T) {
+				assert.False(t, VerifyConsistency(h, 8, 3, root(h, leaves), root(h, leaves[:3]), [][]byte{}))
+			},
+		},
+		{
+			name: "empty proof",
+			test: func(t *testing.T) {
+				assert.False(t, VerifyConsistency(h, 3, 8, root(h, leaves[:3]), root(h, leaves), [][]byte{}))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
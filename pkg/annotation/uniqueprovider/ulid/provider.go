@@ -15,25 +15,31 @@
 package ulid
 
 import (
-	"math/rand"
-	"time"
+	"crypto/rand"
+	"sync"
 
 	"github.com/oklog/ulid/v2"
 )
 
 // provider is a receiver that encapsulates required dependencies.
 type provider struct {
+	m sync.Mutex
 	e *ulid.MonotonicEntropy
 }
 
-// New is a factory function that returns an initialized provider.
+// New is a factory function that returns an initialized provider; entropy is read from crypto/rand so providers on
+// different nodes (or started at the same instant) do not generate the same values, which replication relies on.
 func New() *provider {
 	return &provider{
-		e: ulid.Monotonic(rand.New(rand.NewSource(time.Unix(1000000, 0).UnixNano())), 0),
+		m: sync.Mutex{},
+		e: ulid.Monotonic(rand.Reader, 0),
 	}
 }
 
-// Get returns a globally unique identifier.
+// Get returns a globally unique identifier; it is safe for concurrent use by annotators sharing the provider.
 func (p *provider) Get() string {
+	p.m.Lock()
+	defer p.m.Unlock()
+
 	return ulid.MustNew(ulid.Now(), p.e).String()
 }
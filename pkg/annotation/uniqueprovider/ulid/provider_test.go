@@ -15,6 +15,7 @@
 package ulid
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -55,6 +56,35 @@ func TestProvider_Get(t *testing.T) {
 				assert.NotEqual(t, sut.Get(), sut.Get())
 			},
 		},
+		{
+			name: "not same string across providers",
+			test: func(t *testing.T) {
+				assert.NotEqual(t, newSUT().Get(), newSUT().Get())
+			},
+		},
+		{
+			name: "not same string across goroutines",
+			test: func(t *testing.T) {
+				const count = 100
+				sut := newSUT()
+				result := make([]string, count)
+				var wg sync.WaitGroup
+				for i := 0; i < count; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						result[i] = sut.Get()
+					}(i)
+				}
+				wg.Wait()
+
+				unique := make(map[string]bool, count)
+				for i := range result {
+					unique[result[i]] = true
+				}
+				assert.Equal(t, count, len(unique))
+			},
+		},
 	}
 
 	for i := range cases {
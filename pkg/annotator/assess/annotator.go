@@ -16,16 +16,19 @@
 package assess
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor"
 	assessMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/filter"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
 	"github.com/project-alvarium/go-sdk/pkg/identityprovider"
 	"github.com/project-alvarium/go-sdk/pkg/status"
 )
@@ -38,6 +41,7 @@ type annotator struct {
 	store            store.Contract
 	assessor         assessor.Contract
 	filter           filter.Contract
+	criteria         *query.Criteria
 }
 
 // New is a factory function that returns an initialized annotator.
@@ -59,6 +63,23 @@ func New(
 	}
 }
 
+// NewWithQuery is a factory function that returns an initialized annotator which, if store implements
+// query.Contract, retrieves annotations matching criteria (for the derived identity and its lineage) rather than
+// all annotations returned by FindByIdentity.
+func NewWithQuery(
+	provenance provenance.Contract,
+	uniqueProvider uniqueprovider.Contract,
+	identityProvider identityprovider.Contract,
+	store store.Contract,
+	assessor assessor.Contract,
+	filter filter.Contract,
+	criteria query.Criteria) *annotator {
+
+	a := New(provenance, uniqueProvider, identityProvider, store, assessor, filter)
+	a.criteria = &criteria
+	return a
+}
+
 // SetUp is called once when the signer is instantiated.
 func (a *annotator) SetUp() {
 	a.assessor.SetUp()
@@ -74,33 +95,93 @@ func (a *annotator) failureFindByIdentity(result status.Value) string {
 	return fmt.Sprintf("FindByIdentity returned %d", result)
 }
 
-// assess delegates to assessor's assess method, stores resulting assessment as annotation, and returns status.
-func (a *annotator) assess(newData []byte) *status.Contract {
+// find returns annotations and status for identity, querying the store if criteria were provided and supported.
+func (a *annotator) find(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	q, ok := a.store.(query.Contract)
+	if a.criteria == nil || !ok {
+		return a.store.FindByIdentity(id)
+	}
+
+	criteria := *a.criteria
+	criteria.Identity = id
+	criteria.Lineage = true
+	criteria.Cursor = ""
+	return query.All(q, criteria)
+}
+
+// assess delegates to assessor's assess method within ctx, stores resulting assessment as annotation, and returns
+// status.
+//
+// Nothing is annotated if ctx is done before the assessment is stored (including an assessment cut short by ctx,
+// whose abandoned assessor may yet complete); the result is then cancelled.
+func (a *annotator) assess(ctx context.Context, newData []byte) *status.Contract {
+	if ctx.Err() != nil {
+		return status.New(a.provenance, status.Cancelled)
+	}
+
 	var assessResult metadata.Contract
 
 	id := a.identityProvider.Derive(newData)
-	annotations, result := a.store.FindByIdentity(id)
+	annotations, result := a.find(id)
 	switch result {
 	case status.Success:
-		assessResult = a.assessor.Assess(a.filter.Do(annotations))
+		assessResult = assessor.WithContext(a.assessor).AssessContext(ctx, a.filter.Do(annotations))
 	default:
 		assessResult = a.assessor.Failure(a.failureFindByIdentity(result))
 	}
 
+	if ctx.Err() != nil {
+		return status.New(a.provenance, status.Cancelled)
+	}
+
 	m := annotation.New(a.uniqueProvider.Get(), id, nil, assessMetadata.New(a.provenance, assessResult))
 	result = a.store.Append(id, m)
 	if result == status.NotFound {
 		result = a.store.Create(id, m)
+		if result == status.Exists {
+			// another annotator in the same sdk stage created identity after Append was attempted.
+			result = a.store.Append(id, m)
+		}
 	}
 	return status.New(a.provenance, result)
 }
 
 // Create evaluates newly-created data.
 func (a *annotator) Create(data []byte) *status.Contract {
-	return a.assess(data)
+	return a.assess(context.Background(), data)
+}
+
+// CreateContext evaluates newly-created data within ctx.
+func (a *annotator) CreateContext(ctx context.Context, data []byte) *status.Contract {
+	return a.assess(ctx, data)
 }
 
 // Mutate evaluates mutated data.
 func (a *annotator) Mutate(_, newData []byte) *status.Contract {
-	return a.assess(newData)
+	return a.assess(context.Background(), newData)
+}
+
+// MutateContext evaluates mutated data within ctx.
+func (a *annotator) MutateContext(ctx context.Context, _, newData []byte) *status.Contract {
+	return a.assess(ctx, newData)
+}
+
+// Derive evaluates data derived from multiple sources.
+func (a *annotator) Derive(_ [][]byte, data []byte) *status.Contract {
+	return a.assess(context.Background(), data)
+}
+
+// DeriveContext evaluates data derived from multiple sources within ctx.
+func (a *annotator) DeriveContext(ctx context.Context, _ [][]byte, data []byte) *status.Contract {
+	return a.assess(ctx, data)
+}
+
+// Transfer evaluates data whose ownership has been transferred.
+func (a *annotator) Transfer(data []byte, _ identity.Contract) *status.Contract {
+	return a.assess(context.Background(), data)
+}
+
+// TransferContext evaluates data whose ownership has been transferred within ctx.
+func (a *annotator) TransferContext(ctx context.Context, data []byte, _ identity.Contract) *status.Contract {
+	return a.assess(ctx, data)
 }
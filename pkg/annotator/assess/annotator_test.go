@@ -15,20 +15,26 @@
 package assess
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	testMetadata "github.com/project-alvarium/go-sdk/internal/pkg/test/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider/ulid"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor"
 	assessorStub "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/stub"
 	assessMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/filter/matching"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/filter/passthrough"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
 	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
 	"github.com/project-alvarium/go-sdk/pkg/identityprovider"
 	identityProvider "github.com/project-alvarium/go-sdk/pkg/identityprovider/hash"
 	"github.com/project-alvarium/go-sdk/pkg/status"
@@ -136,6 +142,144 @@ func TestAnnotator_Create(t *testing.T) {
 				)
 			},
 		},
+		{
+			name: "identity created concurrently",
+			test: func(t *testing.T) {
+				prov := test.FactoryRandomString()
+				idProvider := identityProvider.New(sha256.New())
+				data := test.FactoryRandomByteSlice()
+				id := idProvider.Derive(data)
+				a := annotation.New(
+					test.FactoryRandomString(),
+					id,
+					nil,
+					metadataStub.New(test.FactoryRandomString(), test.FactoryRandomString()),
+				)
+				persistence := &racingStore{Contract: memory.New(), annotation: a}
+				sut := newSUT(
+					prov,
+					idProvider,
+					persistence,
+					assessorStub.New(test.FactoryRandomString(), metadataStub.NewNullObject()),
+				)
+
+				result := sut.Create(data)
+
+				assert.Equal(t, status.New(prov, status.Success), result)
+				testMetadata.Assert(
+					t,
+					[]*annotation.Instance{
+						a,
+						annotation.New(
+							test.FactoryRandomString(),
+							id,
+							nil,
+							assessMetadata.New(prov, sut.assessor.Failure(sut.failureFindByIdentity(status.NotFound))),
+						),
+					},
+					id,
+					persistence,
+				)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// racingStore is a store whose first Append creates identity with annotation before reporting it as not found,
+// simulating another annotator creating identity concurrently.
+type racingStore struct {
+	store.Contract
+	annotation *annotation.Instance
+	raced      bool
+}
+
+// Append stores annotations corresponding to identity and returns status.
+func (s *racingStore) Append(id identity.Contract, m *annotation.Instance) status.Value {
+	if !s.raced {
+		s.raced = true
+		s.Contract.Create(id, s.annotation)
+		return status.NotFound
+	}
+	return s.Contract.Append(id, m)
+}
+
+// blockingAssessor is an assessor whose Assess blocks until release is closed.
+type blockingAssessor struct {
+	assessor.Contract
+	release chan struct{}
+}
+
+// Assess accepts data and returns associated assessments.
+func (a *blockingAssessor) Assess(annotations []*annotation.Instance) metadata.Contract {
+	<-a.release
+	return a.Contract.Assess(annotations)
+}
+
+// Failure creates an assessor-specific failure annotation.
+func (a *blockingAssessor) Failure(errorMessage string) metadata.Contract {
+	return metadataStub.New(a.Kind(), errorMessage)
+}
+
+// TestAnnotator_CreateContext tests annotator.CreateContext.
+func TestAnnotator_CreateContext(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "done beforehand",
+			test: func(t *testing.T) {
+				prov := test.FactoryRandomString()
+				idProvider := identityProvider.New(sha256.New())
+				persistence := memory.New()
+				data := test.FactoryRandomByteSlice()
+				sut := newSUT(
+					prov,
+					idProvider,
+					persistence,
+					assessorStub.New(test.FactoryRandomString(), metadataStub.NewNullObject()),
+				)
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				result := sut.CreateContext(ctx, data)
+
+				assert.Equal(t, status.New(prov, status.Cancelled), result)
+				_, findResult := persistence.FindByIdentity(idProvider.Derive(data))
+				assert.Equal(t, status.NotFound, findResult)
+			},
+		},
+		{
+			name: "deadline exceeded while assessing",
+			test: func(t *testing.T) {
+				prov := test.FactoryRandomString()
+				idProvider := identityProvider.New(sha256.New())
+				persistence := memory.New()
+				data := test.FactoryRandomByteSlice()
+				id := idProvider.Derive(data)
+				a := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				assert.Equal(t, status.Success, persistence.Create(id, a))
+				blocking := &blockingAssessor{
+					Contract: assessorStub.New(test.FactoryRandomString(), metadataStub.NewNullObject()),
+					release:  make(chan struct{}),
+				}
+				defer close(blocking.release)
+				sut := newSUT(prov, idProvider, persistence, blocking)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				result := sut.CreateContext(ctx, data)
+
+				assert.Equal(t, status.New(prov, status.Cancelled), result)
+				testMetadata.Assert(t, []*annotation.Instance{a}, id, persistence)
+			},
+		},
 	}
 
 	for i := range cases {
@@ -218,3 +362,73 @@ func TestAnnotator_Mutate(t *testing.T) {
 		t.Run(cases[i].name, cases[i].test)
 	}
 }
+
+// TestAnnotator_Derive tests annotator.Derive.
+func TestAnnotator_Derive(t *testing.T) {
+	prov := test.FactoryRandomString()
+	idProvider := identityProvider.New(sha256.New())
+	persistence := memory.New()
+	kind := test.FactoryRandomString()
+	data := test.FactoryRandomByteSlice()
+	id := idProvider.Derive(data)
+	m := metadataStub.New(kind, test.FactoryRandomString())
+	a := annotation.New(test.FactoryRandomString(), id, nil, m)
+	assert.Equal(t, status.Success, persistence.Create(id, a))
+	sut := newSUT(prov, idProvider, persistence, assessorStub.New(kind, m))
+
+	result := sut.Derive([][]byte{test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice()}, data)
+
+	assert.Equal(t, status.New(prov, status.Success), result)
+	testMetadata.Assert(
+		t,
+		[]*annotation.Instance{
+			a,
+			annotation.New(test.FactoryRandomString(), id, nil, assessMetadata.New(prov, m)),
+		},
+		id,
+		persistence,
+	)
+}
+
+// TestAnnotator_NewWithQuery tests annotator retrieval through the store's query capability.
+func TestAnnotator_NewWithQuery(t *testing.T) {
+	prov := test.FactoryRandomString()
+	idProvider := identityProvider.New(sha256.New())
+	persistence := memory.New()
+	kind := test.FactoryRandomString()
+	data := test.FactoryRandomByteSlice()
+	id := idProvider.Derive(data)
+	m := metadataStub.New(kind, test.FactoryRandomString())
+	a1 := annotation.New(test.FactoryRandomString(), id, nil, m)
+	a2 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+	assert.Equal(t, status.Success, persistence.Create(id, a1))
+	assert.Equal(t, status.Success, persistence.Append(id, a2))
+	filtered := make([]*annotation.Instance, 0)
+	sut := NewWithQuery(
+		prov,
+		ulid.New(),
+		idProvider,
+		persistence,
+		assessorStub.New(kind, m),
+		matching.New(func(annotation *annotation.Instance) bool {
+			filtered = append(filtered, annotation)
+			return true
+		}),
+		query.Criteria{Kinds: []string{kind}},
+	)
+
+	result := sut.Create(data)
+
+	assert.Equal(t, status.New(prov, status.Success), result)
+	assert.Equal(t, []*annotation.Instance{a1}, filtered)
+	testMetadata.Assert(
+		t,
+		[]*annotation.Instance{
+			a1,
+			a2,
+			annotation.New(test.FactoryRandomString(), id, nil, assessMetadata.New(prov, m)),
+		},
+		id,
+		persistence,
+	)
+}
@@ -0,0 +1,53 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package assessor
+
+import (
+	"context"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/cancellation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+)
+
+// adapter is a receiver that bounds an assessor's Assess by a context.
+type adapter struct {
+	Contract
+}
+
+// WithContext returns a context-aware form of assessor.
+//
+// Assessors that implement ContextContract are returned as is.  Otherwise the result calls assessor's Assess but
+// returns assessor's Failure (reporting the context's error) as soon as the context is done; the call cannot be
+// interrupted and runs to completion in the background.
+func WithContext(assessor Contract) ContextContract {
+	if c, ok := assessor.(ContextContract); ok {
+		return c
+	}
+	return &adapter{Contract: assessor}
+}
+
+// AssessContext assesses annotations within ctx.
+func (a *adapter) AssessContext(ctx context.Context, annotations []*annotation.Instance) metadata.Contract {
+	if err := ctx.Err(); err != nil {
+		return a.Failure(err.Error())
+	}
+
+	var result metadata.Contract
+	if err := cancellation.Run(ctx, func() { result = a.Assess(annotations) }); err != nil {
+		return a.Failure(err.Error())
+	}
+	return result
+}
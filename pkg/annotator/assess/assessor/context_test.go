@@ -0,0 +1,130 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package assessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/stub"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blocking is an assessor whose Assess returns once release is closed.
+type blocking struct {
+	Contract
+	release chan struct{}
+	called  int
+}
+
+// newBlocking returns a new blocking assessor.
+func newBlocking(assessment metadata.Contract) *blocking {
+	return &blocking{
+		Contract: stub.New(test.FactoryRandomString(), assessment),
+		release:  make(chan struct{}),
+	}
+}
+
+// Assess accepts data and returns associated assessments.
+func (b *blocking) Assess(annotations []*annotation.Instance) metadata.Contract {
+	b.called++
+	<-b.release
+	return b.Contract.Assess(annotations)
+}
+
+// Failure creates an assessor-specific failure annotation.
+func (b *blocking) Failure(errorMessage string) metadata.Contract {
+	return metadataStub.New(b.Kind(), errorMessage)
+}
+
+// contextAware is an assessor that implements ContextContract.
+type contextAware struct {
+	*blocking
+}
+
+// AssessContext accepts data and returns associated assessments within ctx.
+func (c *contextAware) AssessContext(_ context.Context, annotations []*annotation.Instance) metadata.Contract {
+	return c.Assess(annotations)
+}
+
+// TestWithContext tests WithContext.
+func TestWithContext(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "context-aware assessor returned as is",
+			test: func(t *testing.T) {
+				p := &contextAware{blocking: newBlocking(nil)}
+
+				assert.Equal(t, p, WithContext(p))
+			},
+		},
+		{
+			name: "completed",
+			test: func(t *testing.T) {
+				assessment := metadataStub.NewNullObject()
+				p := newBlocking(assessment)
+				close(p.release)
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				result := WithContext(p).AssessContext(ctx, []*annotation.Instance{})
+
+				assert.Equal(t, assessment, result)
+				assert.Equal(t, 1, p.called)
+			},
+		},
+		{
+			name: "done before assessing",
+			test: func(t *testing.T) {
+				p := newBlocking(nil)
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				result := WithContext(p).AssessContext(ctx, []*annotation.Instance{})
+
+				assert.Equal(t, p.Failure(context.Canceled.Error()), result)
+				assert.Equal(t, 0, p.called)
+			},
+		},
+		{
+			name: "deadline exceeded during assessing",
+			test: func(t *testing.T) {
+				p := newBlocking(nil)
+				defer close(p.release)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				result := WithContext(p).AssessContext(ctx, []*annotation.Instance{})
+
+				assert.Equal(t, p.Failure(context.DeadlineExceeded.Error()), result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
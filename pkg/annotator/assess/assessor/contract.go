@@ -15,6 +15,8 @@
 package assessor
 
 import (
+	"context"
+
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 )
@@ -36,3 +38,12 @@ type Contract interface {
 	// Kind returns an implementation mnemonic.
 	Kind() string
 }
+
+// ContextContract defines the abstraction implemented by assessors that observe a context's cancellation and deadline
+// (see WithContext).
+type ContextContract interface {
+	Contract
+
+	// AssessContext accepts data and returns associated assessments within ctx.
+	AssessContext(ctx context.Context, annotations []*annotation.Instance) metadata.Contract
+}
@@ -0,0 +1,63 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// ownership implements an assessor that verifies the current owner of data.
+package ownership
+
+import (
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	ownershipAssessorMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/ownership/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/ownership"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+)
+
+// assessor is a receiver that encapsulates required dependencies.
+type assessor struct {
+	expected identity.Contract
+}
+
+// New is a factory function that returns an initialized assessor.
+func New(expected identity.Contract) *assessor {
+	return &assessor{
+		expected: expected,
+	}
+}
+
+// SetUp is called once when the assessor is instantiated.
+func (*assessor) SetUp() {}
+
+// TearDown is called once when assessor is terminated.
+func (*assessor) TearDown() {}
+
+// Assess accepts data and returns whether its current owner (see ownership.Current) is the expected owner.
+func (a *assessor) Assess(annotations []*annotation.Instance) metadata.Contract {
+	owner, m := ownership.Current(annotations)
+	if owner == nil {
+		return ownershipAssessorMetadata.NewSuccess(false, "", "")
+	}
+
+	valid := a.expected != nil && owner.Kind() == a.expected.Kind() && owner.Printable() == a.expected.Printable()
+	return ownershipAssessorMetadata.NewSuccess(valid, owner.Printable(), m.Unique)
+}
+
+// Failure creates a publisher-specific failure annotation.
+func (a *assessor) Failure(errorMessage string) metadata.Contract {
+	return ownershipAssessorMetadata.NewFailure(errorMessage)
+}
+
+// Kind returns an implementation mnemonic.
+func (*assessor) Kind() string {
+	return ownershipAssessorMetadata.Kind
+}
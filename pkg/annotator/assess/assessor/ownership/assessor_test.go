@@ -0,0 +1,180 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package ownership
+
+import (
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider/ulid"
+	ownershipAssessorMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/ownership/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/ownership"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/identity/principal"
+	identityProvider "github.com/project-alvarium/go-sdk/pkg/identityprovider/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSUT returns a new system under test.
+func newSUT(expected identity.Contract) *assessor {
+	return New(expected)
+}
+
+// TestAssessor_SetUp tests assessor.SetUp.
+func TestAssessor_SetUp(t *testing.T) {
+	sut := newSUT(nil)
+
+	// no assertions; called for coverage.
+	sut.SetUp()
+}
+
+// TestAssessor_TearDown tests assessor.TearDown.
+func TestAssessor_TearDown(t *testing.T) {
+	sut := newSUT(nil)
+
+	// no assertions; called for coverage.
+	sut.TearDown()
+}
+
+// TestAssessor_Assess tests assessor.Assess.
+func TestAssessor_Assess(t *testing.T) {
+	owner, transferee := principal.New(test.FactoryRandomString()), principal.New(test.FactoryRandomString())
+	idProvider := identityProvider.New(sha256.New())
+
+	// annotate returns the annotations of data created by owner and, if transferee is not nil, transferred to it.
+	annotate := func(t *testing.T, transferee identity.Contract) []*annotation.Instance {
+		persistence := memory.New()
+		a := ownership.New(test.FactoryRandomString(), ulid.New(), idProvider, persistence, owner, owner)
+		data := test.FactoryRandomByteSlice()
+		require.Equal(t, status.Success, a.Create(data).Value)
+		if transferee != nil {
+			require.Equal(t, status.Success, a.Transfer(data, transferee).Value)
+		}
+		annotations, result := persistence.FindByIdentity(idProvider.Derive(data))
+		require.Equal(t, status.Success, result)
+		return annotations
+	}
+
+	type testCase struct {
+		name        string
+		expected    identity.Contract
+		transferee  identity.Contract
+		valid       bool
+		ownerIndex  int
+		expectOwner identity.Contract
+	}
+
+	cases := []testCase{
+		{
+			name:        "original owner",
+			expected:    owner,
+			valid:       true,
+			ownerIndex:  0,
+			expectOwner: owner,
+		},
+		{
+			name:        "transferred owner",
+			expected:    transferee,
+			transferee:  transferee,
+			valid:       true,
+			ownerIndex:  1,
+			expectOwner: transferee,
+		},
+		{
+			name:        "previous owner",
+			expected:    owner,
+			transferee:  transferee,
+			valid:       false,
+			ownerIndex:  1,
+			expectOwner: transferee,
+		},
+		{
+			name:        "same printable value, different kind",
+			expected:    identityHash.New([]byte(owner.Printable())),
+			valid:       false,
+			ownerIndex:  0,
+			expectOwner: owner,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				annotations := annotate(t, cases[i].transferee)
+				sut := newSUT(cases[i].expected)
+
+				result := sut.Assess(annotations)
+
+				assert.Equal(
+					t,
+					testInternal.Marshal(
+						t,
+						ownershipAssessorMetadata.NewSuccess(
+							cases[i].valid,
+							cases[i].expectOwner.Printable(),
+							annotations[cases[i].ownerIndex].Unique,
+						),
+					),
+					testInternal.Marshal(t, result),
+				)
+			},
+		)
+	}
+
+	t.Run(
+		"no owner",
+		func(t *testing.T) {
+			sut := newSUT(owner)
+
+			result := sut.Assess([]*annotation.Instance{})
+
+			assert.Equal(
+				t,
+				testInternal.Marshal(t, ownershipAssessorMetadata.NewSuccess(false, "", "")),
+				testInternal.Marshal(t, result),
+			)
+		},
+	)
+}
+
+// TestAssessor_Failure tests assessor.Failure.
+func TestAssessor_Failure(t *testing.T) {
+	message := test.FactoryRandomString()
+	sut := newSUT(nil)
+
+	result := sut.Failure(message)
+
+	assert.Equal(
+		t,
+		testInternal.Marshal(t, ownershipAssessorMetadata.NewFailure(message)),
+		testInternal.Marshal(t, result),
+	)
+}
+
+// TestAssessor_Kind tests assessor.Kind.
+func TestAssessor_Kind(t *testing.T) {
+	sut := newSUT(nil)
+
+	assert.Equal(t, ownershipAssessorMetadata.Kind, sut.Kind())
+}
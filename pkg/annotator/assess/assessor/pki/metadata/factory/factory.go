@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/registry"
 	pkiAssessorMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/metadata"
 )
 
@@ -29,6 +30,13 @@ func New() *instance {
 	return &instance{}
 }
 
+// init registers the PKI assessor metadata kind.
+func init() {
+	if err := registry.RegisterMetadata(registry.Assessor, pkiAssessorMetadata.Kind, New()); err != nil {
+		panic(err)
+	}
+}
+
 // Create returns a contract implementation based on the provided metadata.
 func (i *instance) Create(kind string, data json.RawMessage) metadata.Contract {
 	if kind != pkiAssessorMetadata.Kind {
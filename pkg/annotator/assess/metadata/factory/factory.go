@@ -19,6 +19,8 @@ import (
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/registry"
+	ownershipAssessorFactory "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/ownership/metadata/factory"
 	pkiAssessorFactory "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/metadata/factory"
 	assessMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/metadata"
 )
@@ -40,10 +42,19 @@ func NewDefault() *instance {
 	return New(
 		[]factory.Contract{
 			pkiAssessorFactory.New(),
+			ownershipAssessorFactory.New(),
 		},
 	)
 }
 
+// init registers the assess metadata kind; its assessor metadata is decoded with the registered assessor kinds.
+func init() {
+	f := New([]factory.Contract{registry.NewMetadataFactory(registry.Assessor)})
+	if err := registry.RegisterMetadata(registry.Metadata, assessMetadata.Kind, f); err != nil {
+		panic(err)
+	}
+}
+
 // Create returns a contract implementation based on the provided metadata.
 func (i *instance) Create(kind string, data json.RawMessage) metadata.Contract {
 	switch kind {
@@ -20,6 +20,7 @@ import (
 
 	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	ownershipAssessorMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/ownership/metadata"
 	pkiAssessorMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/assessor/pki/metadata"
 	assessMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/assess/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/test"
@@ -92,6 +93,22 @@ func TestInstance_Create(t *testing.T) {
 
 				result := sut.Create(assessMetadata.Kind, json.RawMessage(testInternal.Marshal(t, value)))
 
+				assert.NotNil(t, result)
+				assert.IsType(t, &assessMetadata.Instance{}, result)
+				assert.Equal(t, testInternal.Marshal(t, value), testInternal.Marshal(t, result))
+			},
+		},
+		{
+			name: "Valid (ownership assessor success)",
+			test: func(t *testing.T) {
+				sut := newDefaultSUT()
+				value := assessMetadata.New(
+					test.FactoryRandomByteSlice(),
+					ownershipAssessorMetadata.NewSuccess(true, test.FactoryRandomString(), test.FactoryRandomString()),
+				)
+
+				result := sut.Create(assessMetadata.Kind, json.RawMessage(testInternal.Marshal(t, value)))
+
 				assert.NotNil(t, result)
 				assert.IsType(t, &assessMetadata.Instance{}, result)
 				assert.Equal(t, testInternal.Marshal(t, value), testInternal.Marshal(t, result))
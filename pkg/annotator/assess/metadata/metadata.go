@@ -20,6 +20,7 @@ import (
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/opaque"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
 )
 
@@ -48,6 +49,11 @@ func (*Instance) Kind() string {
 	return Kind
 }
 
+// GetProvenance returns the provenance recorded in the metadata.
+func (i *Instance) GetProvenance() provenance.Contract {
+	return i.Provenance
+}
+
 // SetAssessorFactories provides for method injection of required factory to unmarshal metadata JSON.
 func (i *Instance) SetAssessorFactories(assessorFactories []metadataFactory.Contract) {
 	i.assessorFactories = assessorFactories
@@ -73,12 +79,7 @@ func (i *Instance) UnmarshalJSON(data []byte) error {
 	i.Provenance = value.Provenance
 	i.AssessorKind = value.AssessorKind
 
-	for f := range i.assessorFactories {
-		if result := i.assessorFactories[f].Create(value.AssessorKind, value.AssessorMetadata); result != nil {
-			i.AssessorMetadata = result
-			break
-		}
-	}
+	i.AssessorMetadata = opaque.Create(i.assessorFactories, value.AssessorKind, value.AssessorMetadata)
 
 	return nil
 }
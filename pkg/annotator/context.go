@@ -0,0 +1,109 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package annotator
+
+import (
+	"context"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/cancellation"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// adapter is a receiver that bounds an annotator's evaluation by a context.
+type adapter struct {
+	Contract
+}
+
+// transfererAdapter is a receiver that bounds the evaluation of an annotator that implements Transferer by a context.
+type transfererAdapter struct {
+	*adapter
+	transferer Transferer
+}
+
+// WithContext returns a context-aware form of annotator.
+//
+// Annotators that implement ContextContract (and ContextTransferer, if they implement Transferer) are returned as is.
+// Otherwise the result calls annotator's methods but returns a status.Cancelled result as soon as the context is done,
+// attributed to annotator's provenance if it implements Provenancer.  The result implements Transferer and
+// ContextTransferer if annotator implements Transferer.
+//
+// An adapted call cannot be interrupted: it runs to completion in the background and may still write its annotation
+// to the store after status.Cancelled is returned, so a cancelled result means the outcome is unknown rather than that
+// nothing was annotated.  Annotators that must not annotate after cancellation implement ContextContract and check
+// the context before writing (as the SDK's annotators do).
+func WithContext(annotator Contract) ContextContract {
+	c, isContext := annotator.(ContextContract)
+	t, isTransferer := annotator.(Transferer)
+	if _, isContextTransferer := annotator.(ContextTransferer); isContext && (!isTransferer || isContextTransferer) {
+		return c
+	}
+
+	a := &adapter{Contract: annotator}
+	if isTransferer {
+		return &transfererAdapter{adapter: a, transferer: t}
+	}
+	return a
+}
+
+// run returns the result of evaluate, or a status.Cancelled result attributed to the annotator's provenance (if it
+// reports one) if ctx is done first.
+func (a *adapter) run(ctx context.Context, evaluate func() *status.Contract) *status.Contract {
+	cancelled := func() *status.Contract {
+		if p, ok := a.Contract.(Provenancer); ok {
+			return status.New(p.Provenance(), status.Cancelled)
+		}
+		return status.New(nil, status.Cancelled)
+	}
+
+	if ctx.Err() != nil {
+		return cancelled()
+	}
+
+	var result *status.Contract
+	if err := cancellation.Run(ctx, func() { result = evaluate() }); err != nil {
+		return cancelled()
+	}
+	return result
+}
+
+// CreateContext evaluates newly-created data within ctx.
+func (a *adapter) CreateContext(ctx context.Context, data []byte) *status.Contract {
+	return a.run(ctx, func() *status.Contract { return a.Create(data) })
+}
+
+// MutateContext evaluates mutated data within ctx.
+func (a *adapter) MutateContext(ctx context.Context, oldData, newData []byte) *status.Contract {
+	return a.run(ctx, func() *status.Contract { return a.Mutate(oldData, newData) })
+}
+
+// DeriveContext evaluates data derived from multiple sources within ctx.
+func (a *adapter) DeriveContext(ctx context.Context, sources [][]byte, data []byte) *status.Contract {
+	return a.run(ctx, func() *status.Contract { return a.Derive(sources, data) })
+}
+
+// Transfer evaluates data whose ownership has been transferred to owner.
+func (a *transfererAdapter) Transfer(data []byte, owner identity.Contract) *status.Contract {
+	return a.transferer.Transfer(data, owner)
+}
+
+// TransferContext evaluates data whose ownership has been transferred to owner within ctx.
+func (a *transfererAdapter) TransferContext(
+	ctx context.Context,
+	data []byte,
+	owner identity.Contract) *status.Contract {
+
+	return a.run(ctx, func() *status.Contract { return a.transferer.Transfer(data, owner) })
+}
@@ -0,0 +1,226 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package annotator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blocking is an annotator whose evaluation returns result once release is closed.
+type blocking struct {
+	result  *status.Contract
+	release chan struct{}
+	called  int
+}
+
+// newBlocking returns a new blocking annotator.
+func newBlocking(result *status.Contract) *blocking {
+	return &blocking{
+		result:  result,
+		release: make(chan struct{}),
+	}
+}
+
+// evaluate returns result once release is closed.
+func (b *blocking) evaluate() *status.Contract {
+	b.called++
+	<-b.release
+	return b.result
+}
+
+// SetUp is called once when the annotator is instantiated.
+func (*blocking) SetUp() {}
+
+// TearDown is called once when annotator is terminated.
+func (*blocking) TearDown() {}
+
+// Create evaluates newly-created data.
+func (b *blocking) Create(_ []byte) *status.Contract {
+	return b.evaluate()
+}
+
+// Mutate evaluates mutated data.
+func (b *blocking) Mutate(_, _ []byte) *status.Contract {
+	return b.evaluate()
+}
+
+// Derive evaluates data derived from multiple sources.
+func (b *blocking) Derive(_ [][]byte, _ []byte) *status.Contract {
+	return b.evaluate()
+}
+
+// blockingTransferer is a blocking annotator that implements Transferer.
+type blockingTransferer struct {
+	*blocking
+}
+
+// Transfer evaluates data whose ownership has been transferred.
+func (b *blockingTransferer) Transfer(_ []byte, _ identity.Contract) *status.Contract {
+	return b.evaluate()
+}
+
+// blockingProvenancer is a blocking annotator that implements Provenancer.
+type blockingProvenancer struct {
+	*blocking
+	provenance provenance.Contract
+}
+
+// Provenance returns the provenance of the annotator's results.
+func (b *blockingProvenancer) Provenance() provenance.Contract {
+	return b.provenance
+}
+
+// contextAware is a blocking annotator that implements ContextContract.
+type contextAware struct {
+	*blocking
+}
+
+// CreateContext evaluates newly-created data within ctx.
+func (c *contextAware) CreateContext(_ context.Context, data []byte) *status.Contract {
+	return c.Create(data)
+}
+
+// MutateContext evaluates mutated data within ctx.
+func (c *contextAware) MutateContext(_ context.Context, oldData, newData []byte) *status.Contract {
+	return c.Mutate(oldData, newData)
+}
+
+// DeriveContext evaluates data derived from multiple sources within ctx.
+func (c *contextAware) DeriveContext(_ context.Context, sources [][]byte, data []byte) *status.Contract {
+	return c.Derive(sources, data)
+}
+
+// contextAwareTransferer is a context-aware annotator that implements Transferer but not ContextTransferer.
+type contextAwareTransferer struct {
+	*contextAware
+}
+
+// Transfer evaluates data whose ownership has been transferred.
+func (c *contextAwareTransferer) Transfer(_ []byte, _ identity.Contract) *status.Contract {
+	return c.evaluate()
+}
+
+// TestWithContext tests WithContext.
+func TestWithContext(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "context-aware annotator returned as is",
+			test: func(t *testing.T) {
+				a := &contextAware{blocking: newBlocking(nil)}
+
+				assert.Equal(t, a, WithContext(a))
+			},
+		},
+		{
+			name: "context-aware transferer adapted",
+			test: func(t *testing.T) {
+				a := &contextAwareTransferer{contextAware: &contextAware{blocking: newBlocking(nil)}}
+
+				_, isContextTransferer := WithContext(a).(ContextTransferer)
+
+				assert.True(t, isContextTransferer)
+			},
+		},
+		{
+			name: "transferer preserved",
+			test: func(t *testing.T) {
+				_, isTransferer := WithContext(newBlocking(nil)).(Transferer)
+				_, isContextTransferer := WithContext(&blockingTransferer{blocking: newBlocking(nil)}).(ContextTransferer)
+
+				assert.False(t, isTransferer)
+				assert.True(t, isContextTransferer)
+			},
+		},
+		{
+			name: "completed",
+			test: func(t *testing.T) {
+				result := status.New(test.FactoryRandomString(), status.Success)
+				b := &blockingTransferer{blocking: newBlocking(result)}
+				close(b.release)
+				sut := WithContext(b)
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				assert.Equal(t, result, sut.CreateContext(ctx, test.FactoryRandomByteSlice()))
+				assert.Equal(t, result, sut.MutateContext(ctx, test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice()))
+				assert.Equal(t, result, sut.DeriveContext(ctx, nil, test.FactoryRandomByteSlice()))
+				assert.Equal(
+					t,
+					result,
+					sut.(ContextTransferer).TransferContext(ctx, test.FactoryRandomByteSlice(), nil),
+				)
+				assert.Equal(t, 4, b.called)
+			},
+		},
+		{
+			name: "done before evaluation",
+			test: func(t *testing.T) {
+				b := newBlocking(nil)
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				result := WithContext(b).CreateContext(ctx, test.FactoryRandomByteSlice())
+
+				assert.Equal(t, status.New(nil, status.Cancelled), result)
+				assert.Equal(t, 0, b.called)
+			},
+		},
+		{
+			name: "deadline exceeded during evaluation",
+			test: func(t *testing.T) {
+				b := &blockingTransferer{blocking: newBlocking(nil)}
+				defer close(b.release)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				result := WithContext(b).(ContextTransferer).TransferContext(ctx, test.FactoryRandomByteSlice(), nil)
+
+				assert.Equal(t, status.New(nil, status.Cancelled), result)
+			},
+		},
+		{
+			name: "cancelled result attributed to provenance",
+			test: func(t *testing.T) {
+				p := test.FactoryRandomString()
+				b := &blockingProvenancer{blocking: newBlocking(nil), provenance: p}
+				defer close(b.release)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				result := WithContext(b).CreateContext(ctx, test.FactoryRandomByteSlice())
+
+				assert.Equal(t, status.New(p, status.Cancelled), result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
@@ -14,7 +14,13 @@
 
 package annotator
 
-import "github.com/project-alvarium/go-sdk/pkg/status"
+import (
+	"context"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
 
 const (
 	SuccessKind = "success"
@@ -34,4 +40,42 @@ type Contract interface {
 
 	// Mutate evaluates mutated data.
 	Mutate(oldData, newData []byte) *status.Contract
+
+	// Derive evaluates data derived (e.g. merged or aggregated) from multiple sources.
+	Derive(sources [][]byte, data []byte) *status.Contract
+}
+
+// Transferer defines the optional abstraction implemented by annotators that evaluate a transfer of ownership.
+type Transferer interface {
+	// Transfer evaluates data whose ownership has been transferred to owner.
+	Transfer(data []byte, owner identity.Contract) *status.Contract
+}
+
+// Provenancer defines the optional abstraction implemented by annotators that report the provenance of their results
+// (used by WithContext to attribute a cancelled result).
+type Provenancer interface {
+	// Provenance returns the provenance of the annotator's results.
+	Provenance() provenance.Contract
+}
+
+// ContextContract defines the abstraction implemented by annotators whose evaluation observes a context's
+// cancellation and deadline (see WithContext).
+type ContextContract interface {
+	Contract
+
+	// CreateContext evaluates newly-created data within ctx.
+	CreateContext(ctx context.Context, data []byte) *status.Contract
+
+	// MutateContext evaluates mutated data within ctx.
+	MutateContext(ctx context.Context, oldData, newData []byte) *status.Contract
+
+	// DeriveContext evaluates data derived from multiple sources within ctx.
+	DeriveContext(ctx context.Context, sources [][]byte, data []byte) *status.Contract
+}
+
+// ContextTransferer defines the optional abstraction implemented by context-aware annotators that evaluate a transfer
+// of ownership.
+type ContextTransferer interface {
+	// TransferContext evaluates data whose ownership has been transferred to owner within ctx.
+	TransferContext(ctx context.Context, data []byte, owner identity.Contract) *status.Contract
 }
@@ -0,0 +1,227 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// ownership implements an annotator that records the author and owner of data and transfers of its ownership.
+package ownership
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/ownership/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/identityprovider"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// annotator is a receiver that encapsulates required dependencies.
+type annotator struct {
+	provenance       provenance.Contract
+	uniqueProvider   uniqueprovider.Contract
+	identityProvider identityprovider.Contract
+	store            store.Contract
+	author           identity.Contract
+	owner            identity.Contract
+}
+
+// New is a factory function that returns an initialized annotator.
+//
+// Annotations are authored by author.  Created and derived data is owned by owner; mutated data retains the current
+// owner of the data it was mutated from (or owner if it has none) until ownership is transferred.
+func New(
+	provenance provenance.Contract,
+	uniqueProvider uniqueprovider.Contract,
+	identityProvider identityprovider.Contract,
+	store store.Contract,
+	author identity.Contract,
+	owner identity.Contract) *annotator {
+
+	return &annotator{
+		provenance:       provenance,
+		uniqueProvider:   uniqueProvider,
+		identityProvider: identityProvider,
+		store:            store,
+		author:           author,
+		owner:            owner,
+	}
+}
+
+// Current returns the current owner recorded in annotations (ordered as returned by store.Contract.FindByIdentity)
+// and the annotation that recorded it, or nil if no owner is recorded.
+//
+// The most recent owner recorded against the first identity is current; if none is, the search continues with
+// each predecessor in turn, nearest first.
+func Current(annotations []*annotation.Instance) (identity.Contract, *annotation.Instance) {
+	var found *annotation.Instance
+	for i := range annotations {
+		if found != nil && annotations[i].CurrentIdentity.Printable() != found.CurrentIdentity.Printable() {
+			break
+		}
+		if annotations[i].Owner != nil {
+			found = annotations[i]
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+	return found.Owner, found
+}
+
+// printable returns the printable value of id or an empty string if id is nil.
+func printable(id identity.Contract) string {
+	if id == nil {
+		return ""
+	}
+	return id.Printable()
+}
+
+// currentOwner returns the current owner of the data identified by id, or nil if none is recorded.
+func (a *annotator) currentOwner(id identity.Contract) identity.Contract {
+	annotations, result := a.store.FindByIdentity(id)
+	if result != status.Success {
+		return nil
+	}
+	owner, _ := Current(annotations)
+	return owner
+}
+
+// annotation returns a new annotation recording owner.
+func (a *annotator) annotation(
+	id identity.Contract,
+	previousIdentities []identity.Contract,
+	owner identity.Contract,
+	m *metadata.Instance) *annotation.Instance {
+
+	var result *annotation.Instance
+	switch len(previousIdentities) {
+	case 0:
+		result = annotation.New(a.uniqueProvider.Get(), id, nil, m)
+	case 1:
+		result = annotation.New(a.uniqueProvider.Get(), id, previousIdentities[0], m)
+	default:
+		result = annotation.NewDerived(a.uniqueProvider.Get(), id, previousIdentities, m)
+	}
+	result.SetAuthor(a.author)
+	result.SetOwner(owner)
+	return result
+}
+
+// SetUp is called once when the annotator is instantiated.
+func (*annotator) SetUp() {}
+
+// TearDown is called once when annotator is terminated.
+func (*annotator) TearDown() {}
+
+// Create evaluates newly-created data.
+func (a *annotator) Create(data []byte) *status.Contract {
+	id := a.identityProvider.Derive(data)
+	m := a.annotation(id, nil, a.owner, metadata.New(a.provenance, metadata.ActionCreate, ""))
+	return status.New(a.provenance, a.store.Create(id, m))
+}
+
+// Mutate evaluates mutated data.
+func (a *annotator) Mutate(oldData, newData []byte) *status.Contract {
+	oldDataIdentity := a.identityProvider.Derive(oldData)
+	newDataIdentity := a.identityProvider.Derive(newData)
+	owner := a.currentOwner(oldDataIdentity)
+	if owner == nil {
+		owner = a.owner
+	}
+	m := a.annotation(
+		newDataIdentity,
+		[]identity.Contract{oldDataIdentity},
+		owner,
+		metadata.New(a.provenance, metadata.ActionMutate, ""),
+	)
+
+	if !bytes.Equal(oldDataIdentity.Binary(), newDataIdentity.Binary()) {
+		return status.New(a.provenance, a.store.Create(newDataIdentity, m))
+	}
+	return status.New(a.provenance, a.store.Append(newDataIdentity, m))
+}
+
+// Derive evaluates data derived from multiple sources.
+func (a *annotator) Derive(sources [][]byte, data []byte) *status.Contract {
+	appendToExisting := false
+	sourceIdentities := make([]identity.Contract, 0, len(sources))
+	dataIdentity := a.identityProvider.Derive(data)
+	seen := make(map[string]struct{})
+	for i := range sources {
+		id := a.identityProvider.Derive(sources[i])
+		if _, exists := seen[id.Printable()]; exists {
+			continue
+		}
+		seen[id.Printable()] = struct{}{}
+		sourceIdentities = append(sourceIdentities, id)
+		if bytes.Equal(id.Binary(), dataIdentity.Binary()) {
+			appendToExisting = true
+		}
+	}
+
+	m := a.annotation(dataIdentity, sourceIdentities, a.owner, metadata.New(a.provenance, metadata.ActionDerive, ""))
+	if appendToExisting {
+		return status.New(a.provenance, a.store.Append(dataIdentity, m))
+	}
+	return status.New(a.provenance, a.store.Create(dataIdentity, m))
+}
+
+// Transfer records that ownership of data has been transferred to owner.
+func (a *annotator) Transfer(data []byte, owner identity.Contract) *status.Contract {
+	id := a.identityProvider.Derive(data)
+	m := a.annotation(
+		id,
+		nil,
+		owner,
+		metadata.New(a.provenance, metadata.ActionTransfer, printable(a.currentOwner(id))),
+	)
+	return status.New(a.provenance, a.store.Append(id, m))
+}
+
+// CreateContext evaluates newly-created data within ctx; nothing is annotated if ctx is done beforehand.
+func (a *annotator) CreateContext(ctx context.Context, data []byte) *status.Contract {
+	if ctx.Err() != nil {
+		return status.New(a.provenance, status.Cancelled)
+	}
+	return a.Create(data)
+}
+
+// MutateContext evaluates mutated data within ctx; nothing is annotated if ctx is done beforehand.
+func (a *annotator) MutateContext(ctx context.Context, oldData, newData []byte) *status.Contract {
+	if ctx.Err() != nil {
+		return status.New(a.provenance, status.Cancelled)
+	}
+	return a.Mutate(oldData, newData)
+}
+
+// DeriveContext evaluates data derived from multiple sources within ctx; nothing is annotated if ctx is done
+// beforehand.
+func (a *annotator) DeriveContext(ctx context.Context, sources [][]byte, data []byte) *status.Contract {
+	if ctx.Err() != nil {
+		return status.New(a.provenance, status.Cancelled)
+	}
+	return a.Derive(sources, data)
+}
+
+// TransferContext records that ownership of data has been transferred to owner within ctx; nothing is annotated if
+// ctx is done beforehand.
+func (a *annotator) TransferContext(ctx context.Context, data []byte, owner identity.Contract) *status.Contract {
+	if ctx.Err() != nil {
+		return status.New(a.provenance, status.Cancelled)
+	}
+	return a.Transfer(data, owner)
+}
@@ -0,0 +1,331 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package ownership
+
+import (
+	"context"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider/ulid"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/ownership/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/identity/principal"
+	identityProvider "github.com/project-alvarium/go-sdk/pkg/identityprovider/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSUT returns a new system under test.
+func newSUT(store store.Contract, author, owner identity.Contract) *annotator {
+	return New(test.FactoryRandomString(), ulid.New(), identityProvider.New(sha256.New()), store, author, owner)
+}
+
+// newPrincipal returns a new random principal.
+func newPrincipal() identity.Contract {
+	return principal.New(test.FactoryRandomString())
+}
+
+// currentOwner returns the current owner of data in store.
+func currentOwner(t *testing.T, s store.Contract, data []byte) identity.Contract {
+	annotations, result := s.FindByIdentity(identityProvider.New(sha256.New()).Derive(data))
+	require.Equal(t, status.Success, result)
+	owner, _ := Current(annotations)
+	return owner
+}
+
+// latest returns the most recent annotation stored for data in store.
+func latest(t *testing.T, s store.Contract, data []byte) *annotation.Instance {
+	annotations, result := s.FindByIdentity(identityProvider.New(sha256.New()).Derive(data))
+	require.Equal(t, status.Success, result)
+	id := annotations[0].CurrentIdentity.Printable()
+	var m *annotation.Instance
+	for i := range annotations {
+		if annotations[i].CurrentIdentity.Printable() == id {
+			m = annotations[i]
+		}
+	}
+	return m
+}
+
+// TestAnnotator_Create tests annotator.Create.
+func TestAnnotator_Create(t *testing.T) {
+	s := memory.New()
+	author, owner := newPrincipal(), newPrincipal()
+	sut := newSUT(s, author, owner)
+	data := test.FactoryRandomByteSlice()
+
+	result := sut.Create(data)
+
+	assert.Equal(t, status.Success, result.Value)
+	m := latest(t, s, data)
+	assert.Equal(t, author, m.Author)
+	assert.Equal(t, principal.Kind, m.AuthorKind)
+	assert.Equal(t, owner, m.Owner)
+	assert.Equal(t, principal.Kind, m.OwnerKind)
+	assert.Equal(t, metadata.ActionCreate, m.Metadata.(*metadata.Instance).Action)
+	assert.Equal(t, status.Exists, sut.Create(data).Value)
+}
+
+// TestAnnotator_Context tests the annotator's context-aware methods.
+func TestAnnotator_Context(t *testing.T) {
+	type testCase struct {
+		name     string
+		evaluate func(ctx context.Context, sut *annotator, data []byte) *status.Contract
+	}
+
+	cases := []testCase{
+		{
+			name: "create",
+			evaluate: func(ctx context.Context, sut *annotator, data []byte) *status.Contract {
+				return sut.CreateContext(ctx, data)
+			},
+		},
+		{
+			name: "mutate",
+			evaluate: func(ctx context.Context, sut *annotator, data []byte) *status.Contract {
+				return sut.MutateContext(ctx, test.FactoryRandomByteSlice(), data)
+			},
+		},
+		{
+			name: "derive",
+			evaluate: func(ctx context.Context, sut *annotator, data []byte) *status.Contract {
+				return sut.DeriveContext(ctx, [][]byte{test.FactoryRandomByteSlice()}, data)
+			},
+		},
+		{
+			name: "transfer",
+			evaluate: func(ctx context.Context, sut *annotator, data []byte) *status.Contract {
+				_ = sut.Create(data)
+				return sut.TransferContext(ctx, data, newPrincipal())
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				s := memory.New()
+				sut := newSUT(s, newPrincipal(), newPrincipal())
+				data := test.FactoryRandomByteSlice()
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				result := cases[i].evaluate(ctx, sut, data)
+
+				assert.Equal(t, status.Cancelled, result.Value)
+				assert.Equal(t, status.Success, cases[i].evaluate(context.Background(), sut, data).Value)
+			},
+		)
+	}
+}
+
+// TestAnnotator_Mutate tests annotator.Mutate.
+func TestAnnotator_Mutate(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "retains current owner",
+			test: func(t *testing.T) {
+				s := memory.New()
+				owner, transferee := newPrincipal(), newPrincipal()
+				sut := newSUT(s, newPrincipal(), owner)
+				oldData, newData := test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice()
+				require.Equal(t, status.Success, sut.Create(oldData).Value)
+				require.Equal(t, status.Success, sut.Transfer(oldData, transferee).Value)
+
+				result := sut.Mutate(oldData, newData)
+
+				assert.Equal(t, status.Success, result.Value)
+				assert.Equal(t, transferee, currentOwner(t, s, newData))
+				assert.Equal(t, metadata.ActionMutate, latest(t, s, newData).Metadata.(*metadata.Instance).Action)
+			},
+		},
+		{
+			name: "unowned data",
+			test: func(t *testing.T) {
+				s := memory.New()
+				owner := newPrincipal()
+				sut := newSUT(s, newPrincipal(), owner)
+				newData := test.FactoryRandomByteSlice()
+
+				result := sut.Mutate(test.FactoryRandomByteSlice(), newData)
+
+				assert.Equal(t, status.Success, result.Value)
+				assert.Equal(t, owner, currentOwner(t, s, newData))
+			},
+		},
+		{
+			name: "same data",
+			test: func(t *testing.T) {
+				s := memory.New()
+				sut := newSUT(s, newPrincipal(), newPrincipal())
+				data := test.FactoryRandomByteSlice()
+				require.Equal(t, status.Success, sut.Create(data).Value)
+
+				result := sut.Mutate(data, data)
+
+				assert.Equal(t, status.Success, result.Value)
+				annotations, _ := s.FindByIdentity(identityProvider.New(sha256.New()).Derive(data))
+				assert.Equal(t, 2, len(annotations))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestAnnotator_Derive tests annotator.Derive.
+func TestAnnotator_Derive(t *testing.T) {
+	s := memory.New()
+	owner := newPrincipal()
+	sut := newSUT(s, newPrincipal(), owner)
+	source1, source2, data := test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice()
+	require.Equal(t, status.Success, sut.Create(source1).Value)
+	require.Equal(t, status.Success, sut.Transfer(source1, newPrincipal()).Value)
+
+	result := sut.Derive([][]byte{source1, source2, source1}, data)
+
+	assert.Equal(t, status.Success, result.Value)
+	m := latest(t, s, data)
+	assert.Equal(t, 2, len(m.PreviousIdentities))
+	assert.Equal(t, owner, m.Owner)
+	assert.Equal(t, metadata.ActionDerive, m.Metadata.(*metadata.Instance).Action)
+}
+
+// TestAnnotator_Transfer tests annotator.Transfer.
+func TestAnnotator_Transfer(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "records new and previous owner",
+			test: func(t *testing.T) {
+				s := memory.New()
+				author, owner, first, second := newPrincipal(), newPrincipal(), newPrincipal(), newPrincipal()
+				sut := newSUT(s, author, owner)
+				data := test.FactoryRandomByteSlice()
+				require.Equal(t, status.Success, sut.Create(data).Value)
+
+				assert.Equal(t, status.Success, sut.Transfer(data, first).Value)
+				assert.Equal(t, status.Success, sut.Transfer(data, second).Value)
+
+				assert.Equal(t, second, currentOwner(t, s, data))
+				m := latest(t, s, data)
+				assert.Equal(t, author, m.Author)
+				assert.Equal(t, metadata.ActionTransfer, m.Metadata.(*metadata.Instance).Action)
+				assert.Equal(t, first.Printable(), m.Metadata.(*metadata.Instance).PreviousOwner)
+			},
+		},
+		{
+			name: "unknown data",
+			test: func(t *testing.T) {
+				sut := newSUT(memory.New(), newPrincipal(), newPrincipal())
+
+				result := sut.Transfer(test.FactoryRandomByteSlice(), newPrincipal())
+
+				assert.Equal(t, status.NotFound, result.Value)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestCurrent tests Current.
+func TestCurrent(t *testing.T) {
+	newAnnotation := func(id, owner identity.Contract) *annotation.Instance {
+		m := annotation.New(test.FactoryRandomString(), id, nil, metadata.New(nil, metadata.ActionCreate, ""))
+		m.SetOwner(owner)
+		return m
+	}
+	id, previous := newPrincipal(), newPrincipal()
+	owner1, owner2, owner3 := newPrincipal(), newPrincipal(), newPrincipal()
+
+	type testCase struct {
+		name          string
+		annotations   []*annotation.Instance
+		expectedOwner identity.Contract
+		expectedIndex int
+	}
+
+	cases := []testCase{
+		{
+			name:          "empty",
+			annotations:   []*annotation.Instance{},
+			expectedIndex: -1,
+		},
+		{
+			name:          "no owner",
+			annotations:   []*annotation.Instance{newAnnotation(id, nil), newAnnotation(previous, nil)},
+			expectedIndex: -1,
+		},
+		{
+			name: "latest owner of identity",
+			annotations: []*annotation.Instance{
+				newAnnotation(id, owner1),
+				newAnnotation(id, nil),
+				newAnnotation(id, owner2),
+				newAnnotation(previous, owner3),
+			},
+			expectedOwner: owner2,
+			expectedIndex: 2,
+		},
+		{
+			name: "nearest predecessor",
+			annotations: []*annotation.Instance{
+				newAnnotation(id, nil),
+				newAnnotation(previous, owner1),
+				newAnnotation(previous, owner3),
+			},
+			expectedOwner: owner3,
+			expectedIndex: 2,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				owner, m := Current(cases[i].annotations)
+
+				assert.Equal(t, cases[i].expectedOwner, owner)
+				if cases[i].expectedIndex < 0 {
+					assert.Nil(t, m)
+					return
+				}
+				assert.Equal(t, cases[i].annotations[cases[i].expectedIndex], m)
+			},
+		)
+	}
+}
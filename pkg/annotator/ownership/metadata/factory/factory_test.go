@@ -0,0 +1,87 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package factory
+
+import (
+	"encoding/json"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	ownershipMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/ownership/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInstance_Create tests instance.Create.
+func TestInstance_Create(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "Unknown name",
+			test: func(t *testing.T) {
+				sut := New()
+
+				result := sut.Create(test.FactoryRandomString(), test.FactoryRandomByteSlice())
+
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "Null",
+			test: func(t *testing.T) {
+				sut := New()
+
+				result := sut.Create(ownershipMetadata.Kind, json.RawMessage("null"))
+
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "Invalid JSON",
+			test: func(t *testing.T) {
+				sut := New()
+
+				result := sut.Create(ownershipMetadata.Kind, json.RawMessage("{"))
+
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "Valid",
+			test: func(t *testing.T) {
+				sut := New()
+				value := ownershipMetadata.New(
+					test.FactoryRandomString(),
+					ownershipMetadata.ActionTransfer,
+					test.FactoryRandomString(),
+				)
+
+				result := sut.Create(ownershipMetadata.Kind, json.RawMessage(testInternal.Marshal(t, value)))
+
+				assert.IsType(t, &ownershipMetadata.Instance{}, result)
+				assert.Equal(t, testInternal.Marshal(t, value), testInternal.Marshal(t, result))
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
@@ -0,0 +1,52 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metadata
+
+import "github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
+
+const (
+	Kind = "ownership"
+
+	ActionCreate   = "create"
+	ActionMutate   = "mutate"
+	ActionDerive   = "derive"
+	ActionTransfer = "transfer"
+)
+
+// Instance is the annotator-specific metadata.
+type Instance struct {
+	Provenance    provenance.Contract `json:"provenance"`
+	Action        string              `json:"action"`
+	PreviousOwner string              `json:"previousOwner,omitempty"`
+}
+
+// New is a factory function that returns an initialized Instance.
+func New(provenance provenance.Contract, action string, previousOwner string) *Instance {
+	return &Instance{
+		Provenance:    provenance,
+		Action:        action,
+		PreviousOwner: previousOwner,
+	}
+}
+
+// Kind returns the type of concrete implementation.
+func (*Instance) Kind() string {
+	return Kind
+}
+
+// GetProvenance returns the provenance recorded in the metadata.
+func (i *Instance) GetProvenance() provenance.Contract {
+	return i.Provenance
+}
@@ -0,0 +1,38 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInstance_Kind tests instance.Kind.
+func TestInstance_Kind(t *testing.T) {
+	sut := New(test.FactoryRandomString(), ActionCreate, "")
+
+	assert.Equal(t, Kind, sut.Kind())
+}
+
+// TestInstance_GetProvenance tests instance.GetProvenance.
+func TestInstance_GetProvenance(t *testing.T) {
+	prov := test.FactoryRandomString()
+	sut := New(prov, ActionTransfer, test.FactoryRandomString())
+
+	assert.Equal(t, prov, sut.GetProvenance())
+}
@@ -17,12 +17,13 @@ package pki
 
 import (
 	"bytes"
+	"context"
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
-	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer"
+	pkiSigner "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
 	"github.com/project-alvarium/go-sdk/pkg/identity"
 	"github.com/project-alvarium/go-sdk/pkg/identityprovider"
@@ -35,7 +36,7 @@ type annotator struct {
 	uniqueProvider   uniqueprovider.Contract
 	identityProvider identityprovider.Contract
 	store            store.Contract
-	signer           signer.Contract
+	signer           pkiSigner.ContextContract
 }
 
 // New is a factory function that returns an initialized annotator.
@@ -44,36 +45,33 @@ func New(
 	uniqueProvider uniqueprovider.Contract,
 	identityProvider identityprovider.Contract,
 	store store.Contract,
-	signer signer.Contract) *annotator {
+	signer pkiSigner.Contract) *annotator {
 
 	return &annotator{
 		provenance:       provenance,
 		uniqueProvider:   uniqueProvider,
 		identityProvider: identityProvider,
 		store:            store,
-		signer:           signer,
+		signer:           pkiSigner.WithContext(signer),
 	}
 }
 
 // metadata is a private factory function that delegates to metadata.New() and returns Annotate.
 func (a *annotator) metadata(
 	identity identity.Contract,
-	previousIdentity identity.Contract,
+	previousIdentities []identity.Contract,
 	identitySignature []byte,
 	dataSignature []byte) *annotation.Instance {
 
-	return annotation.New(
-		a.uniqueProvider.Get(),
-		identity,
-		previousIdentity,
-		metadata.New(
-			a.provenance,
-			identitySignature,
-			dataSignature,
-			a.signer.PublicKey(),
-			a.signer.Metadata(),
-		),
-	)
+	m := metadata.New(a.provenance, identitySignature, dataSignature, a.signer.PublicKey(), a.signer.Metadata())
+	if len(previousIdentities) > 1 {
+		return annotation.NewDerived(a.uniqueProvider.Get(), identity, previousIdentities, m)
+	}
+
+	if len(previousIdentities) == 1 {
+		return annotation.New(a.uniqueProvider.Get(), identity, previousIdentities[0], m)
+	}
+	return annotation.New(a.uniqueProvider.Get(), identity, nil, m)
 }
 
 // SetUp is called once when the signer is instantiated.
@@ -86,26 +84,83 @@ func (a *annotator) TearDown() {
 	a.signer.TearDown()
 }
 
-// sign evaluates data and returns metadata.
-func (a *annotator) sign(oldIdentity identity.Contract, data []byte) (identity.Contract, *annotation.Instance) {
+// sign evaluates data within ctx and returns metadata, or the context's error if ctx is done before signing completes.
+func (a *annotator) sign(
+	ctx context.Context,
+	oldIdentities []identity.Contract,
+	data []byte) (identity.Contract, *annotation.Instance, error) {
+
 	id := a.identityProvider.Derive(data)
-	identitySignature, dataSignature := a.signer.Sign(id.Binary(), data)
-	return id, a.metadata(id, oldIdentity, identitySignature, dataSignature)
+	identitySignature, dataSignature, err := a.signer.SignContext(ctx, id.Binary(), data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return id, a.metadata(id, oldIdentities, identitySignature, dataSignature), nil
 }
 
 // Create evaluates newly-created data.
 func (a *annotator) Create(data []byte) *status.Contract {
-	id, m := a.sign(nil, data)
+	return a.CreateContext(context.Background(), data)
+}
+
+// CreateContext evaluates newly-created data within ctx; nothing is annotated if ctx is done before signing completes.
+func (a *annotator) CreateContext(ctx context.Context, data []byte) *status.Contract {
+	id, m, err := a.sign(ctx, nil, data)
+	if err != nil {
+		return status.New(a.provenance, status.Cancelled)
+	}
 	return status.New(a.provenance, a.store.Create(id, m))
 }
 
 // Mutate evaluates mutated data.
 func (a *annotator) Mutate(oldData, newData []byte) *status.Contract {
+	return a.MutateContext(context.Background(), oldData, newData)
+}
+
+// MutateContext evaluates mutated data within ctx; nothing is annotated if ctx is done before signing completes.
+func (a *annotator) MutateContext(ctx context.Context, oldData, newData []byte) *status.Contract {
 	oldDataIdentity := a.identityProvider.Derive(oldData)
-	newDataIdentity, m := a.sign(oldDataIdentity, newData)
+	newDataIdentity, m, err := a.sign(ctx, []identity.Contract{oldDataIdentity}, newData)
+	if err != nil {
+		return status.New(a.provenance, status.Cancelled)
+	}
 
 	if !bytes.Equal(oldDataIdentity.Binary(), newDataIdentity.Binary()) {
 		return status.New(a.provenance, a.store.Create(newDataIdentity, m))
 	}
 	return status.New(a.provenance, a.store.Append(newDataIdentity, m))
 }
+
+// Derive evaluates data derived from multiple sources.
+func (a *annotator) Derive(sources [][]byte, data []byte) *status.Contract {
+	return a.DeriveContext(context.Background(), sources, data)
+}
+
+// DeriveContext evaluates data derived from multiple sources within ctx; nothing is annotated if ctx is done before
+// signing completes.
+func (a *annotator) DeriveContext(ctx context.Context, sources [][]byte, data []byte) *status.Contract {
+	appendToExisting := false
+	sourceIdentities := make([]identity.Contract, 0, len(sources))
+	dataIdentity := a.identityProvider.Derive(data)
+	seen := make(map[string]struct{})
+	for i := range sources {
+		id := a.identityProvider.Derive(sources[i])
+		if _, exists := seen[id.Printable()]; exists {
+			continue
+		}
+		seen[id.Printable()] = struct{}{}
+		sourceIdentities = append(sourceIdentities, id)
+		if bytes.Equal(id.Binary(), dataIdentity.Binary()) {
+			appendToExisting = true
+		}
+	}
+
+	id, m, err := a.sign(ctx, sourceIdentities, data)
+	if err != nil {
+		return status.New(a.provenance, status.Cancelled)
+	}
+	if appendToExisting {
+		return status.New(a.provenance, a.store.Append(id, m))
+	}
+	return status.New(a.provenance, a.store.Create(id, m))
+}
@@ -15,8 +15,10 @@
 package pki
 
 import (
+	"context"
 	"crypto"
 	"testing"
+	"time"
 
 	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
 	testMetadata "github.com/project-alvarium/go-sdk/internal/pkg/test/metadata"
@@ -31,6 +33,7 @@ import (
 	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
 	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
 	"github.com/project-alvarium/go-sdk/pkg/identityprovider"
 	identityProvider "github.com/project-alvarium/go-sdk/pkg/identityprovider/hash"
 	"github.com/project-alvarium/go-sdk/pkg/status"
@@ -243,6 +246,88 @@ func TestAnnotator_Create(t *testing.T) {
 	}
 }
 
+// blockingSigner is a signer whose Sign blocks until release is closed.
+type blockingSigner struct {
+	signer.Contract
+	release chan struct{}
+}
+
+// Sign returns a signature for the given identity and data.
+func (s *blockingSigner) Sign(identity, data []byte) (identitySignature, dataSignature []byte) {
+	<-s.release
+	return s.Contract.Sign(identity, data)
+}
+
+// TestAnnotator_Context tests the annotator's context-aware methods.
+func TestAnnotator_Context(t *testing.T) {
+	type testCase struct {
+		name     string
+		evaluate func(ctx context.Context, sut *annotator, data []byte) *status.Contract
+	}
+
+	cases := []testCase{
+		{
+			name: "create",
+			evaluate: func(ctx context.Context, sut *annotator, data []byte) *status.Contract {
+				return sut.CreateContext(ctx, data)
+			},
+		},
+		{
+			name: "mutate",
+			evaluate: func(ctx context.Context, sut *annotator, data []byte) *status.Contract {
+				return sut.MutateContext(ctx, test.FactoryRandomByteSlice(), data)
+			},
+		},
+		{
+			name: "derive",
+			evaluate: func(ctx context.Context, sut *annotator, data []byte) *status.Contract {
+				return sut.DeriveContext(ctx, [][]byte{test.FactoryRandomByteSlice()}, data)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name+" done beforehand",
+			func(t *testing.T) {
+				prov := test.FactoryRandomString()
+				idProvider := identityProvider.New(sha256.New())
+				persistence := memory.New()
+				data := test.FactoryRandomByteSlice()
+				sut := newSUT(prov, idProvider, persistence, fail.New())
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				result := cases[i].evaluate(ctx, sut, data)
+
+				assert.Equal(t, status.New(prov, status.Cancelled), result)
+				_, findResult := persistence.FindByIdentity(idProvider.Derive(data))
+				assert.Equal(t, status.NotFound, findResult)
+			},
+		)
+		t.Run(
+			cases[i].name+" deadline exceeded while signing",
+			func(t *testing.T) {
+				prov := test.FactoryRandomString()
+				idProvider := identityProvider.New(sha256.New())
+				persistence := memory.New()
+				data := test.FactoryRandomByteSlice()
+				s := &blockingSigner{Contract: fail.New(), release: make(chan struct{})}
+				defer close(s.release)
+				sut := newSUT(prov, idProvider, persistence, s)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				result := cases[i].evaluate(ctx, sut, data)
+
+				assert.Equal(t, status.New(prov, status.Cancelled), result)
+				_, findResult := persistence.FindByIdentity(idProvider.Derive(data))
+				assert.Equal(t, status.NotFound, findResult)
+			},
+		)
+	}
+}
+
 // TestAnnotator_Mutate tests annotator.Mutate.
 func TestAnnotator_Mutate(t *testing.T) {
 	type testCase struct {
@@ -567,3 +652,118 @@ func TestAnnotator_Mutate(t *testing.T) {
 		)
 	}
 }
+
+// TestAnnotator_Derive tests annotator.Derive.
+func TestAnnotator_Derive(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	newSigner := func() signer.Contract {
+		return signpkcs1v15.New(crypto.SHA256, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, sha256.New())
+	}
+	newMetadata := func(p provenance.Contract, s signer.Contract, data []byte) *metadata.Instance {
+		identitySignature, dataSignature := s.Sign(identityProvider.New(sha256.New()).Derive(data).Binary(), data)
+		return metadata.New(p, identitySignature, dataSignature, testInternal.ValidPublicKey, s.Metadata())
+	}
+
+	cases := []testCase{
+		{
+			name: "Derive From Multiple Sources",
+			test: func(t *testing.T) {
+				p := test.FactoryRandomString()
+				persistence := memory.New()
+				idProvider := identityProvider.New(sha256.New())
+				s := newSigner()
+				sut := newSUT(p, idProvider, persistence, s)
+				data1, data2, data3 := test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice()
+				id1, id2, id3 := idProvider.Derive(data1), idProvider.Derive(data2), idProvider.Derive(data3)
+				assert.Equal(t, status.New(p, status.Success), sut.Create(data1))
+				assert.Equal(t, status.New(p, status.Success), sut.Create(data2))
+
+				result := sut.Derive([][]byte{data1, data2, data1}, data3)
+
+				assert.Equal(t, status.New(p, status.Success), result)
+				testMetadata.Assert(
+					t,
+					[]*annotation.Instance{
+						annotation.NewDerived(
+							test.FactoryRandomString(),
+							id3,
+							[]identity.Contract{id1, id2},
+							newMetadata(p, s, data3),
+						),
+						annotation.New(test.FactoryRandomString(), id1, nil, newMetadata(p, s, data1)),
+						annotation.New(test.FactoryRandomString(), id2, nil, newMetadata(p, s, data2)),
+					},
+					id3,
+					persistence,
+				)
+			},
+		},
+		{
+			name: "Derive Into Existing Source",
+			test: func(t *testing.T) {
+				p := test.FactoryRandomString()
+				persistence := memory.New()
+				idProvider := identityProvider.New(sha256.New())
+				s := newSigner()
+				sut := newSUT(p, idProvider, persistence, s)
+				data1, data2 := test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice()
+				id1, id2 := idProvider.Derive(data1), idProvider.Derive(data2)
+				assert.Equal(t, status.New(p, status.Success), sut.Create(data1))
+				assert.Equal(t, status.New(p, status.Success), sut.Create(data2))
+
+				result := sut.Derive([][]byte{data1, data2}, data1)
+
+				assert.Equal(t, status.New(p, status.Success), result)
+				testMetadata.Assert(
+					t,
+					[]*annotation.Instance{
+						annotation.New(test.FactoryRandomString(), id1, nil, newMetadata(p, s, data1)),
+						annotation.NewDerived(
+							test.FactoryRandomString(),
+							id1,
+							[]identity.Contract{id1, id2},
+							newMetadata(p, s, data1),
+						),
+						annotation.New(test.FactoryRandomString(), id2, nil, newMetadata(p, s, data2)),
+					},
+					id1,
+					persistence,
+				)
+			},
+		},
+		{
+			name: "Derive From Single Source",
+			test: func(t *testing.T) {
+				p := test.FactoryRandomString()
+				persistence := memory.New()
+				idProvider := identityProvider.New(sha256.New())
+				s := newSigner()
+				sut := newSUT(p, idProvider, persistence, s)
+				data1, data2 := test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice()
+				id1, id2 := idProvider.Derive(data1), idProvider.Derive(data2)
+				assert.Equal(t, status.New(p, status.Success), sut.Create(data1))
+
+				result := sut.Derive([][]byte{data1}, data2)
+
+				assert.Equal(t, status.New(p, status.Success), result)
+				testMetadata.Assert(
+					t,
+					[]*annotation.Instance{
+						annotation.New(test.FactoryRandomString(), id2, id1, newMetadata(p, s, data2)),
+						annotation.New(test.FactoryRandomString(), id1, nil, newMetadata(p, s, data1)),
+					},
+					id2,
+					persistence,
+				)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
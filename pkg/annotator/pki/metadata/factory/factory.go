@@ -19,6 +19,7 @@ import (
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/registry"
 	pkiAnnotatorMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
 	signpkcs1v15Factory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15/metadata/factory"
 	signtpmv2Factory "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signtpmv2/metadata/factory"
@@ -46,6 +47,14 @@ func NewDefault() *instance {
 	)
 }
 
+// init registers the PKI metadata kind; its signer metadata is decoded with the registered signer kinds.
+func init() {
+	f := New([]factory.Contract{registry.NewMetadataFactory(registry.Signer)})
+	if err := registry.RegisterMetadata(registry.Metadata, pkiAnnotatorMetadata.Kind, f); err != nil {
+		panic(err)
+	}
+}
+
 // Create returns a contract implementation based on the provided metadata.
 func (i *instance) Create(kind string, data json.RawMessage) metadata.Contract {
 	switch kind {
@@ -21,6 +21,7 @@ import (
 
 	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	opaqueMetadata "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/opaque"
 	pkiAnnotatorMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
 	pkcsSignerMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
@@ -86,6 +87,29 @@ func TestInstance_Create(t *testing.T) {
 				assert.Equal(t, testInternal.Marshal(t, value), testInternal.Marshal(t, result))
 			},
 		},
+		{
+			name: "Valid (pkiAnnotator), unrecognized signer",
+			test: func(t *testing.T) {
+				sut := newDefaultSUT()
+				signerMetadata := opaqueMetadata.New(
+					test.FactoryRandomString(),
+					json.RawMessage(`{"z":"`+test.FactoryRandomString()+`","a":[1.50]}`),
+				)
+				value := pkiAnnotatorMetadata.New(
+					test.FactoryRandomByteSlice(),
+					test.FactoryRandomByteSlice(),
+					test.FactoryRandomByteSlice(),
+					test.FactoryRandomByteSlice(),
+					signerMetadata,
+				)
+
+				result := sut.Create(pkiAnnotatorMetadata.Kind, json.RawMessage(testInternal.Marshal(t, value)))
+
+				assert.IsType(t, &pkiAnnotatorMetadata.Instance{}, result)
+				assert.Equal(t, signerMetadata, result.(*pkiAnnotatorMetadata.Instance).SignerMetadata)
+				assert.Equal(t, testInternal.Marshal(t, value), testInternal.Marshal(t, result))
+			},
+		},
 	}
 
 	for i := range cases {
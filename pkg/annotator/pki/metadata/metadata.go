@@ -20,6 +20,7 @@ import (
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/opaque"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
 )
 
@@ -60,6 +61,11 @@ func (*Instance) Kind() string {
 	return Kind
 }
 
+// GetProvenance returns the provenance recorded in the metadata.
+func (i *Instance) GetProvenance() provenance.Contract {
+	return i.Provenance
+}
+
 // SetSignerFactories provides for method injection of required factory to unmarshal metadata JSON.
 func (i *Instance) SetSignerFactories(signerFactories []metadataFactory.Contract) {
 	i.signerFactories = signerFactories
@@ -91,12 +97,7 @@ func (i *Instance) UnmarshalJSON(data []byte) error {
 	i.PublicKey = value.PublicKey
 	i.SignerKind = value.SignerKind
 
-	for f := range i.signerFactories {
-		if result := i.signerFactories[f].Create(value.SignerKind, value.SignerMetadata); result != nil {
-			i.SignerMetadata = result
-			break
-		}
-	}
+	i.SignerMetadata = opaque.Create(i.signerFactories, value.SignerKind, value.SignerMetadata)
 
 	return nil
 }
@@ -35,3 +35,17 @@ func TestInstance_Kind(t *testing.T) {
 
 	assert.Equal(t, Kind, sut.Kind())
 }
+
+// TestInstance_GetProvenance tests instance.GetProvenance.
+func TestInstance_GetProvenance(t *testing.T) {
+	p := test.FactoryRandomString()
+	sut := New(
+		p,
+		test.FactoryRandomByteSlice(),
+		test.FactoryRandomByteSlice(),
+		test.FactoryRandomByteSlice(),
+		metadataStub.NewNullObject(),
+	)
+
+	assert.Equal(t, p, sut.GetProvenance())
+}
@@ -0,0 +1,67 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package signer
+
+import (
+	"context"
+
+	"github.com/project-alvarium/go-sdk/internal/pkg/cancellation"
+)
+
+// adapter is a receiver that bounds a signer's Sign by a context.
+type adapter struct {
+	Contract
+	signing chan struct{}
+}
+
+// WithContext returns a context-aware form of signer.
+//
+// Signers that implement ContextContract are returned as is.  Otherwise the result calls signer's Sign but returns
+// the context's error as soon as the context is done; the call cannot be interrupted and runs to completion in the
+// background.  Because a signer's Metadata reports the outcome of its last signature, the result does not start
+// signing until any such abandoned call completes.
+func WithContext(signer Contract) ContextContract {
+	if c, ok := signer.(ContextContract); ok {
+		return c
+	}
+	return &adapter{
+		Contract: signer,
+		signing:  make(chan struct{}, 1),
+	}
+}
+
+// SignContext returns a signature for the given identity and data, or the context's error if ctx is done before
+// signing completes.
+func (a *adapter) SignContext(ctx context.Context, identity, data []byte) ([]byte, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	select {
+	case a.signing <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	var identitySignature, dataSignature []byte
+	err := cancellation.Run(ctx, func() {
+		defer func() { <-a.signing }()
+		identitySignature, dataSignature = a.Sign(identity, data)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return identitySignature, dataSignature, nil
+}
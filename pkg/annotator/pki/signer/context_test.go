@@ -0,0 +1,169 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package signer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blocking is a signer whose Sign returns once release is closed.
+type blocking struct {
+	m        sync.Mutex
+	release  chan struct{}
+	called   int
+	active   int
+	overlaps int
+}
+
+// newBlocking returns a new blocking signer.
+func newBlocking() *blocking {
+	return &blocking{
+		release: make(chan struct{}),
+	}
+}
+
+// SetUp is called once when the signer is instantiated.
+func (*blocking) SetUp() {}
+
+// TearDown is called once when signer is terminated.
+func (*blocking) TearDown() {}
+
+// PublicKey returns the associated public key.
+func (*blocking) PublicKey() []byte {
+	return nil
+}
+
+// Sign returns a signature for the given identity and data.
+func (b *blocking) Sign(identity, data []byte) (identitySignature, dataSignature []byte) {
+	b.m.Lock()
+	b.called++
+	b.active++
+	if b.active > 1 {
+		b.overlaps++
+	}
+	b.m.Unlock()
+
+	<-b.release
+
+	b.m.Lock()
+	b.active--
+	b.m.Unlock()
+	return identity, data
+}
+
+// Metadata returns implementation-specific metadata.
+func (*blocking) Metadata() metadata.Contract {
+	return metadataStub.NewNullObject()
+}
+
+// contextAware is a signer that implements ContextContract.
+type contextAware struct {
+	*blocking
+}
+
+// SignContext returns a signature for the given identity and data within ctx.
+func (c *contextAware) SignContext(_ context.Context, identity, data []byte) ([]byte, []byte, error) {
+	identitySignature, dataSignature := c.Sign(identity, data)
+	return identitySignature, dataSignature, nil
+}
+
+// TestWithContext tests WithContext.
+func TestWithContext(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "context-aware signer returned as is",
+			test: func(t *testing.T) {
+				s := &contextAware{blocking: newBlocking()}
+
+				assert.Equal(t, s, WithContext(s))
+			},
+		},
+		{
+			name: "completed",
+			test: func(t *testing.T) {
+				s := newBlocking()
+				close(s.release)
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				identity, data := test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice()
+
+				identitySignature, dataSignature, err := WithContext(s).SignContext(ctx, identity, data)
+
+				require.NoError(t, err)
+				assert.Equal(t, identity, identitySignature)
+				assert.Equal(t, data, dataSignature)
+			},
+		},
+		{
+			name: "done before signing",
+			test: func(t *testing.T) {
+				s := newBlocking()
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				identitySignature, dataSignature, err := WithContext(s).SignContext(ctx, nil, nil)
+
+				assert.Equal(t, context.Canceled, err)
+				assert.Nil(t, identitySignature)
+				assert.Nil(t, dataSignature)
+				assert.Equal(t, 0, s.called)
+			},
+		},
+		{
+			name: "deadline exceeded during signing",
+			test: func(t *testing.T) {
+				s := newBlocking()
+				sut := WithContext(s)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				_, _, err := sut.SignContext(ctx, nil, nil)
+				assert.Equal(t, context.DeadlineExceeded, err)
+
+				second, cancelSecond := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancelSecond()
+				_, _, err = sut.SignContext(second, nil, nil)
+				assert.Equal(t, context.DeadlineExceeded, err)
+
+				close(s.release)
+				_, _, err = sut.SignContext(context.Background(), nil, nil)
+				require.NoError(t, err)
+				s.m.Lock()
+				defer s.m.Unlock()
+				assert.Equal(t, 2, s.called)
+				assert.Equal(t, 0, s.overlaps)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
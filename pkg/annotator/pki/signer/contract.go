@@ -14,7 +14,12 @@
 
 package signer
 
-import "github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+import (
+	"context"
+	"errors"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+)
 
 const (
 	PublicKeyType     = "PUBLIC KEY"
@@ -38,3 +43,35 @@ type Contract interface {
 	// Metadata returns implementation-specific metadata.
 	Metadata() metadata.Contract
 }
+
+// DataContract defines the abstraction implemented by signers that can sign data without an identity (see SignData).
+type DataContract interface {
+	Contract
+
+	// SignData returns a signature for the given data, or an error if the data could not be signed.
+	SignData(data []byte) (dataSignature []byte, err error)
+}
+
+// SignData returns signer's signature for data alone, or an error if the data could not be signed; signers that do not
+// implement DataContract are asked to sign data with an empty identity, whose signature is discarded, and fail if they
+// return an empty signature.
+func SignData(signer Contract, data []byte) ([]byte, error) {
+	if s, ok := signer.(DataContract); ok {
+		return s.SignData(data)
+	}
+	_, dataSignature := signer.Sign(nil, data)
+	if len(dataSignature) == 0 {
+		return nil, errors.New("signer returned an empty signature")
+	}
+	return dataSignature, nil
+}
+
+// ContextContract defines the abstraction implemented by signers that observe a context's cancellation and deadline
+// (see WithContext).
+type ContextContract interface {
+	Contract
+
+	// SignContext returns a signature for the given identity and data, or the context's error if ctx is done before
+	// signing completes.
+	SignContext(ctx context.Context, identity, data []byte) (identitySignature, dataSignature []byte, err error)
+}
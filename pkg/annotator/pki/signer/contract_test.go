@@ -0,0 +1,94 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package signer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// echo is a signer whose signatures are the data it signs.
+type echo struct{}
+
+// SetUp is called once when the signer is instantiated.
+func (echo) SetUp() {}
+
+// TearDown is called once when signer is terminated.
+func (echo) TearDown() {}
+
+// PublicKey returns the associated public key.
+func (echo) PublicKey() []byte {
+	return nil
+}
+
+// Sign returns a signature for the given identity and data.
+func (echo) Sign(identity, data []byte) (identitySignature, dataSignature []byte) {
+	return identity, data
+}
+
+// Metadata returns implementation-specific metadata.
+func (echo) Metadata() metadata.Contract {
+	return metadataStub.NewNullObject()
+}
+
+// failingData is a signer that implements DataContract and fails to sign data.
+type failingData struct {
+	echo
+}
+
+// SignData returns an error.
+func (failingData) SignData(_ []byte) ([]byte, error) {
+	return nil, errors.New("unavailable")
+}
+
+// TestSignData tests SignData.
+func TestSignData(t *testing.T) {
+	type testCase struct {
+		name          string
+		signer        Contract
+		data          []byte
+		expectedError bool
+	}
+
+	data := test.FactoryRandomByteSlice()
+	cases := []testCase{
+		{name: "signed with Sign", signer: echo{}, data: data},
+		{name: "empty signature from Sign", signer: echo{}, data: nil, expectedError: true},
+		{name: "error from SignData", signer: failingData{}, data: data, expectedError: true},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				signature, err := SignData(cases[i].signer, cases[i].data)
+
+				if cases[i].expectedError {
+					assert.Error(t, err)
+					assert.Nil(t, signature)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, cases[i].data, signature)
+			},
+		)
+	}
+}
@@ -68,14 +68,21 @@ func (s *signer) PublicKey() []byte {
 }
 
 // sign implements the common signature implementation.
-func (s *signer) sign(hash []byte) (signature []byte) {
+func (s *signer) sign(hash []byte) (signature []byte, err error) {
 	signature, s.signerError = rsa.SignPKCS1v15(rand.Reader, s.privateKey, s.hash, hash[:])
-	return signature
+	return signature, s.signerError
 }
 
 // Sign returns a signature for the given identity and data.
 func (s *signer) Sign(identity, data []byte) (identitySignature, dataSignature []byte) {
-	return s.sign(s.hashProvider.Derive(identity)), s.sign(s.hashProvider.Derive(data))
+	identitySignature, _ = s.sign(s.hashProvider.Derive(identity))
+	dataSignature, _ = s.sign(s.hashProvider.Derive(data))
+	return identitySignature, dataSignature
+}
+
+// SignData returns a signature for the given data, or an error if the data could not be signed.
+func (s *signer) SignData(data []byte) (dataSignature []byte, err error) {
+	return s.sign(s.hashProvider.Derive(data))
 }
 
 // Metadata returns implementation-specific metadata.
@@ -142,6 +142,19 @@ func TestSigner_Sign(t *testing.T) {
 	}
 }
 
+// TestSigner_SignData tests signpkcs1v15.SignData.
+func TestSigner_SignData(t *testing.T) {
+	h := crypto.SHA256
+	hashProvider := sha256.New()
+	data := test.FactoryRandomByteSlice()
+	sut := newSUT(h, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, hashProvider)
+
+	dataSignature, err := sut.SignData(data)
+
+	assert.NoError(t, err)
+	assert.True(t, verifypkcs1v15.New(h, hashProvider).VerifyData(data, dataSignature, testInternal.ValidPublicKey))
+}
+
 // TestSigner_SetUp tests signpkcs1v15.SetUp.
 func TestSigner_SetUp(t *testing.T) {
 	sut := newSUT(crypto.SHA256, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, sha256.New())
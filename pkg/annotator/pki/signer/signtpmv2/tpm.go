@@ -131,21 +131,28 @@ func (s *signer) PublicKey() []byte {
 }
 
 // sign implements the common signature implementation.
-func (s *signer) sign(data []byte) []byte {
+func (s *signer) sign(data []byte) ([]byte, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
 
 	signature, err := tpm2.Sign(s.rwc, s.handle, "", data, s.scheme)
 	if err != nil {
 		s.signerError = err
-		return nil
+		return nil, err
 	}
-	return signature.RSA.Signature
+	return signature.RSA.Signature, nil
 }
 
 // Sign returns a signature for the given identity and data.
 func (s *signer) Sign(identity, data []byte) (identitySignature, dataSignature []byte) {
-	return s.sign(s.hashProvider.Derive(identity)), s.sign(s.hashProvider.Derive(data))
+	identitySignature, _ = s.sign(s.hashProvider.Derive(identity))
+	dataSignature, _ = s.sign(s.hashProvider.Derive(data))
+	return identitySignature, dataSignature
+}
+
+// SignData returns a signature for the given data, or an error if the data could not be signed.
+func (s *signer) SignData(data []byte) (dataSignature []byte, err error) {
+	return s.sign(s.hashProvider.Derive(data))
 }
 
 // Metadata returns implementation-specific metadata.
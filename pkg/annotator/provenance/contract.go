@@ -16,3 +16,9 @@ package provenance
 
 // Contract defines the provenance abstraction.
 type Contract interface{}
+
+// Source defines the abstraction implemented by metadata that records the provenance of the annotator that created it.
+type Source interface {
+	// GetProvenance returns the provenance recorded in the metadata.
+	GetProvenance() Contract
+}
@@ -16,16 +16,19 @@
 package publish
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/filter"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
 	publishMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
 	"github.com/project-alvarium/go-sdk/pkg/identityprovider"
 	"github.com/project-alvarium/go-sdk/pkg/status"
 )
@@ -38,6 +41,7 @@ type annotator struct {
 	store            store.Contract
 	publisher        publisher.Contract
 	filter           filter.Contract
+	criteria         *query.Criteria
 }
 
 // New is a factory function that returns an initialized annotator.
@@ -59,6 +63,23 @@ func New(
 	}
 }
 
+// NewWithQuery is a factory function that returns an initialized annotator which, if store implements
+// query.Contract, retrieves annotations matching criteria (for the derived identity and its lineage) rather than
+// all annotations returned by FindByIdentity.
+func NewWithQuery(
+	provenance provenance.Contract,
+	uniqueProvider uniqueprovider.Contract,
+	identityProvider identityprovider.Contract,
+	store store.Contract,
+	publisher publisher.Contract,
+	filter filter.Contract,
+	criteria query.Criteria) *annotator {
+
+	a := New(provenance, uniqueProvider, identityProvider, store, publisher, filter)
+	a.criteria = &criteria
+	return a
+}
+
 // SetUp is called once when the signer is instantiated.
 func (a *annotator) SetUp() {
 	a.publisher.SetUp()
@@ -74,33 +95,92 @@ func (a *annotator) failureFindByIdentity(result status.Value) string {
 	return fmt.Sprintf("FindByIdentity returned %d", result)
 }
 
-// publish delegates to publisher's publish method, stores publish result as annotation, and returns status.
-func (a *annotator) publish(data []byte) *status.Contract {
+// find returns annotations and status for identity, querying the store if criteria were provided and supported.
+func (a *annotator) find(id identity.Contract) ([]*annotation.Instance, status.Value) {
+	q, ok := a.store.(query.Contract)
+	if a.criteria == nil || !ok {
+		return a.store.FindByIdentity(id)
+	}
+
+	criteria := *a.criteria
+	criteria.Identity = id
+	criteria.Lineage = true
+	criteria.Cursor = ""
+	return query.All(q, criteria)
+}
+
+// publish delegates to publisher's publish method within ctx, stores publish result as annotation, and returns status.
+//
+// Nothing is annotated if ctx is done before the publish result is stored (including a publish cut short by ctx, whose
+// abandoned publisher may yet succeed); the result is then cancelled.
+func (a *annotator) publish(ctx context.Context, data []byte) *status.Contract {
+	if ctx.Err() != nil {
+		return status.New(a.provenance, status.Cancelled)
+	}
+
 	var publishResult metadata.Contract
 
 	id := a.identityProvider.Derive(data)
-	annotations, result := a.store.FindByIdentity(id)
+	annotations, result := a.find(id)
 	switch result {
 	case status.Success:
-		publishResult = a.publisher.Publish(a.filter.Do(annotations))
+		publishResult = publisher.WithContext(a.publisher).PublishContext(ctx, a.filter.Do(annotations))
 	default:
 		publishResult = a.publisher.Failure(a.failureFindByIdentity(result))
 	}
 
+	if ctx.Err() != nil {
+		return status.New(a.provenance, status.Cancelled)
+	}
+
 	m := annotation.New(a.uniqueProvider.Get(), id, nil, publishMetadata.New(a.provenance, publishResult))
 	result = a.store.Append(id, m)
 	if result == status.NotFound {
 		result = a.store.Create(id, m)
+		if result == status.Exists {
+			// another annotator in the same sdk stage created identity after Append was attempted.
+			result = a.store.Append(id, m)
+		}
 	}
 	return status.New(a.provenance, result)
 }
 
 // Create evaluates newly-created data.
 func (a *annotator) Create(data []byte) *status.Contract {
-	return a.publish(data)
+	return a.publish(context.Background(), data)
+}
+
+// CreateContext evaluates newly-created data within ctx.
+func (a *annotator) CreateContext(ctx context.Context, data []byte) *status.Contract {
+	return a.publish(ctx, data)
 }
 
 // Mutate evaluates mutated data.
 func (a *annotator) Mutate(_, newData []byte) *status.Contract {
-	return a.publish(newData)
+	return a.publish(context.Background(), newData)
+}
+
+// MutateContext evaluates mutated data within ctx.
+func (a *annotator) MutateContext(ctx context.Context, _, newData []byte) *status.Contract {
+	return a.publish(ctx, newData)
+}
+
+// Derive evaluates data derived from multiple sources.
+func (a *annotator) Derive(_ [][]byte, data []byte) *status.Contract {
+	return a.publish(context.Background(), data)
+}
+
+// DeriveContext evaluates data derived from multiple sources within ctx.
+func (a *annotator) DeriveContext(ctx context.Context, _ [][]byte, data []byte) *status.Contract {
+	return a.publish(ctx, data)
+}
+
+// Transfer evaluates data whose ownership has been transferred.
+func (a *annotator) Transfer(data []byte, _ identity.Contract) *status.Contract {
+	return a.publish(context.Background(), data)
+}
+
+// TransferContext evaluates data whose ownership has been transferred within ctx.
+func (a *annotator) TransferContext(ctx context.Context, data []byte, _ identity.Contract) *status.Contract {
+	return a.publish(ctx, data)
 }
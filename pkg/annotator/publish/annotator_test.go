@@ -15,20 +15,26 @@
 package publish
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	testMetadata "github.com/project-alvarium/go-sdk/internal/pkg/test/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/query"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider/ulid"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/filter/matching"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/filter/passthrough"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
 	publishMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher"
 	publisherStub "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/stub"
 	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
 	"github.com/project-alvarium/go-sdk/pkg/identityprovider"
 	identityProvider "github.com/project-alvarium/go-sdk/pkg/identityprovider/hash"
 	"github.com/project-alvarium/go-sdk/pkg/status"
@@ -144,6 +150,147 @@ func TestAnnotator_Create(t *testing.T) {
 				)
 			},
 		},
+		{
+			name: "identity created concurrently",
+			test: func(t *testing.T) {
+				prov := test.FactoryRandomString()
+				idProvider := identityProvider.New(sha256.New())
+				data := test.FactoryRandomByteSlice()
+				id := idProvider.Derive(data)
+				a := annotation.New(
+					test.FactoryRandomString(),
+					id,
+					nil,
+					metadataStub.New(test.FactoryRandomString(), test.FactoryRandomString()),
+				)
+				persistence := &racingStore{Contract: memory.New(), annotation: a}
+				sut := newSUT(
+					prov,
+					idProvider,
+					persistence,
+					publisherStub.New(test.FactoryRandomString(), metadataStub.NewNullObject()),
+				)
+
+				result := sut.Create(data)
+
+				assert.Equal(t, status.New(prov, status.Success), result)
+				testMetadata.Assert(
+					t,
+					[]*annotation.Instance{
+						a,
+						annotation.New(
+							test.FactoryRandomString(),
+							id,
+							nil,
+							publishMetadata.New(
+								prov,
+								sut.publisher.Failure(sut.failureFindByIdentity(status.NotFound)),
+							),
+						),
+					},
+					id,
+					persistence,
+				)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// racingStore is a store whose first Append creates identity with annotation before reporting it as not found,
+// simulating another annotator creating identity concurrently.
+type racingStore struct {
+	store.Contract
+	annotation *annotation.Instance
+	raced      bool
+}
+
+// Append stores annotations corresponding to identity and returns status.
+func (s *racingStore) Append(id identity.Contract, m *annotation.Instance) status.Value {
+	if !s.raced {
+		s.raced = true
+		s.Contract.Create(id, s.annotation)
+		return status.NotFound
+	}
+	return s.Contract.Append(id, m)
+}
+
+// blockingPublisher is a publisher whose Publish blocks until release is closed.
+type blockingPublisher struct {
+	publisher.Contract
+	release chan struct{}
+}
+
+// Publish retrieves and "publishes" annotations.
+func (p *blockingPublisher) Publish(annotations []*annotation.Instance) metadata.Contract {
+	<-p.release
+	return p.Contract.Publish(annotations)
+}
+
+// Failure creates a publisher-specific failure annotation.
+func (p *blockingPublisher) Failure(errorMessage string) metadata.Contract {
+	return metadataStub.New(p.Kind(), errorMessage)
+}
+
+// TestAnnotator_CreateContext tests annotator.CreateContext.
+func TestAnnotator_CreateContext(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "done beforehand",
+			test: func(t *testing.T) {
+				prov := test.FactoryRandomString()
+				idProvider := identityProvider.New(sha256.New())
+				persistence := memory.New()
+				data := test.FactoryRandomByteSlice()
+				sut := newSUT(
+					prov,
+					idProvider,
+					persistence,
+					publisherStub.New(test.FactoryRandomString(), metadataStub.NewNullObject()),
+				)
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				result := sut.CreateContext(ctx, data)
+
+				assert.Equal(t, status.New(prov, status.Cancelled), result)
+				_, findResult := persistence.FindByIdentity(idProvider.Derive(data))
+				assert.Equal(t, status.NotFound, findResult)
+			},
+		},
+		{
+			name: "deadline exceeded while publishing",
+			test: func(t *testing.T) {
+				prov := test.FactoryRandomString()
+				idProvider := identityProvider.New(sha256.New())
+				persistence := memory.New()
+				data := test.FactoryRandomByteSlice()
+				id := idProvider.Derive(data)
+				a := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+				assert.Equal(t, status.Success, persistence.Create(id, a))
+				p := &blockingPublisher{
+					Contract: publisherStub.New(test.FactoryRandomString(), metadataStub.NewNullObject()),
+					release:  make(chan struct{}),
+				}
+				defer close(p.release)
+				sut := newSUT(prov, idProvider, persistence, p)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				result := sut.CreateContext(ctx, data)
+
+				assert.Equal(t, status.New(prov, status.Cancelled), result)
+				testMetadata.Assert(t, []*annotation.Instance{a}, id, persistence)
+			},
+		},
 	}
 
 	for i := range cases {
@@ -230,3 +377,73 @@ func TestAnnotator_Mutate(t *testing.T) {
 		t.Run(cases[i].name, cases[i].test)
 	}
 }
+
+// TestAnnotator_Derive tests annotator.Derive.
+func TestAnnotator_Derive(t *testing.T) {
+	prov := test.FactoryRandomString()
+	idProvider := identityProvider.New(sha256.New())
+	persistence := memory.New()
+	kind := test.FactoryRandomString()
+	data := test.FactoryRandomByteSlice()
+	id := idProvider.Derive(data)
+	m := metadataStub.New(kind, test.FactoryRandomString())
+	a := annotation.New(test.FactoryRandomString(), id, nil, m)
+	assert.Equal(t, status.Success, persistence.Create(id, a))
+	sut := newSUT(prov, idProvider, persistence, publisherStub.New(kind, m))
+
+	result := sut.Derive([][]byte{test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice()}, data)
+
+	assert.Equal(t, status.New(prov, status.Success), result)
+	testMetadata.Assert(
+		t,
+		[]*annotation.Instance{
+			a,
+			annotation.New(test.FactoryRandomString(), id, nil, publishMetadata.New(prov, m)),
+		},
+		id,
+		persistence,
+	)
+}
+
+// TestAnnotator_NewWithQuery tests annotator retrieval through the store's query capability.
+func TestAnnotator_NewWithQuery(t *testing.T) {
+	prov := test.FactoryRandomString()
+	idProvider := identityProvider.New(sha256.New())
+	persistence := memory.New()
+	kind := test.FactoryRandomString()
+	data := test.FactoryRandomByteSlice()
+	id := idProvider.Derive(data)
+	m := metadataStub.New(kind, test.FactoryRandomString())
+	a1 := annotation.New(test.FactoryRandomString(), id, nil, m)
+	a2 := annotation.New(test.FactoryRandomString(), id, nil, metadataStub.NewNullObject())
+	assert.Equal(t, status.Success, persistence.Create(id, a1))
+	assert.Equal(t, status.Success, persistence.Append(id, a2))
+	filtered := make([]*annotation.Instance, 0)
+	sut := NewWithQuery(
+		prov,
+		ulid.New(),
+		idProvider,
+		persistence,
+		publisherStub.New(kind, m),
+		matching.New(func(annotation *annotation.Instance) bool {
+			filtered = append(filtered, annotation)
+			return true
+		}),
+		query.Criteria{Kinds: []string{kind}},
+	)
+
+	result := sut.Create(data)
+
+	assert.Equal(t, status.New(prov, status.Success), result)
+	assert.Equal(t, []*annotation.Instance{a1}, filtered)
+	testMetadata.Assert(
+		t,
+		[]*annotation.Instance{
+			a1,
+			a2,
+			annotation.New(test.FactoryRandomString(), id, nil, publishMetadata.New(prov, m)),
+		},
+		id,
+		persistence,
+	)
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/registry"
 	publishMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/metadata"
 	exampleMetadataFactory "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/example/metadata/factory"
 	iotaMetadataFactory "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/iota/metadata/factory"
@@ -48,6 +49,14 @@ func NewDefault() *instance {
 	)
 }
 
+// init registers the publish metadata kind; its publisher metadata is decoded with the registered publisher kinds.
+func init() {
+	f := New([]factory.Contract{registry.NewMetadataFactory(registry.Publisher)})
+	if err := registry.RegisterMetadata(registry.Metadata, publishMetadata.Kind, f); err != nil {
+		panic(err)
+	}
+}
+
 // Create returns a contract implementation based on the provided metadata.
 func (i *instance) Create(kind string, data json.RawMessage) metadata.Contract {
 	switch kind {
@@ -20,6 +20,7 @@ import (
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	metadataFactory "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/factory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata/opaque"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
 )
 
@@ -48,6 +49,11 @@ func (*Instance) Kind() string {
 	return Kind
 }
 
+// GetProvenance returns the provenance recorded in the metadata.
+func (i *Instance) GetProvenance() provenance.Contract {
+	return i.Provenance
+}
+
 // SetPublisherFactories provides for method injection of required factory to unmarshal metadata JSON.
 func (i *Instance) SetPublisherFactories(publisherFactories []metadataFactory.Contract) {
 	i.publisherFactories = publisherFactories
@@ -73,12 +79,7 @@ func (i *Instance) UnmarshalJSON(data []byte) error {
 	i.Provenance = value.Provenance
 	i.PublisherKind = value.PublisherKind
 
-	for f := range i.publisherFactories {
-		if result := i.publisherFactories[f].Create(value.PublisherKind, value.PublisherMetadata); result != nil {
-			i.PublisherMetadata = result
-			break
-		}
-	}
+	i.PublisherMetadata = opaque.Create(i.publisherFactories, value.PublisherKind, value.PublisherMetadata)
 
 	return nil
 }
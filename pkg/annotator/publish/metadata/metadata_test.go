@@ -29,3 +29,11 @@ func TestInstance_Kind(t *testing.T) {
 
 	assert.Equal(t, Kind, sut.Kind())
 }
+
+// TestSuccess_GetProvenance tests success.GetProvenance.
+func TestSuccess_GetProvenance(t *testing.T) {
+	p := test.FactoryRandomString()
+	sut := New(p, metadataStub.NewNullObject())
+
+	assert.Equal(t, p, sut.GetProvenance())
+}
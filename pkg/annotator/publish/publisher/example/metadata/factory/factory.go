@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/registry"
 	examplePublisherMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/example/metadata"
 )
 
@@ -29,6 +30,13 @@ func New() *instance {
 	return &instance{}
 }
 
+// init registers the example publisher metadata kind.
+func init() {
+	if err := registry.RegisterMetadata(registry.Publisher, examplePublisherMetadata.Kind, New()); err != nil {
+		panic(err)
+	}
+}
+
 // Create returns a contract implementation based on the provided metadata.
 func (i *instance) Create(kind string, data json.RawMessage) metadata.Contract {
 	if kind != examplePublisherMetadata.Kind {
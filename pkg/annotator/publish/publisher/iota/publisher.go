@@ -15,9 +15,11 @@
 package iota
 
 import (
-	"encoding/json"
+	"fmt"
 
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding"
+	jsonEncoding "github.com/project-alvarium/go-sdk/pkg/annotation/encoding/json"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	iotaPublisherMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/iota/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/iota/sdk"
@@ -27,25 +29,39 @@ import (
 
 // publisher is a receiver that encapsulates required dependencies.
 type publisher struct {
-	seed  string
-	depth uint64
-	mwm   uint64
-	sdk   sdk.Contract
+	seed     string
+	depth    uint64
+	mwm      uint64
+	encoding encoding.Contract
+	sdk      sdk.Contract
 }
 
 // newWithIota is a factory function that returns an initialized publisher.
-func newWithIOTA(seed string, depth uint64, mwm uint64, sdk sdk.Contract) *publisher {
+func newWithIOTA(seed string, depth uint64, mwm uint64, encoding encoding.Contract, sdk sdk.Contract) *publisher {
 	return &publisher{
-		seed:  seed,
-		depth: depth,
-		mwm:   mwm,
-		sdk:   sdk,
+		seed:     seed,
+		depth:    depth,
+		mwm:      mwm,
+		encoding: encoding,
+		sdk:      sdk,
 	}
 }
 
-// New is a factory function that returns an initialized publisher.
+// New is a factory function that returns an initialized publisher that publishes canonical JSON.
 func New(seed string, depth uint64, mwm uint64, client client.Contract) *publisher {
-	return newWithIOTA(seed, depth, mwm, iota.New(iotaPublisherMetadata.Kind, client))
+	return newWithIOTA(seed, depth, mwm, jsonEncoding.NewCanonical(), iota.New(iotaPublisherMetadata.Kind, client))
+}
+
+// NewWithEncoding is a factory function that returns an initialized publisher that publishes annotations with
+// encoding; binary encodings (e.g. CBOR) are carried in the transaction message two trytes per byte.
+func NewWithEncoding(
+	seed string,
+	depth uint64,
+	mwm uint64,
+	client client.Contract,
+	encoding encoding.Contract) *publisher {
+
+	return newWithIOTA(seed, depth, mwm, encoding, iota.NewBinary(iotaPublisherMetadata.Kind, client))
 }
 
 // SetUp is called once when the publisher is instantiated.
@@ -59,13 +75,21 @@ func (p *publisher) failureNoAnnotations() *iotaPublisherMetadata.Failure {
 	return iotaPublisherMetadata.NewFailure("IOTA Tangle publisher received 0 annotations")
 }
 
+// failureMarshal returns annotations for failure case; separated to facilitate unit testing.
+func (p *publisher) failureMarshal(message string) *iotaPublisherMetadata.Failure {
+	return iotaPublisherMetadata.NewFailure(fmt.Sprintf("Marshal returned \"%s\"", message))
+}
+
 // Publish retrieves and "publishes" annotations.
 func (p *publisher) Publish(annotations []*annotation.Instance) metadata.Contract {
 	if len(annotations) == 0 {
 		return p.failureNoAnnotations()
 	}
 
-	marshalledAnnotations, _ := json.Marshal(annotations)
+	marshalledAnnotations, err := p.encoding.Marshal(annotations)
+	if err != nil {
+		return p.failureMarshal(err.Error())
+	}
 	return p.sdk.Send(p.seed, p.depth, p.mwm, marshalledAnnotations)
 }
 
@@ -19,6 +19,8 @@ import (
 
 	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
+	jsonEncoding "github.com/project-alvarium/go-sdk/pkg/annotation/encoding/json"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
 	iotaPublisherMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/iota/metadata"
@@ -30,6 +32,8 @@ import (
 	identityProvider "github.com/project-alvarium/go-sdk/pkg/identityprovider/hash"
 	"github.com/project-alvarium/go-sdk/pkg/test"
 
+	"github.com/iotaledger/iota.go/bundle"
+	"github.com/iotaledger/iota.go/trinary"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,6 +43,7 @@ func newSUT(sdk sdk.Contract) *publisher {
 		testInternal.FactoryRandomSeedString(),
 		test.FactoryRandomUint64(),
 		test.FactoryRandomUint64(),
+		jsonEncoding.NewCanonical(),
 		sdk,
 	)
 }
@@ -56,6 +61,31 @@ func TestNew(t *testing.T) {
 	assert.IsType(t, &publisher{}, sut)
 }
 
+// TestNewWithEncoding tests publisher.NewWithEncoding.
+func TestNewWithEncoding(t *testing.T) {
+	resultTx := testInternal.FactoryRandomFixedSizeBundle(1)[0]
+	sut := NewWithEncoding(
+		testInternal.FactoryRandomSeedString(),
+		test.FactoryRandomUint64(),
+		test.FactoryRandomUint64(),
+		clientStub.New([]trinary.Hash{testInternal.FactoryRandomAddressTrytesString()}, nil, bundle.Bundle{resultTx}, nil),
+		cbor.New(),
+	)
+
+	result := sut.Publish(
+		[]*annotation.Instance{
+			annotation.New(
+				test.FactoryRandomString(),
+				identityProvider.New(sha256.New()).Derive(test.FactoryRandomByteSlice()),
+				nil,
+				metadataStub.New(test.FactoryRandomString(), test.FactoryRandomString()),
+			),
+		},
+	)
+
+	assert.Equal(t, iotaPublisherMetadata.NewSuccess(resultTx.Address, resultTx.Hash, resultTx.Tag), result)
+}
+
 // TestPublisher_SetUp tests publisher.SetUp.
 func TestPublisher_SetUp(t *testing.T) {
 	sut := New(
@@ -106,6 +136,25 @@ func TestPublisher_Publish(t *testing.T) {
 				},
 			}
 		}(),
+		func() testCase {
+			annotations := []*annotation.Instance{
+				annotation.New(
+					test.FactoryRandomString(),
+					identityProvider.New(sha256.New()).Derive(test.FactoryRandomByteSlice()),
+					nil,
+					metadataStub.New(test.FactoryRandomString(), make(chan int)),
+				),
+			}
+			return testCase{
+				name:        "marshal failure",
+				annotations: annotations,
+				sdk:         stub.New(nil),
+				expectedResult: func(sut *publisher) metadata.Contract {
+					_, err := sut.encoding.Marshal(annotations)
+					return sut.failureMarshal(err.Error())
+				},
+			}
+		}(),
 		func() testCase {
 			resultTx := testInternal.FactoryRandomFixedSizeBundle(1)[0]
 			result := iotaPublisherMetadata.NewSuccess(resultTx.Address, resultTx.Hash, resultTx.Tag)
@@ -23,24 +23,52 @@ import (
 
 	iotaAPI "github.com/iotaledger/iota.go/api"
 	"github.com/iotaledger/iota.go/bundle"
+	"github.com/iotaledger/iota.go/consts"
 	"github.com/iotaledger/iota.go/converter"
 	"github.com/iotaledger/iota.go/trinary"
 )
 
 // instance is a receiver that encapsulates required dependencies.
 type instance struct {
-	kind   string
-	client client.Contract
+	kind     string
+	client   client.Contract
+	toTrytes func(message []byte) (trinary.Trytes, error)
 }
 
-// New is a factory function that returns an initialized instance.
+// New is a factory function that returns an initialized instance that sends ASCII (e.g. JSON) messages.
 func New(kind string, client client.Contract) *instance {
 	return &instance{
-		kind:   kind,
-		client: client,
+		kind:     kind,
+		client:   client,
+		toTrytes: asciiToTrytes,
 	}
 }
 
+// NewBinary is a factory function that returns an initialized instance that sends arbitrary binary (e.g. CBOR)
+// messages.
+func NewBinary(kind string, client client.Contract) *instance {
+	return &instance{
+		kind:     kind,
+		client:   client,
+		toTrytes: bytesToTrytes,
+	}
+}
+
+// asciiToTrytes converts an ASCII message to trytes.
+func asciiToTrytes(message []byte) (trinary.Trytes, error) {
+	return converter.ASCIIToTrytes(string(message))
+}
+
+// bytesToTrytes converts a binary message to trytes, two trytes per byte; ASCII messages convert exactly as
+// converter.ASCIIToTrytes would, so existing decoders are unaffected.
+func bytesToTrytes(message []byte) (trinary.Trytes, error) {
+	trytes := make([]byte, 0, len(message)*2)
+	for _, b := range message {
+		trytes = append(trytes, consts.TryteAlphabet[b%27], consts.TryteAlphabet[b/27])
+	}
+	return trinary.Trytes(trytes), nil
+}
+
 // createTransfer is a factory function that returns an initialized instance of a Transfer.
 func (*instance) createTransfer(address trinary.Hash, message trinary.Trytes) bundle.Transfer {
 	return bundle.Transfer{
@@ -82,7 +110,7 @@ func (i *instance) Send(seed string, depth uint64, mwm uint64, annotations []byt
 		return i.getNewAddressError(err.Error())
 	}
 
-	messageTrytes, err := converter.ASCIIToTrytes(string(annotations))
+	messageTrytes, err := i.toTrytes(annotations)
 	if err != nil {
 		return i.convertToTrytesError(err.Error())
 	}
@@ -27,8 +27,10 @@ import (
 
 	"github.com/iotaledger/iota.go/bundle"
 	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/converter"
 	"github.com/iotaledger/iota.go/trinary"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // newSUT returns a new system under test.
@@ -147,3 +149,74 @@ func TestInstance_Send(t *testing.T) {
 		})
 	}
 }
+
+// TestInstance_SendBinary tests instance.Send with a binary message.
+func TestInstance_SendBinary(t *testing.T) {
+	res := testInternal.FactoryRandomFixedSizeBundle(1)
+	sut := NewBinary(
+		test.FactoryRandomString(),
+		stub.New([]trinary.Hash{testInternal.FactoryRandomAddressTrytesString()}, nil, res, nil),
+	)
+
+	result := sut.Send(
+		testInternal.FactoryRandomSeedString(),
+		test.FactoryRandomUint64(),
+		test.FactoryRandomUint64(),
+		[]byte("\u2000"),
+	)
+
+	assert.Equal(
+		t,
+		testInternal.Marshal(t, iotaPublisherMetadata.NewSuccess(res[0].Address, res[0].Hash, res[0].Tag)),
+		testInternal.Marshal(t, result),
+	)
+}
+
+// TestBytesToTrytes tests bytesToTrytes.
+func TestBytesToTrytes(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "ascii matches converter",
+			test: func(t *testing.T) {
+				message := test.FactoryRandomString()
+				expected, err := converter.ASCIIToTrytes(message)
+				require.NoError(t, err)
+
+				result, err := bytesToTrytes([]byte(message))
+
+				assert.NoError(t, err)
+				assert.Equal(t, expected, result)
+			},
+		},
+		{
+			name: "every byte value",
+			test: func(t *testing.T) {
+				message := make([]byte, 256)
+				for b := range message {
+					message[b] = byte(b)
+				}
+
+				result, err := bytesToTrytes(message)
+
+				require.NoError(t, err)
+				assert.NoError(t, trinary.ValidTrytes(result))
+				decoded, err := converter.TrytesToASCII(result)
+				require.NoError(t, err)
+				runes := []rune(decoded)
+				require.Len(t, runes, len(message))
+				for b := range message {
+					assert.Equal(t, rune(message[b]), runes[b])
+				}
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
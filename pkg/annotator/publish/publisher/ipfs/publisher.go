@@ -15,33 +15,42 @@
 package ipfs
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	"github.com/project-alvarium/go-sdk/internal/pkg/ipfs/sdk"
 	"github.com/project-alvarium/go-sdk/internal/pkg/ipfs/sdk/ipfs"
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding"
+	jsonEncoding "github.com/project-alvarium/go-sdk/pkg/annotation/encoding/json"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	ipfsPublisherMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/ipfs/metadata"
 )
 
 // publisher is a receiver that encapsulates required dependencies.
 type publisher struct {
-	url string
-	sdk sdk.Contract
+	url      string
+	encoding encoding.Contract
+	sdk      sdk.Contract
 }
 
 // newWithIPFS is a factory function that returns an initialized publisher.
-func newWithIPFS(url string, sdk sdk.Contract) *publisher {
+func newWithIPFS(url string, encoding encoding.Contract, sdk sdk.Contract) *publisher {
 	return &publisher{
-		url: url,
-		sdk: sdk,
+		url:      url,
+		encoding: encoding,
+		sdk:      sdk,
 	}
 }
 
-// New is a factory function that returns an initialized publisher.
+// New is a factory function that returns an initialized publisher that publishes canonical JSON.
 func New(url string) *publisher {
-	return newWithIPFS(url, ipfs.New())
+	return NewWithEncoding(url, jsonEncoding.NewCanonical())
+}
+
+// NewWithEncoding is a factory function that returns an initialized publisher that publishes annotations with encoding.
+func NewWithEncoding(url string, encoding encoding.Contract) *publisher {
+	return newWithIPFS(url, encoding, ipfs.New())
 }
 
 // SetUp is called once when the publisher is instantiated.
@@ -55,10 +64,25 @@ func (p *publisher) failureAdd(message string) *ipfsPublisherMetadata.Failure {
 	return ipfsPublisherMetadata.NewFailure(fmt.Sprintf("Add returned \"`%s\"", message))
 }
 
+// failureMarshal returns annotations for failure case; separated to facilitate unit testing.
+func (p *publisher) failureMarshal(message string) *ipfsPublisherMetadata.Failure {
+	return ipfsPublisherMetadata.NewFailure(fmt.Sprintf("Marshal returned \"`%s\"", message))
+}
+
 // Publish retrieves and "publishes" annotations.
 func (p *publisher) Publish(annotations []*annotation.Instance) metadata.Contract {
-	marshaledAnnotations, _ := json.Marshal(annotations)
-	cid, err := p.sdk.Add(p.url, marshaledAnnotations)
+	return p.PublishContext(context.Background(), annotations)
+}
+
+// PublishContext retrieves and "publishes" annotations within ctx; the request to the IPFS instance is abandoned
+// (and reported as a failure) when ctx is done.
+func (p *publisher) PublishContext(ctx context.Context, annotations []*annotation.Instance) metadata.Contract {
+	marshaledAnnotations, err := p.encoding.Marshal(annotations)
+	if err != nil {
+		return p.failureMarshal(err.Error())
+	}
+
+	cid, err := p.sdk.AddContext(ctx, p.url, marshaledAnnotations)
 	if err != nil {
 		return p.failureAdd(err.Error())
 	}
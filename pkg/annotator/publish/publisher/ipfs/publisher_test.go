@@ -15,14 +15,19 @@
 package ipfs
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/project-alvarium/go-sdk/internal/pkg/ipfs/sdk"
 	"github.com/project-alvarium/go-sdk/internal/pkg/ipfs/sdk/stub"
 	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
+	jsonEncoding "github.com/project-alvarium/go-sdk/pkg/annotation/encoding/json"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/metadata"
 	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
 	ipfsPublisherMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/ipfs/metadata"
@@ -33,7 +38,7 @@ import (
 
 // newSUT returns a new system under test.
 func newSUT(url string, sdk sdk.Contract) *publisher {
-	return newWithIPFS(url, sdk)
+	return newWithIPFS(url, jsonEncoding.NewCanonical(), sdk)
 }
 
 // TestNew tests publisher.New.
@@ -92,6 +97,25 @@ func TestPublisher_Publish(t *testing.T) {
 				},
 			}
 		}(),
+		func() testCase {
+			annotations := []*annotation.Instance{
+				annotation.New(
+					test.FactoryRandomString(),
+					identityProvider.New(sha256.New()).Derive(test.FactoryRandomByteSlice()),
+					nil,
+					metadataStub.New(test.FactoryRandomString(), make(chan int)),
+				),
+			}
+			return testCase{
+				name:        "marshal failure",
+				annotations: annotations,
+				sdk:         stub.New(test.FactoryRandomString(), nil),
+				expectedResult: func(sut *publisher) metadata.Contract {
+					_, err := sut.encoding.Marshal(annotations)
+					return sut.failureMarshal(err.Error())
+				},
+			}
+		}(),
 		func() testCase {
 			cid := test.FactoryRandomString()
 			return testCase{
@@ -183,6 +207,62 @@ func TestPublisher_Publish(t *testing.T) {
 	}
 }
 
+// TestPublisher_PublishWithEncoding tests publisher.Publish adds annotations in the configured encoding.
+func TestPublisher_PublishWithEncoding(t *testing.T) {
+	type testCase struct {
+		name     string
+		encoding encoding.Contract
+	}
+
+	cases := []testCase{
+		{
+			name:     "canonical json",
+			encoding: jsonEncoding.NewCanonical(),
+		},
+		{
+			name:     "cbor",
+			encoding: cbor.New(),
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				cid := test.FactoryRandomString()
+				ipfs := stub.New(cid, nil)
+				annotations := []*annotation.Instance{
+					annotation.New(
+						test.FactoryRandomString(),
+						identityProvider.New(sha256.New()).Derive(test.FactoryRandomByteSlice()),
+						nil,
+						metadataStub.New(test.FactoryRandomString(), test.FactoryRandomString()),
+					),
+				}
+				expected, err := cases[i].encoding.Marshal(annotations)
+				require.NoError(t, err)
+				sut := newWithIPFS(test.FactoryRandomString(), cases[i].encoding, ipfs)
+
+				result := sut.Publish(annotations)
+
+				assert.Equal(t, ipfsPublisherMetadata.NewSuccess(cid), result)
+				assert.Equal(t, expected, ipfs.CapturedAnnotations)
+			},
+		)
+	}
+}
+
+// TestPublisher_PublishContext tests publisher.PublishContext.
+func TestPublisher_PublishContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sut := newSUT(test.FactoryRandomString(), stub.New(test.FactoryRandomString(), nil))
+
+	result := sut.PublishContext(ctx, []*annotation.Instance{})
+
+	assert.Equal(t, sut.failureAdd(context.Canceled.Error()), result)
+}
+
 // TestPublisher_Kind tests publisher.Kind.
 func TestPublisher_Kind(t *testing.T) {
 	sut := newSUT(test.FactoryRandomString(), stub.New(test.FactoryRandomString(), nil))
@@ -14,7 +14,10 @@
 
 package stub
 
-import "github.com/project-alvarium/go-sdk/pkg/status"
+import (
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
 
 // annotator is a receiver that encapsulates required dependencies.
 type annotator struct {
@@ -56,3 +59,13 @@ func (a *annotator) Create(_ []byte) *status.Contract {
 func (a *annotator) Mutate(_, _ []byte) *status.Contract {
 	return a.result
 }
+
+// Derive evaluates data derived from multiple sources.
+func (a *annotator) Derive(_ [][]byte, _ []byte) *status.Contract {
+	return a.result
+}
+
+// Transfer evaluates data whose ownership has been transferred.
+func (a *annotator) Transfer(_ []byte, _ identity.Contract) *status.Contract {
+	return a.result
+}
@@ -0,0 +1,266 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// canonical implements the JSON Canonicalization Scheme (JCS) defined by RFC 8785.
+//
+// Canonical JSON has no insignificant whitespace, object members sorted by the UTF-16 code units of their names,
+// strings escaped minimally, and numbers serialized as ECMAScript does.  Equal values therefore serialize to equal
+// bytes, which is what hashes and signatures over JSON require.  Input must be I-JSON (RFC 7493): object member
+// names must be unique and numbers must be representable as IEEE 754 double precision values.
+package canonical
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Marshal returns the canonical JSON encoding of v.
+func Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Transform(data)
+}
+
+// Transform returns the canonical form of JSON data.
+func Transform(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := parse(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("canonical: unexpected data after top-level value")
+	}
+
+	var b bytes.Buffer
+	if err := encode(&b, value); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// member is a decoded object member.
+type member struct {
+	name  string
+	value interface{}
+}
+
+// parse decodes the next JSON value; objects are decoded as []member and arrays as []interface{}.
+func parse(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			members := make([]member, 0)
+			names := make(map[string]struct{})
+			for decoder.More() {
+				token, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				name := token.(string)
+				if _, exists := names[name]; exists {
+					return nil, fmt.Errorf("canonical: duplicate member name %q", name)
+				}
+				names[name] = struct{}{}
+				value, err := parse(decoder)
+				if err != nil {
+					return nil, err
+				}
+				members = append(members, member{name: name, value: value})
+			}
+			if _, err := decoder.Token(); err != nil {
+				return nil, err
+			}
+			return members, nil
+		case '[':
+			elements := make([]interface{}, 0)
+			for decoder.More() {
+				value, err := parse(decoder)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, value)
+			}
+			if _, err := decoder.Token(); err != nil {
+				return nil, err
+			}
+			return elements, nil
+		}
+		return nil, fmt.Errorf("canonical: unexpected delimiter %v", t)
+	default:
+		return token, nil
+	}
+}
+
+// encode writes the canonical encoding of a value returned by parse.
+func encode(b *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case string:
+		encodeString(b, v)
+	case json.Number:
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return fmt.Errorf("canonical: number %s is not representable: %v", v, err)
+		}
+		s, err := formatNumber(f)
+		if err != nil {
+			return err
+		}
+		b.WriteString(s)
+	case []interface{}:
+		b.WriteByte('[')
+		for e := range v {
+			if e > 0 {
+				b.WriteByte(',')
+			}
+			if err := encode(b, v[e]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case []member:
+		keys := make([][]uint16, len(v))
+		for m := range v {
+			keys[m] = utf16.Encode([]rune(v[m].name))
+		}
+		order := make([]int, len(v))
+		for m := range order {
+			order[m] = m
+		}
+		sort.Slice(order, func(x, y int) bool { return less(keys[order[x]], keys[order[y]]) })
+
+		b.WriteByte('{')
+		for o := range order {
+			if o > 0 {
+				b.WriteByte(',')
+			}
+			encodeString(b, v[order[o]].name)
+			b.WriteByte(':')
+			if err := encode(b, v[order[o]].value); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	default:
+		return fmt.Errorf("canonical: unexpected value %T", value)
+	}
+	return nil
+}
+
+// less returns whether UTF-16 code unit sequence x sorts before y.
+func less(x, y []uint16) bool {
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if x[i] != y[i] {
+			return x[i] < y[i]
+		}
+	}
+	return len(x) < len(y)
+}
+
+// encodeString writes s as a JSON string, escaping only what RFC 8785 requires.
+func encodeString(b *bytes.Buffer, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(b, `\u%04x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+}
+
+// formatNumber returns f serialized as ECMAScript's Number.prototype.toString does (ECMA-262 7.1.12.1); NaN and
+// infinities are not valid JSON and return an error.
+func formatNumber(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("canonical: %v is not a valid JSON number", f)
+	}
+	if f == 0 {
+		return "0", nil
+	}
+
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// f = d1.d2...dk * 10^e using the shortest digits that round-trip; ECMA-262 positions the point at n = e+1.
+	shortest := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exponent := shortest[:strings.IndexByte(shortest, 'e')], shortest[strings.IndexByte(shortest, 'e')+1:]
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, err := strconv.Atoi(exponent)
+	if err != nil {
+		return "", err
+	}
+	n, k := e+1, len(digits)
+
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k), nil
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:], nil
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits, nil
+	}
+
+	exponentSign := "+"
+	if n-1 < 0 {
+		exponentSign = "-"
+	}
+	exponent = strconv.Itoa(int(math.Abs(float64(n - 1))))
+	if k == 1 {
+		return sign + digits + "e" + exponentSign + exponent, nil
+	}
+	return sign + digits[:1] + "." + digits[1:] + "e" + exponentSign + exponent, nil
+}
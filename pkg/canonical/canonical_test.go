@@ -0,0 +1,178 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package canonical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFormatNumber tests formatNumber against the IEEE 754 test vectors in RFC 8785 appendix B.
+func TestFormatNumber(t *testing.T) {
+	type testCase struct {
+		bits     uint64
+		expected string
+	}
+
+	cases := []testCase{
+		{bits: 0x0000000000000000, expected: "0"},
+		{bits: 0x8000000000000000, expected: "0"},
+		{bits: 0x0000000000000001, expected: "5e-324"},
+		{bits: 0x8000000000000001, expected: "-5e-324"},
+		{bits: 0x7fefffffffffffff, expected: "1.7976931348623157e+308"},
+		{bits: 0xffefffffffffffff, expected: "-1.7976931348623157e+308"},
+		{bits: 0x4340000000000000, expected: "9007199254740992"},
+		{bits: 0xc340000000000000, expected: "-9007199254740992"},
+		{bits: 0x4430000000000000, expected: "295147905179352830000"},
+		{bits: 0x44b52d02c7e14af5, expected: "9.999999999999997e+22"},
+		{bits: 0x44b52d02c7e14af6, expected: "1e+23"},
+		{bits: 0x44b52d02c7e14af7, expected: "1.0000000000000001e+23"},
+		{bits: 0x444b1ae4d6e2ef4e, expected: "999999999999999700000"},
+		{bits: 0x444b1ae4d6e2ef4f, expected: "999999999999999900000"},
+		{bits: 0x444b1ae4d6e2ef50, expected: "1e+21"},
+		{bits: 0x3eb0c6f7a0b5ed8c, expected: "9.999999999999997e-7"},
+		{bits: 0x3eb0c6f7a0b5ed8d, expected: "0.000001"},
+		{bits: 0x41b3de4355555553, expected: "333333333.3333332"},
+		{bits: 0x41b3de4355555554, expected: "333333333.33333325"},
+		{bits: 0x41b3de4355555555, expected: "333333333.3333333"},
+		{bits: 0x41b3de4355555556, expected: "333333333.3333334"},
+		{bits: 0x41b3de4355555557, expected: "333333333.33333343"},
+		{bits: 0xbecbf647612f3696, expected: "-0.0000033333333333333333"},
+		{bits: 0x43143ff3c1cb0959, expected: "1424953923781206.2"},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].expected,
+			func(t *testing.T) {
+				result, err := formatNumber(math.Float64frombits(cases[i].bits))
+
+				assert.NoError(t, err)
+				assert.Equal(t, cases[i].expected, result)
+			},
+		)
+	}
+
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err := formatNumber(f)
+
+		assert.Error(t, err)
+	}
+}
+
+// TestTransform tests Transform.
+func TestTransform(t *testing.T) {
+	type testCase struct {
+		name     string
+		data     string
+		expected string
+	}
+
+	cases := []testCase{
+		{
+			name: "RFC 8785 section 3.2.2",
+			data: `{
+				"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+				"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+				"literals": [null, true, false]
+			}`,
+			expected: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+				`"string":"` + "€" + `$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			name: "RFC 8785 section 3.2.3",
+			data: `{
+				"€": "Euro Sign",
+				"\r": "Carriage Return",
+				"דּ": "Hebrew Letter Dalet With Dagesh",
+				"1": "One",
+				"😀": "Emoji: Grinning Face",
+				"\u0080": "Control",
+				"ö": "Latin Small Letter O With Diaeresis"
+			}`,
+			expected: `{"\r":"Carriage Return","1":"One","` + "\u0080" + `":"Control",` +
+				`"` + "ö" + `":"Latin Small Letter O With Diaeresis","` + "€" + `":"Euro Sign",` +
+				`"` + "\U0001f600" + `":"Emoji: Grinning Face","` + "דּ" + `":"Hebrew Letter Dalet With Dagesh"}`,
+		},
+		{
+			name:     "nested objects and arrays",
+			data:     ` { "b" : [ { "d" : 1 , "c" : 2 } , [ ] , { } ] , "a" : "<&>" } `,
+			expected: `{"a":"<&>","b":[{"c":2,"d":1},[],{}]}`,
+		},
+		{
+			name:     "control characters",
+			data:     `"\u0000\u0008\u0009\u001f\u007f "`,
+			expected: `"\u0000\b\t\u001f` + "\u007f " + `"`,
+		},
+		{
+			name:     "scalar",
+			data:     `1.0`,
+			expected: `1`,
+		},
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				result, err := Transform([]byte(cases[i].data))
+
+				assert.NoError(t, err)
+				assert.Equal(t, cases[i].expected, string(result))
+			},
+		)
+	}
+}
+
+// TestTransform_Invalid tests Transform with input that cannot be canonicalized.
+func TestTransform_Invalid(t *testing.T) {
+	for _, data := range []string{
+		``,
+		`{`,
+		`{"a":1,"a":2}`,
+		`[1e400]`,
+		`1 2`,
+	} {
+		t.Run(
+			data,
+			func(t *testing.T) {
+				result, err := Transform([]byte(data))
+
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		)
+	}
+}
+
+// TestMarshal tests Marshal.
+func TestMarshal(t *testing.T) {
+	type value struct {
+		Z string            `json:"z"`
+		A map[string]uint64 `json:"a"`
+		M interface{}       `json:"m"`
+	}
+
+	result, err := Marshal(value{Z: "<z>", A: map[string]uint64{"y": 10, "x": 1e19}, M: []float64{0.1, -0}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":{"x":10000000000000000000,"y":10},"m":[0.1,0],"z":"<z>"}`, string(result))
+
+	_, err = Marshal(math.NaN())
+
+	assert.Error(t, err)
+}
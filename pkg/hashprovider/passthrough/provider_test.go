@@ -31,7 +31,7 @@ func newSUT(name string) *provider {
 func TestProvider_Derive(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		t.Run(
-			"variation "+string(i),
+			"variation "+string(rune(i)),
 			func(t *testing.T) {
 				data := test.FactoryRandomByteSlice()
 				sut := newSUT(test.FactoryRandomString())
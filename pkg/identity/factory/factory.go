@@ -17,8 +17,10 @@ package factory
 import (
 	"encoding/json"
 
+	"github.com/project-alvarium/go-sdk/pkg/annotation/registry"
 	"github.com/project-alvarium/go-sdk/pkg/identity"
 	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/identity/principal"
 )
 
 // instance is a receiver that encapsulates required dependencies.
@@ -29,6 +31,15 @@ func New() *instance {
 	return &instance{}
 }
 
+// init registers the hash and principal identity kinds.
+func init() {
+	for _, kind := range []string{identityHash.Kind, principal.Kind} {
+		if err := registry.RegisterIdentity(kind, New()); err != nil {
+			panic(err)
+		}
+	}
+}
+
 // Create returns a contract implementation based on the provided metadata.
 func (i *instance) Create(kind string, data json.RawMessage) identity.Contract {
 	switch kind {
@@ -41,6 +52,15 @@ func (i *instance) Create(kind string, data json.RawMessage) identity.Contract {
 		if err := json.Unmarshal(data, &concrete); err == nil {
 			return &concrete
 		}
+	case principal.Kind:
+		if string(data) == "null" {
+			return nil
+		}
+
+		var concrete principal.Identity
+		if err := json.Unmarshal(data, &concrete); err == nil {
+			return &concrete
+		}
 	}
 	return nil
 }
@@ -19,6 +19,7 @@ import (
 
 	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
 	identityHash "github.com/project-alvarium/go-sdk/pkg/identity/hash"
+	"github.com/project-alvarium/go-sdk/pkg/identity/principal"
 	"github.com/project-alvarium/go-sdk/pkg/test"
 
 	"github.com/stretchr/testify/assert"
@@ -60,6 +61,29 @@ func TestInstance_Create(t *testing.T) {
 				assert.Equal(t, value, result.Binary())
 			},
 		},
+		{
+			name: "Valid (principal)",
+			test: func(t *testing.T) {
+				sut := newSUT()
+				name := test.FactoryRandomString()
+
+				result := sut.Create(principal.Kind, []byte(testInternal.Marshal(t, principal.New(name))))
+
+				assert.NotNil(t, result)
+				assert.IsType(t, &principal.Identity{}, result)
+				assert.Equal(t, name, result.Printable())
+			},
+		},
+		{
+			name: "Null (principal)",
+			test: func(t *testing.T) {
+				sut := newSUT()
+
+				result := sut.Create(principal.Kind, []byte("null"))
+
+				assert.Nil(t, result)
+			},
+		},
 	}
 
 	for i := range cases {
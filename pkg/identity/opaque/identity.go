@@ -0,0 +1,71 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+// opaque implements an identity of a kind the decoding identity factory does not recognize (for example, one
+// introduced by a newer SDK release or a third party).
+//
+// The identity retains its kind and JSON and re-marshals to the same bytes, so annotations containing it pass through
+// stores and publishers unchanged.  Retained JSON is reproduced as encoding/json compacts it; JSON that is already
+// compact (and, as encoding/json would, escapes '<', '>', and '&') is reproduced byte-for-byte.
+package opaque
+
+import (
+	"encoding/json"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
+)
+
+// Identity is an identity of an unrecognized kind.
+type Identity struct {
+	kind string
+	data json.RawMessage
+}
+
+// New is a factory function that returns an initialized Identity.
+func New(kind string, data json.RawMessage) *Identity {
+	return &Identity{
+		kind: kind,
+		data: data,
+	}
+}
+
+// Binary returns a unique key based on identity used within the SDK.
+func (i *Identity) Binary() []byte {
+	return i.data
+}
+
+// Printable returns a unique key based on identity used within the SDK.
+func (i *Identity) Printable() string {
+	return string(i.data)
+}
+
+// Kind returns the type of concrete implementation.
+func (i *Identity) Kind() string {
+	return i.kind
+}
+
+// Raw returns the identity's JSON.
+func (i *Identity) Raw() json.RawMessage {
+	return i.data
+}
+
+// MarshalJSON returns the identity's retained JSON.
+func (i *Identity) MarshalJSON() ([]byte, error) {
+	return i.data, nil
+}
+
+// MarshalCBOR returns a CBOR data item embedding the identity's retained JSON so it survives CBOR encoding unchanged.
+func (i *Identity) MarshalCBOR() ([]byte, error) {
+	return cbor.EmbedJSON(i.data)
+}
@@ -0,0 +1,54 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package opaque
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotation/encoding/cbor"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIdentity tests Identity.
+func TestIdentity(t *testing.T) {
+	kind := test.FactoryRandomString()
+	data := json.RawMessage(`{"value":"` + test.FactoryRandomString() + `"}`)
+
+	sut := New(kind, data)
+
+	assert.Equal(t, kind, sut.Kind())
+	assert.Equal(t, data, sut.Raw())
+	assert.Equal(t, []byte(data), sut.Binary())
+	assert.Equal(t, string(data), sut.Printable())
+	result, err := json.Marshal(sut)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(result))
+}
+
+// TestIdentity_MarshalCBOR tests Identity.MarshalCBOR.
+func TestIdentity_MarshalCBOR(t *testing.T) {
+	data := json.RawMessage(`{"z":"` + test.FactoryRandomString() + `","a":[1.50,null]}`)
+
+	encoded, err := cbor.Marshal(New(test.FactoryRandomString(), data))
+	require.NoError(t, err)
+	result, err := cbor.ToJSON(encoded)
+
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(result))
+}
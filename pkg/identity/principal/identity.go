@@ -0,0 +1,44 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package principal
+
+const Kind = "principal"
+
+// Identity identifies an entity (for example, a person, organization, or node) that authors or owns data.
+type Identity struct {
+	Name string `json:"name"`
+}
+
+// New is a factory function that returns an initialized Identity.
+func New(name string) *Identity {
+	return &Identity{
+		Name: name,
+	}
+}
+
+// Binary returns a unique key based on identity used within the SDK.
+func (i *Identity) Binary() []byte {
+	return []byte(i.Name)
+}
+
+// Printable returns a unique key based on identity used within the SDK.
+func (i *Identity) Printable() string {
+	return i.Name
+}
+
+// Kind returns the type of concrete implementation.
+func (*Identity) Kind() string {
+	return Kind
+}
@@ -18,8 +18,10 @@ package sdk
 func (sdk *instance) Close() {
 	if !sdk.closed {
 		sdk.closed = true
-		for i := range sdk.annotators {
-			sdk.annotators[i].TearDown()
+		for i := range sdk.stages {
+			for j := range sdk.stages[i] {
+				sdk.stages[i][j].TearDown()
+			}
 		}
 	}
 }
@@ -14,17 +14,28 @@
 
 package sdk
 
-import "github.com/project-alvarium/go-sdk/pkg/status"
+import (
+	"context"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotator"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
 
 // Create calls the Create method on each registered annotator and returns a set of status results.
 func (sdk *instance) Create(data []byte) []*status.Contract {
+	return sdk.CreateContext(context.Background(), data)
+}
+
+// CreateContext calls the CreateContext method on each registered annotator and returns a set of status results;
+// annotators reached after ctx is done return a status.Cancelled result.
+func (sdk *instance) CreateContext(ctx context.Context, data []byte) []*status.Contract {
 	if sdk.closed {
 		return nil
 	}
 
-	result := make([]*status.Contract, 0)
-	for i := range sdk.annotators {
-		result = append(result, sdk.annotators[i].Create(data))
-	}
-	return result
+	return sdk.evaluate(
+		func(a annotator.ContextContract) (*status.Contract, bool) {
+			return a.CreateContext(ctx, data), true
+		},
+	)
 }
@@ -15,8 +15,10 @@
 package sdk
 
 import (
+	"context"
 	"crypto"
 	"testing"
+	"time"
 
 	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
 	testMetadata "github.com/project-alvarium/go-sdk/internal/pkg/test/metadata"
@@ -35,6 +37,7 @@ import (
 	"github.com/project-alvarium/go-sdk/pkg/test"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestInstance_Create tests instance.Create.
@@ -160,3 +163,101 @@ func TestInstance_Create(t *testing.T) {
 		)
 	}
 }
+
+// blocking is an annotator whose Create blocks until release is closed.
+type blocking struct {
+	annotator.Contract
+	release chan struct{}
+}
+
+// Create evaluates newly-created data.
+func (b *blocking) Create(data []byte) *status.Contract {
+	<-b.release
+	return b.Contract.Create(data)
+}
+
+// TestInstance_CreateContext tests instance.CreateContext.
+func TestInstance_CreateContext(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "Nil after close (stub)",
+			test: func(t *testing.T) {
+				sut := newSUT([]annotator.Contract{stub.New()})
+				sut.Close()
+
+				assert.Nil(t, sut.CreateContext(context.Background(), test.FactoryRandomByteSlice()))
+			},
+		},
+		{
+			name: "Completed (stub)",
+			test: func(t *testing.T) {
+				result := status.New(test.FactoryRandomString(), status.Success)
+				sut := newSUT([]annotator.Contract{stub.NewWithResult(result)})
+				defer sut.Close()
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				assert.Equal(t, []*status.Contract{result}, sut.CreateContext(ctx, test.FactoryRandomByteSlice()))
+			},
+		},
+		{
+			name: "Done beforehand (stub and pki)",
+			test: func(t *testing.T) {
+				prov := test.FactoryRandomString()
+				idProvider := identityProvider.New(sha256.New())
+				persistence := memory.New()
+				data := test.FactoryRandomByteSlice()
+				h := sha256.New()
+				s := signpkcs1v15.New(crypto.SHA256, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, h)
+				sut := newSUT(
+					[]annotator.Contract{
+						stub.NewWithResult(status.New(test.FactoryRandomString(), status.Success)),
+						pki.New(prov, ulid.New(), idProvider, persistence, s),
+					},
+				)
+				defer sut.Close()
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				result := sut.CreateContext(ctx, data)
+
+				assert.Equal(
+					t,
+					[]*status.Contract{status.New(nil, status.Cancelled), status.New(prov, status.Cancelled)},
+					result,
+				)
+				_, findResult := persistence.FindByIdentity(idProvider.Derive(data))
+				assert.Equal(t, status.NotFound, findResult)
+			},
+		},
+		{
+			name: "Deadline exceeded (blocking stub)",
+			test: func(t *testing.T) {
+				b := &blocking{Contract: stub.New(), release: make(chan struct{})}
+				defer close(b.release)
+				sut := newSUT(
+					[]annotator.Contract{b, stub.NewWithResult(status.New(test.FactoryRandomString(), status.Success))},
+				)
+				defer sut.Close()
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				result := sut.CreateContext(ctx, test.FactoryRandomByteSlice())
+
+				require.Len(t, result, 2)
+				for i := range result {
+					assert.Equal(t, status.Cancelled, result[i].Value)
+				}
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
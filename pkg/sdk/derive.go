@@ -0,0 +1,41 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package sdk
+
+import (
+	"context"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotator"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// Derive calls the Derive method on each registered annotator and returns a set of status results.
+func (sdk *instance) Derive(sources [][]byte, data []byte) []*status.Contract {
+	return sdk.DeriveContext(context.Background(), sources, data)
+}
+
+// DeriveContext calls the DeriveContext method on each registered annotator and returns a set of status results;
+// annotators reached after ctx is done return a status.Cancelled result.
+func (sdk *instance) DeriveContext(ctx context.Context, sources [][]byte, data []byte) []*status.Contract {
+	if sdk.closed {
+		return nil
+	}
+
+	return sdk.evaluate(
+		func(a annotator.ContextContract) (*status.Contract, bool) {
+			return a.DeriveContext(ctx, sources, data), true
+		},
+	)
+}
@@ -0,0 +1,211 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package sdk
+
+import (
+	"context"
+	"crypto"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	testMetadata "github.com/project-alvarium/go-sdk/internal/pkg/test/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotation"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider/ulid"
+	"github.com/project-alvarium/go-sdk/pkg/annotator"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/provenance"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/stub"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	identityProvider "github.com/project-alvarium/go-sdk/pkg/identityprovider/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInstance_Derive tests instance.Derive.
+func TestInstance_Derive(t *testing.T) {
+	type testCase struct {
+		name           string
+		provenance     provenance.Contract
+		annotator      annotator.Contract
+		sources        [][]byte
+		data           []byte
+		preCondition   func(t *testing.T, sut *instance)
+		postCondition  func(t *testing.T, sut *instance)
+		expectedStatus status.Value
+	}
+
+	cases := []testCase{
+		func() testCase {
+			prov := test.FactoryRandomString()
+			data := test.FactoryRandomByteSlice()
+			s := status.Success
+			return testCase{
+				name:         "Nil after close (stub)",
+				provenance:   prov,
+				annotator:    stub.NewWithResult(status.New(prov, s)),
+				sources:      [][]byte{test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice()},
+				data:         data,
+				preCondition: func(t *testing.T, sut *instance) {},
+				postCondition: func(t *testing.T, sut *instance) {
+					sut.Close()
+					assert.Nil(t, sut.Derive([][]byte{test.FactoryRandomByteSlice()}, data))
+				},
+				expectedStatus: s,
+			}
+		}(),
+		func() testCase {
+			prov := test.FactoryRandomString()
+			persistence := memory.New()
+			h := sha256.New()
+			idProvider := identityProvider.New(h)
+			data1 := test.FactoryRandomByteSlice()
+			id1 := idProvider.Derive(data1)
+			data2 := test.FactoryRandomByteSlice()
+			id2 := idProvider.Derive(data2)
+			data3 := test.FactoryRandomByteSlice()
+			id3 := idProvider.Derive(data3)
+			publicKey := testInternal.ValidPublicKey
+			s := signpkcs1v15.New(crypto.SHA256, testInternal.ValidPrivateKey, publicKey, h)
+			idSignature1, dataSignature1 := s.Sign(id1.Binary(), data1)
+			idSignature2, dataSignature2 := s.Sign(id2.Binary(), data2)
+			idSignature3, dataSignature3 := s.Sign(id3.Binary(), data3)
+			return testCase{
+				name:       "Derive From Two Sources",
+				provenance: prov,
+				annotator:  pki.New(prov, ulid.New(), idProvider, persistence, s),
+				sources:    [][]byte{data1, data2},
+				data:       data3,
+				preCondition: func(t *testing.T, sut *instance) {
+					for _, data := range [][]byte{data1, data2} {
+						assert.Equal(
+							t,
+							testInternal.Marshal(t, []*status.Contract{status.New(prov, status.Success)}),
+							testInternal.Marshal(t, sut.Create(data)),
+						)
+					}
+				},
+				postCondition: func(t *testing.T, sut *instance) {
+					testMetadata.Assert(
+						t,
+						[]*annotation.Instance{
+							annotation.NewDerived(
+								test.FactoryRandomString(),
+								id3,
+								[]identity.Contract{id1, id2},
+								metadata.New(prov, idSignature3, dataSignature3, publicKey, s.Metadata()),
+							),
+							annotation.New(
+								test.FactoryRandomString(),
+								id1,
+								nil,
+								metadata.New(prov, idSignature1, dataSignature1, publicKey, s.Metadata()),
+							),
+							annotation.New(
+								test.FactoryRandomString(),
+								id2,
+								nil,
+								metadata.New(prov, idSignature2, dataSignature2, publicKey, s.Metadata()),
+							),
+						},
+						id3,
+						persistence,
+					)
+				},
+				expectedStatus: status.Success,
+			}
+		}(),
+	}
+
+	for i := range cases {
+		t.Run(
+			cases[i].name,
+			func(t *testing.T) {
+				sut := newSUT([]annotator.Contract{cases[i].annotator})
+				cases[i].preCondition(t, sut)
+
+				result := sut.Derive(cases[i].sources, cases[i].data)
+
+				assert.Equal(
+					t,
+					testInternal.Marshal(
+						t,
+						[]*status.Contract{status.New(cases[i].provenance, cases[i].expectedStatus)},
+					),
+					testInternal.Marshal(t, result),
+				)
+				cases[i].postCondition(t, sut)
+				sut.Close()
+			},
+		)
+	}
+}
+
+// TestInstance_DeriveContext tests instance.DeriveContext.
+func TestInstance_DeriveContext(t *testing.T) {
+	sources, data := [][]byte{test.FactoryRandomByteSlice()}, test.FactoryRandomByteSlice()
+
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "Nil after close (stub)",
+			test: func(t *testing.T) {
+				sut := newSUT([]annotator.Contract{stub.New()})
+				sut.Close()
+
+				result := sut.DeriveContext(context.Background(), sources, data)
+
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "Completed (stub)",
+			test: func(t *testing.T) {
+				result := status.New(test.FactoryRandomString(), status.Success)
+				sut := newSUT([]annotator.Contract{stub.NewWithResult(result)})
+				defer sut.Close()
+
+				assert.Equal(t, []*status.Contract{result}, sut.DeriveContext(context.Background(), sources, data))
+			},
+		},
+		{
+			name: "Done beforehand (stub)",
+			test: func(t *testing.T) {
+				a := stub.NewWithResult(status.New(test.FactoryRandomString(), status.Success))
+				sut := newSUT([]annotator.Contract{a})
+				defer sut.Close()
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				result := sut.DeriveContext(ctx, sources, data)
+
+				assert.Equal(t, []*status.Contract{status.New(nil, status.Cancelled)}, result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
@@ -14,17 +14,28 @@
 
 package sdk
 
-import "github.com/project-alvarium/go-sdk/pkg/status"
+import (
+	"context"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotator"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
 
 // Mutate calls the Mutate method on each registered annotator and returns a set of status results.
 func (sdk *instance) Mutate(oldData, newData []byte) []*status.Contract {
+	return sdk.MutateContext(context.Background(), oldData, newData)
+}
+
+// MutateContext calls the MutateContext method on each registered annotator and returns a set of status results;
+// annotators reached after ctx is done return a status.Cancelled result.
+func (sdk *instance) MutateContext(ctx context.Context, oldData, newData []byte) []*status.Contract {
 	if sdk.closed {
 		return nil
 	}
 
-	result := make([]*status.Contract, 0)
-	for i := range sdk.annotators {
-		result = append(result, sdk.annotators[i].Mutate(oldData, newData))
-	}
-	return result
+	return sdk.evaluate(
+		func(a annotator.ContextContract) (*status.Contract, bool) {
+			return a.MutateContext(ctx, oldData, newData), true
+		},
+	)
 }
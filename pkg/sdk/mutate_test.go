@@ -15,6 +15,7 @@
 package sdk
 
 import (
+	"context"
 	"crypto"
 	"testing"
 
@@ -304,3 +305,55 @@ func TestInstance_Mutate(t *testing.T) {
 		)
 	}
 }
+
+// TestInstance_MutateContext tests instance.MutateContext.
+func TestInstance_MutateContext(t *testing.T) {
+	oldData, newData := test.FactoryRandomByteSlice(), test.FactoryRandomByteSlice()
+
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "Nil after close (stub)",
+			test: func(t *testing.T) {
+				sut := newSUT([]annotator.Contract{stub.New()})
+				sut.Close()
+
+				result := sut.MutateContext(context.Background(), oldData, newData)
+
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "Completed (stub)",
+			test: func(t *testing.T) {
+				result := status.New(test.FactoryRandomString(), status.Success)
+				sut := newSUT([]annotator.Contract{stub.NewWithResult(result)})
+				defer sut.Close()
+
+				assert.Equal(t, []*status.Contract{result}, sut.MutateContext(context.Background(), oldData, newData))
+			},
+		},
+		{
+			name: "Done beforehand (stub)",
+			test: func(t *testing.T) {
+				a := stub.NewWithResult(status.New(test.FactoryRandomString(), status.Success))
+				sut := newSUT([]annotator.Contract{a})
+				defer sut.Close()
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				result := sut.MutateContext(ctx, oldData, newData)
+
+				assert.Equal(t, []*status.Contract{status.New(nil, status.Cancelled)}, result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
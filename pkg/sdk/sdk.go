@@ -15,24 +15,86 @@
 package sdk
 
 import (
+	"sync"
+
 	"github.com/project-alvarium/go-sdk/pkg/annotator"
+	"github.com/project-alvarium/go-sdk/pkg/status"
 )
 
 // instance is a receiver that encapsulates required dependencies.
 type instance struct {
-	annotators []annotator.Contract
-	closed     bool
+	stages [][]annotator.ContextContract
+	closed bool
 }
 
-// New is a factory function that returns an initialized sdk.
+// New is a factory function that returns an initialized sdk whose annotators are evaluated sequentially in the
+// order given.
+//
+// Annotators that do not implement annotator.ContextContract are adapted by annotator.WithContext so that every
+// annotator's evaluation is bounded by the context passed to the sdk's context-aware methods.
 func New(annotators []annotator.Contract) *instance {
+	stages := make([][]annotator.Contract, len(annotators))
+	for i := range annotators {
+		stages[i] = []annotator.Contract{annotators[i]}
+	}
+	return NewWithStages(stages)
+}
+
+// NewWithStages is a factory function that returns an initialized sdk whose annotators are grouped into stages.
+//
+// Stages are evaluated sequentially in the order given; annotators within a stage are independent of one another and
+// are evaluated concurrently, so each must be safe to run alongside the others in its stage (e.g. publishers writing
+// to different destinations).  An annotator that depends on another's annotation (e.g. an assess annotator that
+// verifies a pki annotator's signature) belongs in a later stage.  Annotators within a stage must not share a signer:
+// a pki annotator records its signer's metadata after signing, which a concurrent signature by another annotator
+// sharing the signer may change.  Status results are always returned in stage order and, within a stage, in the
+// order the annotators are listed regardless of which finishes first.
+//
+// Annotators are set up in the order they are listed and adapted as described for New.
+func NewWithStages(stages [][]annotator.Contract) *instance {
 	return &instance{
-		annotators: func() []annotator.Contract {
-			for i := range annotators {
-				annotators[i].SetUp()
+		stages: func() [][]annotator.ContextContract {
+			result := make([][]annotator.ContextContract, len(stages))
+			for i := range stages {
+				result[i] = make([]annotator.ContextContract, len(stages[i]))
+				for j := range stages[i] {
+					stages[i][j].SetUp()
+					result[i][j] = annotator.WithContext(stages[i][j])
+				}
 			}
-			return annotators
+			return result
 		}(),
 		closed: false,
 	}
 }
+
+// evaluate calls f for each annotator stage by stage, concurrently within a stage, and returns f's status results in
+// stage and listing order; annotators for which f returns false are omitted from the result.
+func (sdk *instance) evaluate(f func(a annotator.ContextContract) (*status.Contract, bool)) []*status.Contract {
+	result := make([]*status.Contract, 0)
+	for i := range sdk.stages {
+		stage := sdk.stages[i]
+		results := make([]*status.Contract, len(stage))
+		included := make([]bool, len(stage))
+		if len(stage) == 1 {
+			results[0], included[0] = f(stage[0])
+		} else {
+			var wg sync.WaitGroup
+			wg.Add(len(stage))
+			for j := range stage {
+				go func(j int) {
+					defer wg.Done()
+					results[j], included[j] = f(stage[j])
+				}(j)
+			}
+			wg.Wait()
+		}
+
+		for j := range results {
+			if included[j] {
+				result = append(result, results[j])
+			}
+		}
+	}
+	return result
+}
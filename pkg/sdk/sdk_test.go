@@ -15,23 +15,36 @@
 package sdk
 
 import (
+	"context"
+	"crypto"
+	"sync"
 	"testing"
+	"time"
 
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
 	metadataStub "github.com/project-alvarium/go-sdk/pkg/annotation/metadata/stub"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
 	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider/ulid"
 	"github.com/project-alvarium/go-sdk/pkg/annotator"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/filter/passthrough"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/pki"
+	pkiMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/fail"
+	failMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/fail/metadata"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15"
+	signerMetadata "github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/signpkcs1v15/metadata"
 	"github.com/project-alvarium/go-sdk/pkg/annotator/publish"
 	publisherStub "github.com/project-alvarium/go-sdk/pkg/annotator/publish/publisher/stub"
 	annotatorStub "github.com/project-alvarium/go-sdk/pkg/annotator/stub"
 	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity/principal"
 	identityProvider "github.com/project-alvarium/go-sdk/pkg/identityprovider/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
 	"github.com/project-alvarium/go-sdk/pkg/test"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // newSUT returns a new system under test.
@@ -98,3 +111,221 @@ func TestInstance_SetUpCalled(t *testing.T) {
 		t.Run(cases[i].name, cases[i].test)
 	}
 }
+
+// events is a goroutine-safe record of the annotators that have completed an evaluation.
+type events struct {
+	m     sync.Mutex
+	names []string
+}
+
+// record adds name to the record.
+func (e *events) record(name string) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.names = append(e.names, name)
+}
+
+// snapshot returns a copy of the record.
+func (e *events) snapshot() []string {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	return append([]string{}, e.names...)
+}
+
+// recording is an annotator whose Create notes the events recorded before it began, waits for wait to be closed (if
+// set), records its own completion, and then closes done (if set).
+type recording struct {
+	annotator.Contract
+	name   string
+	events *events
+	wait   chan struct{}
+	done   chan struct{}
+	seen   []string
+}
+
+// newRecording returns an initialized recording annotator that returns result.
+func newRecording(name string, e *events, result *status.Contract) *recording {
+	return &recording{
+		Contract: annotatorStub.NewWithResult(result),
+		name:     name,
+		events:   e,
+	}
+}
+
+// Create evaluates newly-created data.
+func (r *recording) Create(data []byte) *status.Contract {
+	r.seen = r.events.snapshot()
+	if r.wait != nil {
+		<-r.wait
+	}
+	r.events.record(r.name)
+	if r.done != nil {
+		close(r.done)
+	}
+	return r.Contract.Create(data)
+}
+
+// TestNewWithStages tests NewWithStages.
+func TestNewWithStages(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "SetUp and TearDown called once per annotator",
+			test: func(t *testing.T) {
+				a, b, c := annotatorStub.New(), annotatorStub.New(), annotatorStub.New()
+				sut := NewWithStages([][]annotator.Contract{{a, b}, {c}})
+				sut.Close()
+				sut.Close()
+
+				assert.Equal(t, []int{1, 1, 1}, []int{a.SetUpCalled, b.SetUpCalled, c.SetUpCalled})
+				assert.Equal(t, []int{1, 1, 1}, []int{a.TearDownCalled, b.TearDownCalled, c.TearDownCalled})
+			},
+		},
+		{
+			name: "annotators within a stage are evaluated concurrently and results returned in listing order",
+			test: func(t *testing.T) {
+				e := &events{}
+				firstResult := status.New(test.FactoryRandomString(), status.Success)
+				secondResult := status.New(test.FactoryRandomString(), status.PublisherError)
+				first := newRecording("first", e, firstResult)
+				second := newRecording("second", e, secondResult)
+				first.wait = make(chan struct{})
+				second.done = first.wait
+				sut := NewWithStages([][]annotator.Contract{{first, second}})
+				defer sut.Close()
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				result := sut.CreateContext(ctx, test.FactoryRandomByteSlice())
+
+				assert.Equal(t, []*status.Contract{firstResult, secondResult}, result)
+				assert.Equal(t, []string{"second", "first"}, e.snapshot())
+			},
+		},
+		{
+			name: "pki annotators with their own signers within a stage",
+			test: func(t *testing.T) {
+				h := sha256.New()
+				idProvider := identityProvider.New(h)
+				succeeding, failing := memory.New(), memory.New()
+				sut := NewWithStages(
+					[][]annotator.Contract{
+						{
+							pki.New(
+								test.FactoryRandomString(),
+								ulid.New(),
+								idProvider,
+								succeeding,
+								signpkcs1v15.New(crypto.SHA256, testInternal.ValidPrivateKey, testInternal.ValidPublicKey, h),
+							),
+							pki.New(test.FactoryRandomString(), ulid.New(), idProvider, failing, fail.New()),
+						},
+					},
+				)
+				defer sut.Close()
+				data := test.FactoryRandomByteSlice()
+
+				result := sut.Create(data)
+
+				require.Len(t, result, 2)
+				assert.Equal(t, status.Success, result[0].Value)
+				assert.Equal(t, status.Success, result[1].Value)
+				for s, kind := range map[store.Contract]string{succeeding: signerMetadata.Kind, failing: failMetadata.Kind} {
+					annotations, _ := s.FindByIdentity(idProvider.Derive(data))
+					require.Len(t, annotations, 1)
+					assert.Equal(t, kind, annotations[0].Metadata.(*pkiMetadata.Instance).SignerKind)
+				}
+			},
+		},
+		{
+			name: "stages are evaluated in order",
+			test: func(t *testing.T) {
+				e := &events{}
+				first := newRecording("first", e, status.New(test.FactoryRandomString(), status.Success))
+				second := newRecording("second", e, status.New(test.FactoryRandomString(), status.Success))
+				third := newRecording("third", e, status.New(test.FactoryRandomString(), status.Success))
+				sut := NewWithStages([][]annotator.Contract{{first, second}, {third}})
+				defer sut.Close()
+
+				result := sut.Create(test.FactoryRandomByteSlice())
+
+				require.Len(t, result, 3)
+				assert.ElementsMatch(t, []string{"first", "second"}, third.seen)
+				assert.Equal(t, "third", e.snapshot()[2])
+			},
+		},
+		{
+			name: "Mutate and Derive results returned in listing order",
+			test: func(t *testing.T) {
+				results := []*status.Contract{
+					status.New(test.FactoryRandomString(), status.Success),
+					status.New(test.FactoryRandomString(), status.PublisherError),
+					status.New(test.FactoryRandomString(), status.Exists),
+				}
+				sut := NewWithStages(
+					[][]annotator.Contract{
+						{annotatorStub.NewWithResult(results[0]), annotatorStub.NewWithResult(results[1])},
+						{annotatorStub.NewWithResult(results[2])},
+					},
+				)
+				defer sut.Close()
+				data := test.FactoryRandomByteSlice()
+
+				assert.Equal(t, results, sut.Mutate(test.FactoryRandomByteSlice(), data))
+				assert.Equal(t, results, sut.Derive([][]byte{test.FactoryRandomByteSlice()}, data))
+			},
+		},
+		{
+			name: "Transfer omits annotators that are not transferers",
+			test: func(t *testing.T) {
+				e := &events{}
+				result := status.New(test.FactoryRandomString(), status.Success)
+				sut := NewWithStages(
+					[][]annotator.Contract{
+						{newRecording("first", e, nil), annotatorStub.NewWithResult(result)},
+						{newRecording("second", e, nil)},
+					},
+				)
+				defer sut.Close()
+
+				assert.Equal(
+					t,
+					[]*status.Contract{result},
+					sut.Transfer(test.FactoryRandomByteSlice(), principal.New(test.FactoryRandomString())),
+				)
+			},
+		},
+		{
+			name: "Deadline exceeded across stages (blocking stub)",
+			test: func(t *testing.T) {
+				b := &blocking{Contract: annotatorStub.New(), release: make(chan struct{})}
+				defer close(b.release)
+				sut := NewWithStages(
+					[][]annotator.Contract{
+						{b, annotatorStub.New()},
+						{annotatorStub.NewWithResult(status.New(test.FactoryRandomString(), status.Success))},
+					},
+				)
+				defer sut.Close()
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				result := sut.CreateContext(ctx, test.FactoryRandomByteSlice())
+
+				require.Len(t, result, 3)
+				assert.Equal(t, status.New(nil, status.Cancelled), result[0])
+				assert.Equal(t, status.New(nil, status.Cancelled), result[2])
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
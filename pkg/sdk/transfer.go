@@ -0,0 +1,48 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package sdk
+
+import (
+	"context"
+
+	"github.com/project-alvarium/go-sdk/pkg/annotator"
+	"github.com/project-alvarium/go-sdk/pkg/identity"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+)
+
+// Transfer calls the Transfer method on each registered annotator that implements annotator.Transferer and returns
+// a set of status results.
+func (sdk *instance) Transfer(data []byte, owner identity.Contract) []*status.Contract {
+	return sdk.TransferContext(context.Background(), data, owner)
+}
+
+// TransferContext calls the TransferContext method on each registered annotator that implements
+// annotator.Transferer and returns a set of status results; annotators reached after ctx is done return a
+// status.Cancelled result.
+func (sdk *instance) TransferContext(ctx context.Context, data []byte, owner identity.Contract) []*status.Contract {
+	if sdk.closed {
+		return nil
+	}
+
+	return sdk.evaluate(
+		func(a annotator.ContextContract) (*status.Contract, bool) {
+			t, ok := a.(annotator.ContextTransferer)
+			if !ok {
+				return nil, false
+			}
+			return t.TransferContext(ctx, data, owner), true
+		},
+	)
+}
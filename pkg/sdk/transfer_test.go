@@ -0,0 +1,167 @@
+/*******************************************************************************
+ * Copyright 2020 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	testInternal "github.com/project-alvarium/go-sdk/internal/pkg/test"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/store/memory"
+	"github.com/project-alvarium/go-sdk/pkg/annotation/uniqueprovider/ulid"
+	"github.com/project-alvarium/go-sdk/pkg/annotator"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/ownership"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/pki/signer/fail"
+	"github.com/project-alvarium/go-sdk/pkg/annotator/stub"
+	"github.com/project-alvarium/go-sdk/pkg/hashprovider/sha256"
+	"github.com/project-alvarium/go-sdk/pkg/identity/principal"
+	identityProvider "github.com/project-alvarium/go-sdk/pkg/identityprovider/hash"
+	"github.com/project-alvarium/go-sdk/pkg/status"
+	"github.com/project-alvarium/go-sdk/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInstance_Transfer tests instance.Transfer.
+func TestInstance_Transfer(t *testing.T) {
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "Nil after close (stub)",
+			test: func(t *testing.T) {
+				sut := newSUT([]annotator.Contract{stub.NewWithResult(status.New(test.FactoryRandomString(), status.Success))})
+				sut.Close()
+
+				result := sut.Transfer(test.FactoryRandomByteSlice(), principal.New(test.FactoryRandomString()))
+
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "Skips annotators that do not implement Transferer",
+			test: func(t *testing.T) {
+				prov := test.FactoryRandomString()
+				sut := newSUT(
+					[]annotator.Contract{
+						pki.New(
+							test.FactoryRandomString(),
+							ulid.New(),
+							identityProvider.New(sha256.New()),
+							memory.New(),
+							fail.New(),
+						),
+						stub.NewWithResult(status.New(prov, status.Success)),
+					},
+				)
+
+				result := sut.Transfer(test.FactoryRandomByteSlice(), principal.New(test.FactoryRandomString()))
+
+				assert.Equal(
+					t,
+					testInternal.Marshal(t, []*status.Contract{status.New(prov, status.Success)}),
+					testInternal.Marshal(t, result),
+				)
+				sut.Close()
+			},
+		},
+		{
+			name: "Success (ownership)",
+			test: func(t *testing.T) {
+				prov := test.FactoryRandomString()
+				persistence := memory.New()
+				idProvider := identityProvider.New(sha256.New())
+				owner, transferee := principal.New(test.FactoryRandomString()), principal.New(test.FactoryRandomString())
+				sut := newSUT(
+					[]annotator.Contract{ownership.New(prov, ulid.New(), idProvider, persistence, owner, owner)},
+				)
+				data := test.FactoryRandomByteSlice()
+				sut.Create(data)
+
+				result := sut.Transfer(data, transferee)
+
+				assert.Equal(
+					t,
+					testInternal.Marshal(t, []*status.Contract{status.New(prov, status.Success)}),
+					testInternal.Marshal(t, result),
+				)
+				annotations, _ := persistence.FindByIdentity(idProvider.Derive(data))
+				current, _ := ownership.Current(annotations)
+				assert.Equal(t, transferee, current)
+				sut.Close()
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
+
+// TestInstance_TransferContext tests instance.TransferContext.
+func TestInstance_TransferContext(t *testing.T) {
+	data, owner := test.FactoryRandomByteSlice(), principal.New(test.FactoryRandomString())
+
+	type testCase struct {
+		name string
+		test func(t *testing.T)
+	}
+
+	cases := []testCase{
+		{
+			name: "Nil after close (stub)",
+			test: func(t *testing.T) {
+				sut := newSUT([]annotator.Contract{stub.New()})
+				sut.Close()
+
+				result := sut.TransferContext(context.Background(), data, owner)
+
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "Completed (stub)",
+			test: func(t *testing.T) {
+				result := status.New(test.FactoryRandomString(), status.Success)
+				sut := newSUT([]annotator.Contract{stub.NewWithResult(result)})
+				defer sut.Close()
+
+				assert.Equal(t, []*status.Contract{result}, sut.TransferContext(context.Background(), data, owner))
+			},
+		},
+		{
+			name: "Done beforehand (stub)",
+			test: func(t *testing.T) {
+				a := stub.NewWithResult(status.New(test.FactoryRandomString(), status.Success))
+				sut := newSUT([]annotator.Contract{a})
+				defer sut.Close()
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				result := sut.TransferContext(ctx, data, owner)
+
+				assert.Equal(t, []*status.Contract{status.New(nil, status.Cancelled)}, result)
+			},
+		},
+	}
+
+	for i := range cases {
+		t.Run(cases[i].name, cases[i].test)
+	}
+}
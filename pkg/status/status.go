@@ -22,6 +22,8 @@ const (
 	NotFound
 	Exists
 	Unknown
+	Cancelled
+	Conflict
 )
 
 // New is a factory function that returns an initialized Contract.